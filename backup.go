@@ -0,0 +1,133 @@
+package genji
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+)
+
+// Backup writes a consistent copy of the database to dir, which must not exist.
+// The database remains readable and writable during the backup: the copy contains
+// every transaction committed before the call and none of those committed after.
+// Whenever possible, the files of the database are hard linked rather than copied,
+// which makes the backup fast and cheap, even for large databases.
+// The backup is written using the file system of the database and is itself a database
+// that can be opened with Open, or restored with Restore.
+func (db *DB) Backup(ctx context.Context, dir string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	// pebble fails to sync the parent of relative paths
+	// such as "backup", which have no parent directory.
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	// flush the write-ahead log to include the transactions
+	// that were committed without syncing
+	return db.pdb.Checkpoint(dir, pebble.WithFlushedWAL())
+}
+
+// CheckIntegrity ensures the catalog of the database is consistent.
+// It returns an error describing the first inconsistency found, if any.
+func (db *DB) CheckIntegrity(ctx context.Context) error {
+	tx, err := db.WithContext(ctx).Begin(false)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	return db.DB.Catalog.CheckIntegrity()
+}
+
+// Restore copies the backup found in backupDir to path, which must not exist.
+// The backup is opened in read-only mode and the integrity of its catalog
+// is checked before being copied.
+func Restore(ctx context.Context, backupDir, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("cannot restore to %q: file already exists", path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	db, err := OpenWith(backupDir, &Options{ReadOnly: true})
+	if err != nil {
+		return errors.Wrapf(err, "cannot open backup %q", backupDir)
+	}
+
+	err = db.CheckIntegrity(ctx)
+	if cerr := db.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "invalid backup %q", backupDir)
+	}
+
+	err = copyDir(ctx, backupDir, path)
+	if err != nil {
+		_ = os.RemoveAll(path)
+		return err
+	}
+
+	return nil
+}
+
+// copyDir copies the content of the src directory to dst.
+func copyDir(ctx context.Context, src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		return copyFile(p, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
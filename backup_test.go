@@ -0,0 +1,77 @@
+package genji_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/internal/testutil"
+	"github.com/genjidb/genji/internal/testutil/assert"
+)
+
+func TestBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := testutil.TempDir(t)
+
+	db, err := genji.OpenWith(filepath.Join(dir, "db"), &genji.Options{NoSync: true})
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE test (a INT PRIMARY KEY, b TEXT UNIQUE);
+		CREATE SEQUENCE seq;
+		INSERT INTO test (a, b) VALUES (1, 'a'), (2, 'b');
+	`)
+	assert.NoError(t, err)
+
+	// the backup can run during a write transaction
+	// and doesn't contain its uncommitted writes
+	tx, err := db.Begin(true)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+	err = tx.Exec("INSERT INTO test (a, b) VALUES (3, 'c')")
+	assert.NoError(t, err)
+
+	err = db.Backup(ctx, filepath.Join(dir, "backup"))
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	// the database is still writable
+	err = db.Exec("INSERT INTO test (a, b) VALUES (4, 'd')")
+	assert.NoError(t, err)
+
+	// the backup directory must not exist
+	err = db.Backup(ctx, filepath.Join(dir, "backup"))
+	assert.Error(t, err)
+
+	err = genji.Restore(ctx, filepath.Join(dir, "backup"), filepath.Join(dir, "restored"))
+	assert.NoError(t, err)
+
+	// the target must not exist
+	err = genji.Restore(ctx, filepath.Join(dir, "backup"), filepath.Join(dir, "restored"))
+	assert.Error(t, err)
+
+	restored, err := genji.Open(filepath.Join(dir, "restored"))
+	assert.NoError(t, err)
+	defer restored.Close()
+
+	assert.NoError(t, restored.CheckIntegrity(ctx))
+
+	d, err := restored.QueryDocument("SELECT COUNT(*) AS n FROM test")
+	assert.NoError(t, err)
+	testutil.RequireDocJSONEq(t, d, `{"n": 2}`)
+
+	err = restored.Exec("INSERT INTO test (a, b) VALUES (3, 'a')")
+	assert.Error(t, err)
+	err = restored.Exec("INSERT INTO test (a, b) VALUES (3, 'c')")
+	assert.NoError(t, err)
+
+	d, err = restored.QueryDocument("SELECT NEXT VALUE FOR seq AS n")
+	assert.NoError(t, err)
+	testutil.RequireDocJSONEq(t, d, `{"n": 1}`)
+
+	// a database that is not a backup cannot be restored
+	err = genji.Restore(ctx, filepath.Join(dir, "unknown"), filepath.Join(dir, "other"))
+	assert.Error(t, err)
+}
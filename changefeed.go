@@ -0,0 +1,161 @@
+package genji
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/types"
+)
+
+// ChangeType describes how a document was modified.
+type ChangeType uint8
+
+// Change types
+const (
+	ChangeInsert = ChangeType(database.ChangeInsert)
+	ChangeUpdate = ChangeType(database.ChangeUpdate)
+	ChangeDelete = ChangeType(database.ChangeDelete)
+)
+
+func (t ChangeType) String() string {
+	return database.ChangeType(t).String()
+}
+
+// A Change describes the modification of a document by a committed transaction.
+type Change struct {
+	// Commit sequence of the transaction that modified the document.
+	// All the changes of a transaction share the same sequence, which
+	// increases with every transaction that modifies at least one document.
+	Seq int64
+	// Name of the modified table.
+	Table string
+	Type  ChangeType
+	// Primary key of the document, or its docid if the table
+	// doesn't have a primary key.
+	Key []types.Value
+	// Document before the change, nil for insertions.
+	Before types.Document
+	// Document after the change, nil for deletions.
+	After types.Document
+	// Field-level modifications made by an update, nil for insertions and deletions.
+	Ops []document.Op
+}
+
+// A Subscription receives the changes made to a set of tables,
+// in the order in which the transactions were committed.
+// It is not safe for concurrent use.
+type Subscription struct {
+	ctx context.Context
+	db  *DB
+	sub *database.Subscriber
+}
+
+// Subscribe returns a subscription to the changes made to the given tables by the transactions
+// committed from now on. If no table is given, the subscription receives the changes made to all the tables.
+// The subscription is closed once ctx is canceled.
+// Changes are buffered in memory until they are read: slow subscribers don't block writers.
+// Subscriptions that don't keep up are closed, and Next returns errors.ErrSubscriberLagged.
+func (db *DB) Subscribe(ctx context.Context, tables ...string) (*Subscription, error) {
+	sub, err := db.DB.Subscribe(tables...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSubscription(ctx, db, sub), nil
+}
+
+// SubscribeFrom returns a subscription to the durable subscription called name.
+// Durable subscriptions persist their position: the changes committed after the last position
+// acknowledged with Ack are delivered first, even if the database was closed in the meantime,
+// followed by the new ones. If the subscription doesn't exist, it is created and receives the changes
+// made by the transactions committed from now on.
+// While durable subscriptions exist, every change is written to a log, which is pruned as they are acknowledged.
+// Durable subscriptions that are not needed anymore must be deleted with DropSubscription.
+func (db *DB) SubscribeFrom(ctx context.Context, name string, tables ...string) (*Subscription, error) {
+	sub, err := db.DB.SubscribeFrom(name, tables...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSubscription(ctx, db, sub), nil
+}
+
+// DropSubscription deletes the durable subscription called name.
+func (db *DB) DropSubscription(name string) error {
+	return db.DB.DropSubscription(name)
+}
+
+func newSubscription(ctx context.Context, db *DB, sub *database.Subscriber) *Subscription {
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.Close()
+		case <-sub.Done():
+		}
+	}()
+
+	return &Subscription{
+		ctx: ctx,
+		db:  db,
+		sub: sub,
+	}
+}
+
+// Next returns the next change, waiting for a transaction to commit if necessary.
+// It returns the error of the context once it is canceled,
+// or errors.ErrSubscriptionClosed once the subscription is closed.
+// If the subscription was closed because too many changes were pending, it returns
+// errors.ErrSubscriberLagged: durable subscriptions can then resume with SubscribeFrom.
+// Once the database is closed, it returns errors.ErrDatabaseClosed.
+func (s *Subscription) Next() (*Change, error) {
+	c, err := s.sub.Next()
+	if err != nil {
+		if s.ctx.Err() != nil {
+			return nil, s.ctx.Err()
+		}
+		return nil, err
+	}
+
+	key, err := c.Key.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []document.Op
+	if c.Type == database.ChangeUpdate {
+		ops, err = document.Diff(c.Before, c.After)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Change{
+		Seq:    c.Seq,
+		Table:  c.Table,
+		Type:   ChangeType(c.Type),
+		Key:    key,
+		Before: c.Before,
+		After:  c.After,
+		Ops:    ops,
+	}, nil
+}
+
+// Ack acknowledges every change up to the given sequence. Acknowledged changes
+// are not delivered again when the durable subscription is resumed with SubscribeFrom.
+// It returns an error if the subscription is not durable.
+func (s *Subscription) Ack(seq int64) error {
+	if s.sub.Name == "" {
+		return errors.New("cannot acknowledge changes of a subscription that is not durable")
+	}
+
+	return s.db.DB.SetSubscriptionPosition(s.sub.Name, seq)
+}
+
+// Close the subscription. The changes that were not read yet are discarded.
+// The position of a durable subscription is kept.
+func (s *Subscription) Close() error {
+	s.sub.Close()
+	return nil
+}
@@ -0,0 +1,267 @@
+package genji_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/document"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/internal/testutil"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/genjidb/genji/types"
+	"github.com/stretchr/testify/require"
+)
+
+func nextChange(t *testing.T, sub *genji.Subscription) *genji.Change {
+	t.Helper()
+
+	c, err := sub.Next()
+	assert.NoError(t, err)
+	return c
+}
+
+func TestSubscribe(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE foo (a INT PRIMARY KEY, b INT); CREATE TABLE bar")
+	assert.NoError(t, err)
+
+	// unknown tables are rejected
+	_, err = db.Subscribe(ctx, "baz")
+	assert.Error(t, err)
+
+	sub, err := db.Subscribe(ctx, "foo")
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	err = db.Exec(`
+		INSERT INTO foo (a, b) VALUES (1, 10), (2, 20);
+		INSERT INTO bar (a) VALUES (1);
+		UPDATE foo SET b = 11 WHERE a = 1;
+		DELETE FROM foo WHERE a = 2;
+	`)
+	assert.NoError(t, err)
+
+	// changes of rolled back transactions are not delivered
+	err = db.Update(func(tx *genji.Tx) error {
+		err := tx.Exec("INSERT INTO foo (a, b) VALUES (3, 30)")
+		assert.NoError(t, err)
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+
+	err = db.Exec("INSERT INTO foo (a, b) VALUES (4, 40)")
+	assert.NoError(t, err)
+
+	c := nextChange(t, sub)
+	require.Equal(t, genji.ChangeInsert, c.Type)
+	require.Equal(t, "foo", c.Table)
+	require.Equal(t, []types.Value{types.NewIntegerValue(1)}, c.Key)
+	require.Nil(t, c.Before)
+	testutil.RequireDocJSONEq(t, c.After, `{"a": 1, "b": 10}`)
+	first := c.Seq
+
+	c = nextChange(t, sub)
+	require.Equal(t, genji.ChangeInsert, c.Type)
+	require.Equal(t, first, c.Seq)
+
+	c = nextChange(t, sub)
+	require.Equal(t, genji.ChangeUpdate, c.Type)
+	require.Greater(t, c.Seq, first)
+	testutil.RequireDocJSONEq(t, c.Before, `{"a": 1, "b": 10}`)
+	testutil.RequireDocJSONEq(t, c.After, `{"a": 1, "b": 11}`)
+	require.Len(t, c.Ops, 1)
+	require.Equal(t, "set", c.Ops[0].Type)
+	require.Equal(t, document.NewPath("b"), c.Ops[0].Path)
+	require.Equal(t, int64(11), c.Ops[0].Value.V())
+
+	c = nextChange(t, sub)
+	require.Equal(t, genji.ChangeDelete, c.Type)
+	require.Equal(t, []types.Value{types.NewIntegerValue(2)}, c.Key)
+	testutil.RequireDocJSONEq(t, c.Before, `{"a": 2, "b": 20}`)
+	require.Nil(t, c.After)
+
+	c = nextChange(t, sub)
+	require.Equal(t, genji.ChangeInsert, c.Type)
+	require.Equal(t, []types.Value{types.NewIntegerValue(4)}, c.Key)
+
+	assert.NoError(t, sub.Close())
+	_, err = sub.Next()
+	require.True(t, errors.Is(err, errs.ErrSubscriptionClosed))
+
+	// durable subscriptions only
+	require.Error(t, sub.Ack(c.Seq))
+}
+
+func TestSubscribeContext(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE foo")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := db.Subscribe(ctx)
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err = sub.Next()
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestSubscribeDatabaseClosed(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+
+	err = db.Exec("CREATE TABLE foo")
+	assert.NoError(t, err)
+
+	sub, err := db.Subscribe(context.Background())
+	assert.NoError(t, err)
+	durable, err := db.SubscribeFrom(context.Background(), "cache")
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		db.Close()
+	}()
+
+	// subscribers waiting for changes return once the database is closed
+	_, err = sub.Next()
+	require.True(t, errors.Is(err, errs.ErrDatabaseClosed))
+	_, err = durable.Next()
+	require.True(t, errors.Is(err, errs.ErrDatabaseClosed))
+}
+
+func TestSubscribeLagged(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	db.DB.ChangeFeed.MaxPending = 2
+
+	err = db.Exec("CREATE TABLE foo (a INT PRIMARY KEY)")
+	assert.NoError(t, err)
+
+	sub, err := db.Subscribe(ctx, "foo")
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	durable, err := db.SubscribeFrom(ctx, "cache", "foo")
+	assert.NoError(t, err)
+
+	for i := 1; i <= 3; i++ {
+		err = db.Exec("INSERT INTO foo (a) VALUES (?)", i)
+		assert.NoError(t, err)
+	}
+
+	// subscribers that don't keep up are closed
+	_, err = sub.Next()
+	require.True(t, errors.Is(err, errs.ErrSubscriberLagged))
+	_, err = durable.Next()
+	require.True(t, errors.Is(err, errs.ErrSubscriberLagged))
+	assert.NoError(t, durable.Close())
+
+	// durable subscriptions resume from their position,
+	// the changes read from the log don't count towards the limit
+	durable, err = db.SubscribeFrom(ctx, "cache", "foo")
+	assert.NoError(t, err)
+	defer durable.Close()
+
+	err = db.Exec("INSERT INTO foo (a) VALUES (4)")
+	assert.NoError(t, err)
+
+	for i := 1; i <= 4; i++ {
+		c := nextChange(t, durable)
+		require.Equal(t, []types.Value{types.NewIntegerValue(int64(i))}, c.Key)
+	}
+}
+
+func TestSubscribeFrom(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(testutil.TempDir(t), "db")
+
+	db, err := genji.Open(path)
+	assert.NoError(t, err)
+
+	err = db.Exec("CREATE TABLE foo (a INT PRIMARY KEY)")
+	assert.NoError(t, err)
+
+	sub, err := db.SubscribeFrom(ctx, "cache", "foo")
+	assert.NoError(t, err)
+
+	// a durable subscription can only be used once at a time
+	_, err = db.SubscribeFrom(ctx, "cache")
+	assert.Error(t, err)
+
+	err = db.Exec("INSERT INTO foo (a) VALUES (1)")
+	assert.NoError(t, err)
+	err = db.Exec("INSERT INTO foo (a) VALUES (2)")
+	assert.NoError(t, err)
+
+	c := nextChange(t, sub)
+	require.Equal(t, []types.Value{types.NewIntegerValue(1)}, c.Key)
+	assert.NoError(t, sub.Ack(c.Seq))
+
+	// the position cannot go backward
+	assert.Error(t, sub.Ack(c.Seq-1))
+
+	assert.NoError(t, sub.Close())
+	assert.NoError(t, db.Close())
+
+	// changes committed after the last acknowledged one
+	// are delivered again after a restart
+	db, err = genji.Open(path)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("INSERT INTO foo (a) VALUES (3)")
+	assert.NoError(t, err)
+
+	sub, err = db.SubscribeFrom(ctx, "cache", "foo")
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	c = nextChange(t, sub)
+	require.Equal(t, []types.Value{types.NewIntegerValue(2)}, c.Key)
+	c = nextChange(t, sub)
+	require.Equal(t, []types.Value{types.NewIntegerValue(3)}, c.Key)
+	testutil.RequireDocJSONEq(t, c.After, `{"a": 3}`)
+
+	err = db.Exec("DELETE FROM foo WHERE a = 3")
+	assert.NoError(t, err)
+	c = nextChange(t, sub)
+	require.Equal(t, genji.ChangeDelete, c.Type)
+	assert.NoError(t, sub.Ack(c.Seq))
+
+	// acknowledged changes are removed from the log
+	d, err := db.QueryDocument("SELECT COUNT(*) AS n FROM __genji_changes")
+	assert.NoError(t, err)
+	testutil.RequireDocJSONEq(t, d, `{"n": 0}`)
+
+	assert.NoError(t, sub.Close())
+	assert.NoError(t, db.DropSubscription("cache"))
+	assert.Error(t, db.DropSubscription("cache"))
+
+	// changes are not logged anymore
+	err = db.Exec("INSERT INTO foo (a) VALUES (4)")
+	assert.NoError(t, err)
+	d, err = db.QueryDocument("SELECT COUNT(*) AS n FROM __genji_changes")
+	assert.NoError(t, err)
+	testutil.RequireDocJSONEq(t, d, `{"n": 0}`)
+}
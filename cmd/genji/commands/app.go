@@ -20,7 +20,9 @@ func NewApp() *cli.App {
 		NewVersionCommand(),
 		NewDumpCommand(),
 		NewRestoreCommand(),
+		NewBackupCommand(),
 		NewBenchCommand(),
+		NewMigrateCommand(),
 	}
 
 	// Root command
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/cmd/genji/dbutil"
+	"github.com/urfave/cli/v2"
+)
+
+// NewBackupCommand returns a cli.Command for "genji backup".
+func NewBackupCommand() (cmd *cli.Command) {
+	return &cli.Command{
+		Name:      "backup",
+		Usage:     "Create a consistent copy of a database",
+		UsageText: `genji backup dbPath backupDir`,
+		Description: `The backup command copies the files of a database to a new directory.
+Unlike genji dump, the backup is a database that can be opened directly,
+or restored with genji restore:
+
+	$ genji backup mydb backups/mydb-20211102
+	$ genji restore backups/mydb-20211102 restored`,
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 2 {
+				return errors.New(cmd.UsageText)
+			}
+
+			db, err := dbutil.OpenDB(c.Context, c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			return db.Backup(c.Context, c.Args().Get(1))
+		},
+	}
+}
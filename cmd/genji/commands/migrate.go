@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/cmd/genji/dbutil"
+	"github.com/genjidb/genji/migrate"
+	"github.com/urfave/cli/v2"
+)
+
+// NewMigrateCommand returns a cli.Command for "genji migrate".
+func NewMigrateCommand() *cli.Command {
+	dirFlag := &cli.StringFlag{
+		Name:    "dir",
+		Aliases: []string{"d"},
+		Usage:   "directory of the migration files.",
+		Value:   "migrations",
+	}
+
+	stepsFlag := &cli.IntFlag{
+		Name:    "steps",
+		Aliases: []string{"n"},
+		Usage:   "maximum number of migrations to run. 0 means all of them.",
+	}
+
+	return &cli.Command{
+		Name:      "migrate",
+		Usage:     "Apply or revert versioned SQL migrations",
+		UsageText: `genji migrate [up|down|status] [options] dbpath`,
+		Description: `The migrate command applies the SQL migration files of a directory to a database.
+
+Migration files are named <version>_<name>.up.sql, and optionally <version>_<name>.down.sql,
+and are applied in the order of their versions, each one in its own transaction:
+
+$ genji migrate up -d migrations my.db
+applied 1_create_users
+applied 2_add_email_index
+
+Applied migrations can be reverted, the most recent first. By default, only the last one is reverted:
+
+$ genji migrate down -d migrations my.db
+reverted 2_add_email_index
+
+The status of every migration can be listed:
+
+$ genji migrate status -d migrations my.db
+1_create_users     applied at 2021-11-02T10:00:00Z
+2_add_email_index  pending
+
+Migrations are not run if the applied migrations don't match the migration files.`,
+		Subcommands: []*cli.Command{
+			{
+				Name:      "up",
+				Usage:     "Apply the pending migrations",
+				UsageText: `genji migrate up [options] dbpath`,
+				Flags:     []cli.Flag{dirFlag, stepsFlag},
+				Action: func(c *cli.Context) error {
+					return runMigrate(c, func(m *migrate.Migrator) error {
+						done, err := m.Up(c.Context, c.Int("steps"))
+						for _, mig := range done {
+							fmt.Fprintf(c.App.Writer, "applied %s\n", &mig)
+						}
+						return err
+					})
+				},
+			},
+			{
+				Name:      "down",
+				Usage:     "Revert the last applied migrations",
+				UsageText: `genji migrate down [options] dbpath`,
+				Flags: []cli.Flag{dirFlag, &cli.IntFlag{
+					Name:    "steps",
+					Aliases: []string{"n"},
+					Usage:   "maximum number of migrations to revert. 0 means all of them.",
+					Value:   1,
+				}},
+				Action: func(c *cli.Context) error {
+					return runMigrate(c, func(m *migrate.Migrator) error {
+						done, err := m.Down(c.Context, c.Int("steps"))
+						for _, mig := range done {
+							fmt.Fprintf(c.App.Writer, "reverted %s\n", &mig)
+						}
+						return err
+					})
+				},
+			},
+			{
+				Name:      "status",
+				Usage:     "List the migrations and whether they were applied",
+				UsageText: `genji migrate status [options] dbpath`,
+				Flags:     []cli.Flag{dirFlag},
+				Action: func(c *cli.Context) error {
+					return runMigrate(c, func(m *migrate.Migrator) error {
+						status, err := m.Status(c.Context)
+						if err != nil {
+							return err
+						}
+
+						var width int
+						for _, s := range status {
+							if l := len(s.Migration.String()); l > width {
+								width = l
+							}
+						}
+
+						for _, s := range status {
+							state := "pending"
+							if s.Applied {
+								state = "applied at " + s.AppliedAt.Format(time.RFC3339)
+							}
+							fmt.Fprintf(c.App.Writer, "%-*s  %s\n", width, &s.Migration, state)
+						}
+						return nil
+					})
+				},
+			},
+		},
+	}
+}
+
+// runMigrate loads the migration files, opens the database and calls fn with a migrator.
+func runMigrate(c *cli.Context, fn func(m *migrate.Migrator) error) error {
+	dbPath := c.Args().First()
+	if dbPath == "" {
+		return errors.New(c.Command.UsageText)
+	}
+
+	migrations, err := migrate.LoadDir(c.String("dir"))
+	if err != nil {
+		return err
+	}
+
+	db, err := dbutil.OpenDB(c.Context, dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m, err := migrate.New(db, migrations)
+	if err != nil {
+		return err
+	}
+
+	return fn(m)
+}
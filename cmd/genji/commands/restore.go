@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji"
 	"github.com/genjidb/genji/cmd/genji/dbutil"
 	"github.com/urfave/cli/v2"
 )
@@ -12,11 +13,15 @@ import (
 func NewRestoreCommand() (cmd *cli.Command) {
 	return &cli.Command{
 		Name:      "restore",
-		Usage:     "Restore a database from a file created by genji dump",
-		UsageText: `genji restore dumpFile dbPath`,
+		Usage:     "Restore a database from a file created by genji dump or a directory created by genji backup",
+		UsageText: `genji restore dumpFile|backupDir dbPath`,
 		Description: `The restore command can restore a database from a text file.
 
-	$ genji restore dump.sql mydb`,
+	$ genji restore dump.sql mydb
+
+It can also restore a backup directory, after checking its integrity. In that case, mydb must not exist.
+
+	$ genji restore backups/mydb-20211102 mydb`,
 		Flags: []cli.Flag{},
 		Action: func(c *cli.Context) error {
 			if c.Args().Len() != 2 {
@@ -38,6 +43,14 @@ func NewRestoreCommand() (cmd *cli.Command) {
 			}
 			defer file.Close()
 
+			fi, err := file.Stat()
+			if err != nil {
+				return err
+			}
+			if fi.IsDir() {
+				return genji.Restore(c.Context, f, dbPath)
+			}
+
 			db, err := dbutil.OpenDB(c.Context, dbPath)
 			if err != nil {
 				return err
@@ -2,11 +2,17 @@ package dbutil
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
 	"github.com/genjidb/genji"
 	"github.com/genjidb/genji/document"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/internal/query/statement"
+	"github.com/genjidb/genji/internal/sql/parser"
+	"github.com/genjidb/genji/internal/stringutil"
+	"github.com/genjidb/genji/migrate"
 	"github.com/genjidb/genji/types"
 	"go.uber.org/multierr"
 )
@@ -36,6 +42,19 @@ func Dump(ctx context.Context, db *genji.DB, w io.Writer, tables ...string) erro
 
 		return dumpTable(tx, w, query, name)
 	})
+	if err == nil && (len(tables) == 0 || stringutil.Contains(tables, migrate.TableName)) {
+		var n int
+		n, err = dumpMigrations(tx, w, i)
+		i += n
+	}
+	if err == nil {
+		// triggers are dumped last so that they are not fired by the inserts.
+		var n int
+		n, err = dumpViews(tx, w, tables, i)
+		if err == nil {
+			err = dumpTriggers(tx, w, tables, i+n)
+		}
+	}
 	if err != nil {
 		_, er := fmt.Fprintln(w, "ROLLBACK;")
 		return multierr.Append(err, er)
@@ -52,6 +71,11 @@ func dumpTable(tx *genji.Tx, w io.Writer, query, tableName string) error {
 		return err
 	}
 
+	generated, err := generatedFields(query)
+	if err != nil {
+		return err
+	}
+
 	q := fmt.Sprintf("SELECT * FROM %s", tableName)
 	res, err := tx.Query(q)
 	if err != nil {
@@ -62,6 +86,23 @@ func dumpTable(tx *genji.Tx, w io.Writer, query, tableName string) error {
 	// Inserts statements.
 	insert := fmt.Sprintf("INSERT INTO %s VALUES", tableName)
 	return res.Iterate(func(d types.Document) error {
+		// generated fields are computed again when the documents are restored
+		if len(generated) > 0 {
+			fb := document.NewFieldBuffer()
+			err := fb.Copy(d)
+			if err != nil {
+				return err
+			}
+
+			for _, p := range generated {
+				err = fb.Delete(p)
+				if err != nil && !errors.Is(err, types.ErrFieldNotFound) {
+					return err
+				}
+			}
+			d = fb
+		}
+
 		data, err := document.MarshalJSON(d)
 		if err != nil {
 			return err
@@ -75,6 +116,56 @@ func dumpTable(tx *genji.Tx, w io.Writer, query, tableName string) error {
 	})
 }
 
+// dumpMigrations displays the table recording the migrations applied by the migrate package,
+// after the n tables already written. It is internal and not listed with the other tables,
+// but the history of the migrations must be restored along with the tables they created.
+// It returns the number of tables written.
+func dumpMigrations(tx *genji.Tx, w io.Writer, n int) (int, error) {
+	d, err := tx.QueryDocument("SELECT sql FROM __genji_catalog WHERE type = 'table' AND name = ?", migrate.TableName)
+	if errors.Is(err, errs.ErrDocumentNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var query string
+	err = document.Scan(d, &query)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 0 {
+		if _, err := fmt.Fprintln(w, ""); err != nil {
+			return 0, err
+		}
+	}
+
+	return 1, dumpTable(tx, w, query, migrate.TableName)
+}
+
+// generatedFields returns the paths of the generated fields declared by the CREATE TABLE statement.
+func generatedFields(query string) ([]document.Path, error) {
+	stmt, err := parser.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ct, ok := stmt.Statements[0].(*statement.CreateTableStmt)
+	if !ok {
+		return nil, fmt.Errorf("unexpected table definition %q", query)
+	}
+
+	var paths []document.Path
+	for _, fc := range ct.Info.FieldConstraints {
+		if fc.IsGenerated() {
+			paths = append(paths, fc.Path)
+		}
+	}
+
+	return paths, nil
+}
+
 // DumpSchema takes a database and dumps its schema as SQL queries in the given writer.
 // If tables are provided, only selected tables will be outputted.
 func DumpSchema(ctx context.Context, db *genji.DB, w io.Writer, tables ...string) error {
@@ -85,7 +176,7 @@ func DumpSchema(ctx context.Context, db *genji.DB, w io.Writer, tables ...string
 	defer tx.Rollback()
 
 	i := 0
-	return QueryTables(tx, tables, func(name, query string) error {
+	err = QueryTables(tx, tables, func(name, query string) error {
 		// Blank separation between tables.
 		if i > 0 {
 			if _, err := fmt.Fprintln(w, ""); err != nil {
@@ -96,6 +187,55 @@ func DumpSchema(ctx context.Context, db *genji.DB, w io.Writer, tables ...string
 
 		return dumpSchema(tx, w, query, name)
 	})
+	if err != nil {
+		return err
+	}
+
+	n, err := dumpViews(tx, w, tables, i)
+	if err != nil {
+		return err
+	}
+
+	return dumpTriggers(tx, w, tables, i+n)
+}
+
+// dumpViews displays the views as SQL statements, after the n tables already written.
+// Materialized views are populated when they are created, their content is not dumped.
+// It returns the number of views written.
+func dumpViews(tx *genji.Tx, w io.Writer, views []string, n int) (int, error) {
+	i := 0
+	err := QueryViews(tx, views, func(name, query string) error {
+		// Blank separation between tables and views.
+		if i == 0 && n > 0 {
+			if _, err := fmt.Fprintln(w, ""); err != nil {
+				return err
+			}
+		}
+		i++
+
+		_, err := fmt.Fprintf(w, "%s;\n", query)
+		return err
+	})
+
+	return i, err
+}
+
+// dumpTriggers displays the triggers of the given tables as SQL statements,
+// after the n tables and views already written.
+func dumpTriggers(tx *genji.Tx, w io.Writer, tables []string, n int) error {
+	i := 0
+	return QueryTriggers(tx, tables, func(name, query string) error {
+		// Blank separation between the other relations and triggers.
+		if i == 0 && n > 0 {
+			if _, err := fmt.Fprintln(w, ""); err != nil {
+				return err
+			}
+		}
+		i++
+
+		_, err := fmt.Fprintf(w, "%s;\n", query)
+		return err
+	})
 }
 
 // dumpSchema displays the schema of the given table as SQL statements.
@@ -5,9 +5,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"testing/fstest"
 
 	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/document"
 	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/genjidb/genji/migrate"
 	"github.com/stretchr/testify/require"
 )
 
@@ -84,6 +87,21 @@ func TestDump(t *testing.T) {
 				assert.NoError(t, err)
 				writeToBuf(q + "\n")
 			}
+
+			// views are dumped after the tables, materialized views last.
+			getBuffer("foo")("\n")
+			for _, view := range []string{"CREATE MATERIALIZED VIEW bar AS SELECT a FROM tblB;", "CREATE VIEW foo AS SELECT b, c FROM tblA WHERE a > 1;"} {
+				err = db.Exec(view)
+				assert.NoError(t, err)
+			}
+			getBuffer("foo")("CREATE VIEW foo AS SELECT b, c FROM tblA WHERE a > 1;\n")
+			getBuffer("bar")("CREATE MATERIALIZED VIEW bar AS SELECT a FROM tblB;\n")
+
+			// triggers are dumped after the data.
+			trigger := "CREATE TRIGGER tr AFTER INSERT ON tblA FOR EACH ROW BEGIN INSERT INTO tblB VALUES {a: NEW.a}; END;"
+			err = db.Exec(trigger)
+			assert.NoError(t, err)
+			getBuffer("tblA")("\n" + trigger + "\n")
 			want.WriteString("COMMIT;\n")
 
 			var got bytes.Buffer
@@ -148,6 +166,22 @@ func TestDumpSchema(t *testing.T) {
 				writeToBuf(q + "\n")
 			}
 
+			// views are dumped after the tables, materialized views last.
+			if want.Len() > 0 {
+				getBuffer("foo")("\n")
+			}
+			for _, view := range []string{"CREATE MATERIALIZED VIEW bar AS SELECT a FROM tblB;", "CREATE VIEW foo AS SELECT a FROM tblA;"} {
+				err = db.Exec(view)
+				assert.NoError(t, err)
+			}
+			getBuffer("foo")("CREATE VIEW foo AS SELECT a FROM tblA;\n")
+			getBuffer("bar")("CREATE MATERIALIZED VIEW bar AS SELECT a FROM tblB;\n")
+
+			trigger := "CREATE TRIGGER tr BEFORE DELETE ON tblB FOR EACH ROW WHEN OLD.a > 1 BEGIN DELETE FROM tblA WHERE a = OLD.a; END;"
+			err = db.Exec(trigger)
+			assert.NoError(t, err)
+			getBuffer("tblB")("\n" + trigger + "\n")
+
 			var got bytes.Buffer
 			err = DumpSchema(context.Background(), db, &got, tt.tables...)
 			assert.NoError(t, err)
@@ -156,3 +190,79 @@ func TestDumpSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestDumpMigrations(t *testing.T) {
+	migrations, err := migrate.Load(fstest.MapFS{
+		"1_init.up.sql": {Data: []byte("CREATE TABLE a (id INT PRIMARY KEY);")},
+	})
+	assert.NoError(t, err)
+
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	m, err := migrate.New(db, migrations)
+	assert.NoError(t, err)
+	_, err = m.Up(context.Background(), 0)
+	assert.NoError(t, err)
+
+	// the internal table recording the migrations is dumped with its content only
+	var schema bytes.Buffer
+	err = DumpSchema(context.Background(), db, &schema)
+	assert.NoError(t, err)
+	require.NotContains(t, schema.String(), migrate.TableName)
+
+	var dump bytes.Buffer
+	err = Dump(context.Background(), db, &dump)
+	assert.NoError(t, err)
+	require.Contains(t, dump.String(), "CREATE TABLE "+migrate.TableName)
+
+	// the history of the migrations is restored along with the tables
+	restored, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer restored.Close()
+
+	err = ExecSQL(context.Background(), restored, &dump, new(bytes.Buffer))
+	assert.NoError(t, err)
+
+	m, err = migrate.New(restored, migrations)
+	assert.NoError(t, err)
+	status, err := m.Status(context.Background())
+	assert.NoError(t, err)
+	require.Len(t, status, 1)
+	require.True(t, status[0].Applied)
+
+	applied, err := m.Up(context.Background(), 0)
+	assert.NoError(t, err)
+	require.Empty(t, applied)
+}
+
+func TestDumpGeneratedFields(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE test (a INT PRIMARY KEY, b INT, c INT GENERATED ALWAYS AS (a + b));
+		INSERT INTO test (a, b) VALUES (1, 10), (2, 20);
+	`)
+	assert.NoError(t, err)
+
+	var dump bytes.Buffer
+	err = Dump(context.Background(), db, &dump)
+	assert.NoError(t, err)
+
+	// generated fields are computed again when the dump is restored
+	restored, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer restored.Close()
+
+	err = ExecSQL(context.Background(), restored, &dump, new(bytes.Buffer))
+	assert.NoError(t, err)
+
+	d, err := restored.QueryDocument("SELECT SUM(c) AS total FROM test")
+	assert.NoError(t, err)
+	data, err := document.MarshalJSON(d)
+	assert.NoError(t, err)
+	require.JSONEq(t, `{"total": 33}`, string(data))
+}
@@ -26,8 +26,9 @@ func ExecSQL(ctx context.Context, db *genji.DB, r io.Reader, w io.Writer) error
 	for _, p := range q.Statements {
 		qq := query.New(p)
 		qctx := query.Context{
-			Ctx: ctx,
-			DB:  db.DB,
+			Ctx:     ctx,
+			DB:      db.DB,
+			Session: db.Session,
 		}
 		err = qq.Prepare(&qctx)
 		if err != nil {
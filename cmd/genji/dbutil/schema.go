@@ -5,15 +5,13 @@ import (
 
 	"github.com/genjidb/genji"
 	"github.com/genjidb/genji/document"
-	"github.com/genjidb/genji/internal/query/statement"
-	"github.com/genjidb/genji/internal/sql/parser"
 	"github.com/genjidb/genji/types"
 )
 
 func QueryTables(tx *genji.Tx, tables []string, fn func(name, query string) error) error {
-	query := "SELECT name, sql FROM __genji_catalog WHERE type = 'table' AND name != '__genji_sequence'"
+	query := "SELECT name, sql FROM __genji_tables"
 	if len(tables) > 0 {
-		query += " AND name IN ?"
+		query += " WHERE name IN ?"
 	}
 
 	res, err := tx.Query(query, tables)
@@ -33,11 +31,67 @@ func QueryTables(tx *genji.Tx, tables []string, fn func(name, query string) erro
 	})
 }
 
+// QueryViews calls fn for every view, regular views first, then materialized views.
+// Materialized views run their query when they are created, so they must come last.
+// If names is provided, only selected views are returned.
+func QueryViews(tx *genji.Tx, names []string, fn func(name, query string) error) error {
+	for _, cond := range []string{"namespace IS NULL", "namespace IS NOT NULL"} {
+		query := "SELECT name, sql FROM __genji_catalog WHERE type = 'view' AND " + cond
+		if len(names) > 0 {
+			query += " AND name IN ?"
+		}
+
+		res, err := tx.Query(query, names)
+		if err != nil {
+			return err
+		}
+
+		err = res.Iterate(func(d types.Document) error {
+			var name, query string
+			if err := document.Scan(d, &name, &query); err != nil {
+				return err
+			}
+
+			return fn(name, query)
+		})
+		res.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QueryTriggers calls fn for every trigger.
+// If tables is provided, only the triggers of the selected tables are returned.
+func QueryTriggers(tx *genji.Tx, tables []string, fn func(name, query string) error) error {
+	query := "SELECT name, sql FROM __genji_catalog WHERE type = 'trigger'"
+	if len(tables) > 0 {
+		query += " AND table_name IN ?"
+	}
+
+	res, err := tx.Query(query, tables)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	return res.Iterate(func(d types.Document) error {
+		var name, query string
+		if err := document.Scan(d, &name, &query); err != nil {
+			return err
+		}
+
+		return fn(name, query)
+	})
+}
+
 func ListIndexes(ctx context.Context, db *genji.DB, tableName string) ([]string, error) {
 	var listName []string
-	q := "SELECT sql FROM __genji_catalog WHERE type = 'index'"
+	q := "SELECT name FROM __genji_indexes"
 	if tableName != "" {
-		q += " AND table_name = ?"
+		q += " WHERE table_name = ?"
 	}
 	res, err := db.Query(q, tableName)
 	if err != nil {
@@ -46,18 +100,13 @@ func ListIndexes(ctx context.Context, db *genji.DB, tableName string) ([]string,
 	defer res.Close()
 
 	err = res.Iterate(func(d types.Document) error {
-		var query string
-		err = document.Scan(d, &query)
-		if err != nil {
-			return err
-		}
-
-		q, err := parser.ParseQuery(query)
+		var name string
+		err = document.Scan(d, &name)
 		if err != nil {
 			return err
 		}
 
-		listName = append(listName, q.Statements[0].(*statement.CreateIndexStmt).Info.IndexName)
+		listName = append(listName, name)
 		return nil
 	})
 	if err != nil {
@@ -8,13 +8,16 @@ var packageDocs = map[string]functionDocs{
 }
 
 var builtinDocs = functionDocs{
-	"pk":     "The pk() function returns the primary key for the current document",
-	"count":  "Returns a count of the number of times that arg1 is not NULL in a group. The count(*) function (with no arguments) returns the total number of rows in the group.",
-	"min":    "Returns the minimum value of the arg1 expression in a group.",
-	"max":    "Returns the maximum value of the arg1 expressein in a group.",
-	"sum":    "The sum function returns the sum of all values taken by the arg1 expression in a group.",
-	"avg":    "The avg function returns the average of all values taken by the arg1 expression in a group.",
-	"typeof": "The typeof function returns the type of arg1.",
+	"pk":      "The pk() function returns the primary key for the current document",
+	"count":   "Returns a count of the number of times that arg1 is not NULL in a group. The count(*) function (with no arguments) returns the total number of rows in the group.",
+	"min":     "Returns the minimum value of the arg1 expression in a group.",
+	"max":     "Returns the maximum value of the arg1 expressein in a group.",
+	"sum":     "The sum function returns the sum of all values taken by the arg1 expression in a group.",
+	"avg":     "The avg function returns the average of all values taken by the arg1 expression in a group.",
+	"typeof":  "The typeof function returns the type of arg1.",
+	"currval": "Returns the last value returned by the arg1 sequence in the current transaction.",
+	"setval":  "Sets the current value of the arg1 sequence to arg2 and returns arg2. The next value of the sequence follows arg2.",
+	"lastval": "Returns the last value returned by any sequence in the current transaction.",
 }
 
 var mathDocs = functionDocs{
@@ -75,9 +75,9 @@ var commands = []command{
 	},
 	{
 		Name:        ".schema",
-		Options:     "[table_name]",
+		Options:     "[table_name|view_name]",
 		DisplayName: ".schema",
-		Description: "Show the CREATE statements of all tables or of the selected ones.",
+		Description: "Show the CREATE statements of all tables and views or of the selected ones.",
 	},
 	{
 		Name:        ".import",
@@ -127,7 +127,7 @@ func runDocCmd(expr string) error {
 
 // runTablesCmd displays all tables.
 func runTablesCmd(db *genji.DB, w io.Writer) error {
-	res, err := db.Query("SELECT name FROM __genji_catalog WHERE type = 'table' AND name != '__genji_sequence'")
+	res, err := db.Query("SELECT name FROM __genji_tables")
 	if err != nil {
 		return err
 	}
@@ -149,7 +149,7 @@ func runTablesCmd(db *genji.DB, w io.Writer) error {
 func runIndexesCmd(db *genji.DB, tableName string, w io.Writer) error {
 	// ensure table exists
 	if tableName != "" {
-		_, err := db.QueryDocument("SELECT 1 FROM __genji_catalog WHERE table_name = ? LIMIT 1", tableName)
+		_, err := db.QueryDocument("SELECT 1 FROM __genji_tables WHERE name = ? LIMIT 1", tableName)
 		if err != nil {
 			if errors.Is(err, errs.ErrDocumentNotFound) {
 				return fmt.Errorf("%w: %q", errs.NotFoundError{Name: tableName}, tableName)
@@ -400,8 +400,9 @@ func (sh *Shell) executeInput(ctx context.Context, in string) error {
 	// it must not be in the middle of a multi line query though
 	case !sh.multiLine && strings.HasPrefix(in, "."), in == "help", in == "exit":
 		return sh.runCommand(ctx, in)
-	// If it ends with a ";" we can run a query
-	case strings.HasSuffix(in, ";"):
+	// If it ends with a ";" we can run a query,
+	// unless the ";" ends a statement of a trigger body.
+	case strings.HasSuffix(in, ";") && !isIncompleteQuery(sh.query+in):
 		sh.query = sh.query + in
 		sh.multiLine = false
 		sh.livePrefix = in
@@ -423,6 +424,14 @@ func (sh *Shell) executeInput(ctx context.Context, in string) error {
 	return nil
 }
 
+// isIncompleteQuery reports whether the query stops before the end of its last statement.
+func isIncompleteQuery(q string) bool {
+	_, err := parser.ParseQuery(q)
+
+	var perr *parser.ParseError
+	return errors.As(err, &perr) && perr.Found == "EOF"
+}
+
 func (sh *Shell) runCommand(ctx context.Context, in string) error {
 	in = strings.TrimSuffix(in, ";")
 	cmd := strings.Fields(in)
@@ -505,7 +514,7 @@ func (sh *Shell) changelivePrefix() (string, bool) {
 func (sh *Shell) getAllTables(ctx context.Context) ([]string, error) {
 	var tables []string
 
-	res, err := sh.db.Query("SELECT name FROM __genji_catalog WHERE type = 'table'")
+	res, err := sh.db.Query("SELECT name FROM __genji_tables")
 	if err != nil {
 		return nil, err
 	}
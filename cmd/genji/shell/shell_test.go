@@ -0,0 +1,25 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsIncompleteQuery(t *testing.T) {
+	tests := []struct {
+		query      string
+		incomplete bool
+	}{
+		{"SELECT 1;", false},
+		{"SELECT * FROM;", false},
+		{"CREATE TRIGGER t AFTER INSERT ON test BEGIN DELETE FROM foo;", true},
+		{"CREATE TRIGGER t AFTER INSERT ON test BEGIN DELETE FROM foo; END;", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			require.Equal(t, test.incomplete, isIncompleteQuery(test.query))
+		})
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
-	"fmt"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
@@ -19,33 +19,54 @@ import (
 	"github.com/genjidb/genji/internal/environment"
 	"github.com/genjidb/genji/internal/query"
 	"github.com/genjidb/genji/internal/query/statement"
-	"github.com/genjidb/genji/internal/sql/parser"
 	"github.com/genjidb/genji/internal/stream"
 	"github.com/genjidb/genji/types"
 )
 
 // DB represents a collection of tables stored in the underlying engine.
 type DB struct {
-	DB  *database.Database
-	ctx context.Context
-	pdb *pebble.DB
+	DB *database.Database
+	// Session whose parameters are changed by the SET statement.
+	// It is shared by the handles returned by WithContext.
+	Session *statement.Session
+	ctx     context.Context
+	pdb     *pebble.DB
+	plans   *planCache
 }
 
-func newDB(ctx context.Context, pdb *pebble.DB, opts *pebble.Options) (*DB, error) {
-	db, err := database.New(ctx, pdb, opts)
+func newDB(ctx context.Context, pdb *pebble.DB, popts *pebble.Options, opts *Options) (*DB, error) {
+	db, err := database.New(ctx, pdb, popts)
 	if err != nil {
 		return nil, err
 	}
+	db.NoSync = opts.NoSync
+	db.HideExpiredDocuments = opts.HideExpiredDocuments
+	if opts.HashAggregateMemoryLimit > 0 {
+		db.HashAggregateMemoryLimit = opts.HashAggregateMemoryLimit
+	}
+	if opts.TTLInterval != 0 {
+		db.TTLInterval = opts.TTLInterval
+	}
 
 	err = catalogstore.LoadCatalog(pdb, db.Catalog)
 	if err != nil {
 		return nil, err
 	}
 
+	err = db.LoadChangeFeed()
+	if err != nil {
+		return nil, err
+	}
+
+	// expired documents are deleted in the background
+	db.StartTTLWorker()
+
 	return &DB{
-		pdb: pdb,
-		DB:  db,
-		ctx: ctx,
+		pdb:     pdb,
+		DB:      db,
+		Session: statement.NewSession(),
+		ctx:     ctx,
+		plans:   newPlanCache(0),
 	}, nil
 }
 
@@ -53,20 +74,112 @@ func newDB(ctx context.Context, pdb *pebble.DB, opts *pebble.Options) (*DB, erro
 // If path is equal to ":memory:" it will open an in-memory database,
 // otherwise it will create an on-disk database using the BoltDB engine.
 func Open(path string) (*DB, error) {
-	var opts pebble.Options
+	return OpenWith(path, nil)
+}
+
+// Options configure the database opened by OpenWith.
+// The zero value uses the default configuration.
+type Options struct {
+	// File system used to store the database.
+	// If nil, the OS file system is used, or an in-memory
+	// file system if the path is ":memory:".
+	FS vfs.FS
+	// Open the database in read-only mode.
+	// The database must already exist and any attempt to
+	// modify it returns an error.
+	ReadOnly bool
+	// Size of the block cache, in bytes.
+	// If zero, the default size of Pebble is used.
+	CacheSize int64
+	// Size of a memtable, in bytes.
+	// If zero, the default size of Pebble is used.
+	MemTableSize int
+	// If true, commits return without waiting for the write-ahead log
+	// to be synced to disk. A crash may lose the last committed
+	// transactions but doesn't corrupt the database.
+	NoSync bool
+	// If true, the write-ahead log is disabled. A crash loses every
+	// transaction that was not flushed to disk yet.
+	DisableWAL bool
+	// Maximum duration of a statement. Statements running for longer are
+	// interrupted and return context.DeadlineExceeded.
+	// It can be overridden for a session with SET statement_timeout.
+	// If zero, statements are not limited.
+	StatementTimeout time.Duration
+	// Maximum number of queries whose plan is cached. The plans are reused
+	// by the queries and prepared statements that have the same text,
+	// until the schema of the database is modified.
+	// If zero, 256 queries are cached. If negative, the cache is disabled.
+	PlanCacheSize int
+	// Amount of memory, in bytes, a GROUP BY query can use to hold its groups
+	// before spilling them to a temporary store on disk.
+	// If zero, 16MiB are used.
+	HashAggregateMemoryLimit int64
+	// Delay between two deletions of the expired documents of the tables
+	// created WITH TTL. If zero, they are deleted every minute.
+	// If negative, they are never deleted in the background.
+	TTLInterval time.Duration
+	// If true, the expired documents that were not deleted yet
+	// are skipped when reading the tables.
+	HideExpiredDocuments bool
+	// Base options of the underlying Pebble database, for advanced tuning.
+	// They are copied and overridden by the other options.
+	PebbleOptions *pebble.Options
+}
 
+// OpenWith creates a Genji database at the given path, configured with opts.
+// If opts is nil, it behaves like Open.
+func OpenWith(path string, opts *Options) (*DB, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	var popts pebble.Options
+	if opts.PebbleOptions != nil {
+		popts = *opts.PebbleOptions.Clone()
+	}
+
+	if opts.FS != nil {
+		popts.FS = opts.FS
+	}
 	if path == ":memory:" {
-		opts.FS = vfs.NewMem()
+		if opts.FS == nil {
+			popts.FS = vfs.NewMem()
+		}
 		path = ""
 	}
 
-	pdb, err := pebble.Open(path, &opts)
+	if opts.ReadOnly {
+		popts.ReadOnly = true
+	}
+	if opts.MemTableSize > 0 {
+		popts.MemTableSize = opts.MemTableSize
+	}
+	if opts.DisableWAL {
+		popts.DisableWAL = true
+	}
+	if opts.CacheSize > 0 {
+		c := pebble.NewCache(opts.CacheSize)
+		// the database holds its own reference to the cache
+		defer c.Unref()
+		popts.Cache = c
+	}
+
+	pdb, err := pebble.Open(path, &popts)
 	if err != nil {
 		return nil, err
 	}
 
 	ctx := context.Background()
-	return newDB(ctx, pdb, &opts)
+	db, err := newDB(ctx, pdb, &popts, opts)
+	if err != nil {
+		_ = pdb.Close()
+		return nil, err
+	}
+	db.DB.SetStatementTimeout(opts.StatementTimeout)
+	db.plans = newPlanCache(opts.PlanCacheSize)
+
+	return db, nil
 }
 
 // WithContext creates a new database handle using the given context for every operation.
@@ -75,6 +188,14 @@ func (db DB) WithContext(ctx context.Context) *DB {
 	return &db
 }
 
+// NewSession creates a new database handle with its own session: the parameters
+// changed with the SET statement, such as statement_timeout, only apply to the queries
+// run by this handle, or by the handles and transactions created from it.
+func (db DB) NewSession() *DB {
+	db.Session = statement.NewSession()
+	return &db
+}
+
 // Close the database.
 func (db *DB) Close() error {
 	err := db.DB.Close()
@@ -162,20 +283,26 @@ func (db *DB) Exec(q string, args ...interface{}) error {
 	return stmt.Exec(args...)
 }
 
-// Prepare parses the query and returns a prepared statement.
-func (db *DB) Prepare(q string) (*Statement, error) {
-	pq, err := parser.ParseQuery(q)
+// ExecResult runs a query against the database and returns the number of documents
+// it wrote and the key of the last inserted document.
+func (db *DB) ExecResult(q string, args ...interface{}) (*ExecResult, error) {
+	stmt, err := db.Prepare(q)
 	if err != nil {
 		return nil, err
 	}
 
-	err = pq.Prepare(newQueryContext(db, nil, nil))
+	return stmt.ExecResult(args...)
+}
+
+// Prepare parses the query and returns a prepared statement.
+func (db *DB) Prepare(q string) (*Statement, error) {
+	p, err := db.prepare(q, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Statement{
-		pq: pq,
+		p:  p,
 		db: db,
 	}, nil
 }
@@ -200,6 +327,34 @@ func (tx *Tx) Commit() error {
 	return tx.tx.Commit()
 }
 
+// Savepoint marks the current state of the transaction. Rolling back to the savepoint
+// reverts the modifications made by the transaction since then, without ending it,
+// which allows to recover from the failure of a part of the transaction.
+func (tx *Tx) Savepoint() *Savepoint {
+	return &Savepoint{
+		sp: tx.tx.Savepoint(""),
+	}
+}
+
+// A Savepoint marks the state of a transaction at a given moment.
+// Savepoints can be nested.
+type Savepoint struct {
+	sp *database.Savepoint
+}
+
+// Rollback reverts the modifications made by the transaction since the savepoint was created.
+// The savepoints created after this one are released. The savepoint remains
+// active and can be rolled back to again.
+func (sp *Savepoint) Rollback() error {
+	return sp.sp.Rollback()
+}
+
+// Release the savepoint, keeping the modifications made since it was created.
+// The savepoints created after this one are released as well.
+func (sp *Savepoint) Release() error {
+	return sp.sp.Release()
+}
+
 // Query the database withing the transaction and returns the result.
 // Closing the returned result after usage is not mandatory.
 func (tx *Tx) Query(q string, args ...interface{}) (*Result, error) {
@@ -232,20 +387,26 @@ func (tx *Tx) Exec(q string, args ...interface{}) (err error) {
 	return stmt.Exec(args...)
 }
 
-// Prepare parses the query and returns a prepared statement.
-func (tx *Tx) Prepare(q string) (*Statement, error) {
-	pq, err := parser.ParseQuery(q)
+// ExecResult runs a query against the database within tx and returns the number of documents
+// it wrote and the key of the last inserted document.
+func (tx *Tx) ExecResult(q string, args ...interface{}) (*ExecResult, error) {
+	stmt, err := tx.Prepare(q)
 	if err != nil {
 		return nil, err
 	}
 
-	err = pq.Prepare(newQueryContext(tx.db, tx, nil))
+	return stmt.ExecResult(args...)
+}
+
+// Prepare parses the query and returns a prepared statement.
+func (tx *Tx) Prepare(q string) (*Statement, error) {
+	p, err := tx.db.prepare(q, tx)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Statement{
-		pq: pq,
+		p:  p,
 		db: tx.db,
 		tx: tx,
 	}, nil
@@ -255,8 +416,9 @@ func (tx *Tx) Prepare(q string) (*Statement, error) {
 // it will only be valid until Tx closes. If it has been created on a DB, it
 // is valid until the DB closes.
 // It's safe for concurrent use by multiple goroutines.
+// Its plan is prepared again if the schema of the database is modified.
 type Statement struct {
-	pq query.Query
+	p  *preparedQuery
 	db *DB
 	tx *Tx
 }
@@ -264,10 +426,12 @@ type Statement struct {
 // Query the database and return the result.
 // The returned result must always be closed after usage.
 func (s *Statement) Query(args ...interface{}) (*Result, error) {
-	var r *statement.Result
-	var err error
+	pq, err := s.p.plan(s.db, s.tx, true)
+	if err != nil {
+		return nil, err
+	}
 
-	r, err = s.pq.Run(newQueryContext(s.db, s.tx, argsToParams(args)))
+	r, err := pq.Run(newQueryContext(s.db, s.tx, argsToParams(args)))
 	if err != nil {
 		return nil, err
 	}
@@ -275,6 +439,13 @@ func (s *Statement) Query(args ...interface{}) (*Result, error) {
 	return &Result{result: r}, nil
 }
 
+// WithContext returns a copy of the statement that runs its query using ctx.
+// The execution of the query is interrupted once ctx is done.
+func (s Statement) WithContext(ctx context.Context) *Statement {
+	s.db = s.db.WithContext(ctx)
+	return &s
+}
+
 func argsToParams(args []interface{}) []environment.Param {
 	nv := make([]environment.Param, len(args))
 	for i := range args {
@@ -341,20 +512,79 @@ func scanDocument(iter document.Iterator) (types.Document, error) {
 
 // Exec a query against the database without returning the result.
 func (s *Statement) Exec(args ...interface{}) (err error) {
+	_, err = s.ExecResult(args...)
+	return err
+}
+
+// ExecResult runs the statement and returns the number of documents
+// it wrote and the key of the last inserted document.
+// If the query contains multiple statements, only the last one is described.
+func (s *Statement) ExecResult(args ...interface{}) (er *ExecResult, err error) {
 	res, err := s.Query(args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
-		er := res.Close()
+		cerr := res.Close()
 		if err == nil {
-			err = er
+			err = cerr
+		}
+		if err != nil {
+			er = nil
 		}
 	}()
 
-	return res.Iterate(func(d types.Document) error {
-		return nil
-	})
+	err = res.result.Skip()
+	if err != nil {
+		return nil, err
+	}
+
+	var r ExecResult
+	if stats := res.result.Stats; stats != nil {
+		r.rowsAffected = stats.RowsAffected
+		if stats.LastInsertKey != nil {
+			r.lastInsertKey, err = stats.LastInsertKey.Decode()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &r, nil
+}
+
+// ExecResult describes the documents written by a statement.
+type ExecResult struct {
+	rowsAffected  int64
+	lastInsertKey []types.Value
+}
+
+// RowsAffected returns the number of documents inserted, updated or deleted by the statement.
+// Documents written by triggers are not counted.
+func (r *ExecResult) RowsAffected() int64 {
+	return r.rowsAffected
+}
+
+// LastInsertKey returns the primary key of the last document inserted by the statement,
+// with one value per primary key field, or nil if no document was inserted.
+// For tables without a primary key, it contains the generated docid.
+func (r *ExecResult) LastInsertKey() []types.Value {
+	return r.lastInsertKey
+}
+
+// LastInsertID returns the key of the last document inserted by the statement
+// if it is made of a single integer, such as the docid of tables without a primary key.
+// It returns 0 if no document was inserted.
+func (r *ExecResult) LastInsertID() (int64, error) {
+	if r.lastInsertKey == nil {
+		return 0, nil
+	}
+
+	if len(r.lastInsertKey) != 1 || r.lastInsertKey[0].Type() != types.IntegerValue {
+		return 0, errors.New("the key of the last inserted document is not an integer")
+	}
+
+	return r.lastInsertKey[0].V().(int64), nil
 }
 
 // Result of a query.
@@ -366,35 +596,57 @@ func (r *Result) Iterate(fn func(d types.Document) error) error {
 	return r.result.Iterate(fn)
 }
 
+// Fields returns the names of the fields of the documents of the result.
+// If the documents are not projected, it returns "*".
 func (r *Result) Fields() []string {
+	columns := r.Columns()
+	if columns == nil {
+		return nil
+	}
+
+	fields := make([]string, len(columns))
+	for i := range columns {
+		fields[i] = columns[i].Name
+	}
+
+	return fields
+}
+
+// Column describes a field of the documents of a result.
+type Column struct {
+	Name string
+	// Type of the values of the field, or types.AnyType
+	// if it cannot be determined before running the query.
+	Type types.ValueType
+	// Nullable reports whether the field can be null or missing.
+	// It is always true if the type is unknown.
+	Nullable bool
+}
+
+// Columns returns the fields of the documents of the result, typed using the
+// projected expressions and the field constraints of the queried table.
+// If the documents are not projected, it returns a single "*" column.
+func (r *Result) Columns() []Column {
 	if r.result.Iterator == nil {
 		return nil
 	}
 
 	stmt, ok := r.result.Iterator.(*statement.StreamStmtIterator)
-	if !ok || stmt.Stream.Op == nil {
+	if !ok {
 		return nil
 	}
 
-	// Search for the ProjectOperator. If found, extract the projected expression list
-	for op := stmt.Stream.First(); op != nil; op = op.GetNext() {
-		if po, ok := op.(*stream.DocsProjectOperator); ok {
-			// if there are no projected expression, it's a wildcard
-			if len(po.Exprs) == 0 {
-				break
-			}
-
-			fields := make([]string, len(po.Exprs))
-			for i := range po.Exprs {
-				fields[i] = fmt.Sprintf("%s", po.Exprs[i])
-			}
+	cols := stmt.Columns()
+	if cols == nil {
+		return nil
+	}
 
-			return fields
-		}
+	columns := make([]Column, len(cols))
+	for i, c := range cols {
+		columns[i] = Column{Name: c.Name, Type: c.Type, Nullable: c.Nullable}
 	}
 
-	// the stream will output documents in a single field
-	return []string{"*"}
+	return columns
 }
 
 // Close the result stream.
@@ -408,9 +660,10 @@ func (r *Result) Close() (err error) {
 
 func newQueryContext(db *DB, tx *Tx, params []environment.Param) *query.Context {
 	ctx := query.Context{
-		Ctx:    db.ctx,
-		DB:     db.DB,
-		Params: params,
+		Ctx:     db.ctx,
+		DB:      db.DB,
+		Params:  params,
+		Session: db.Session,
 	}
 
 	if tx != nil {
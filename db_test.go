@@ -8,7 +8,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/pebble/vfs"
 	"github.com/genjidb/genji"
 	"github.com/genjidb/genji/document"
 	errs "github.com/genjidb/genji/errors"
@@ -143,6 +145,91 @@ func TestOpen(t *testing.T) {
 	testutil.RequireDocJSONEq(t, d, `{"name": "seqD", "seq": 500}`)
 }
 
+func TestOpenWith(t *testing.T) {
+	t.Run("custom file system", func(t *testing.T) {
+		fs := vfs.NewMem()
+
+		db, err := genji.OpenWith("testdb", &genji.Options{
+			FS:           fs,
+			CacheSize:    1 << 20,
+			MemTableSize: 1 << 20,
+			NoSync:       true,
+		})
+		assert.NoError(t, err)
+
+		err = db.Exec("CREATE TABLE test; INSERT INTO test (a) VALUES (1)")
+		assert.NoError(t, err)
+		assert.NoError(t, db.Close())
+
+		// the database was written to the given file system
+		_, err = fs.Stat("testdb")
+		assert.NoError(t, err)
+
+		db, err = genji.OpenWith("testdb", &genji.Options{FS: fs})
+		assert.NoError(t, err)
+		defer db.Close()
+
+		d, err := db.QueryDocument("SELECT a FROM test")
+		assert.NoError(t, err)
+		testutil.RequireDocJSONEq(t, d, `{"a": 1}`)
+	})
+
+	t.Run("hash aggregate memory limit", func(t *testing.T) {
+		db, err := genji.OpenWith(":memory:", &genji.Options{HashAggregateMemoryLimit: 1})
+		assert.NoError(t, err)
+		defer db.Close()
+		require.Equal(t, int64(1), db.DB.HashAggregateMemoryLimit)
+
+		// groups are spilled to disk
+		err = db.Exec("CREATE TABLE test; INSERT INTO test (a) VALUES (1), (2), (1)")
+		assert.NoError(t, err)
+		d, err := db.QueryDocument("SELECT COUNT(*) AS n FROM test GROUP BY a ORDER BY n DESC LIMIT 1")
+		assert.NoError(t, err)
+		testutil.RequireDocJSONEq(t, d, `{"n": 2}`)
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "genji")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "testdb")
+
+		// the database must exist
+		_, err = genji.OpenWith(path, &genji.Options{ReadOnly: true})
+		assert.Error(t, err)
+
+		db, err := genji.Open(path)
+		assert.NoError(t, err)
+		err = db.Exec("CREATE TABLE test; CREATE SEQUENCE seq; INSERT INTO test (a) VALUES (1)")
+		assert.NoError(t, err)
+		assert.NoError(t, db.Close())
+
+		db, err = genji.OpenWith(path, &genji.Options{ReadOnly: true})
+		assert.NoError(t, err)
+
+		d, err := db.QueryDocument("SELECT a FROM test")
+		assert.NoError(t, err)
+		testutil.RequireDocJSONEq(t, d, `{"a": 1}`)
+
+		err = db.Exec("INSERT INTO test (a) VALUES (2)")
+		assert.Error(t, err)
+		err = db.Exec("SELECT NEXT VALUE FOR seq")
+		assert.Error(t, err)
+		_, err = db.Begin(true)
+		assert.Error(t, err)
+		assert.NoError(t, db.Close())
+
+		// the database is left untouched
+		db, err = genji.Open(path)
+		assert.NoError(t, err)
+		defer db.Close()
+
+		d, err = db.QueryDocument("SELECT COUNT(*) FROM test")
+		assert.NoError(t, err)
+		testutil.RequireDocJSONEq(t, d, `{"COUNT(*)": 1}`)
+	})
+}
+
 func TestQueryDocument(t *testing.T) {
 	db, err := genji.Open(":memory:")
 	assert.NoError(t, err)
@@ -194,6 +281,331 @@ func TestQueryDocument(t *testing.T) {
 	})
 }
 
+func TestExecResult(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	res, err := db.ExecResult("CREATE TABLE test(a INT, b TEXT); CREATE TABLE log(a INT)")
+	assert.NoError(t, err)
+	require.EqualValues(t, 0, res.RowsAffected())
+	require.Nil(t, res.LastInsertKey())
+
+	err = db.Exec("CREATE TRIGGER test_log AFTER INSERT ON test BEGIN INSERT INTO log(a) VALUES (NEW.a); END")
+	assert.NoError(t, err)
+
+	// documents written by triggers are not counted
+	res, err = db.ExecResult("INSERT INTO test(a, b) VALUES (1, 'a'), (2, 'a'), (3, 'b')")
+	assert.NoError(t, err)
+	require.EqualValues(t, 3, res.RowsAffected())
+	id, err := res.LastInsertID()
+	assert.NoError(t, err)
+	require.EqualValues(t, 3, id)
+
+	res, err = db.ExecResult("UPDATE test SET b = 'c' WHERE b = ?", "a")
+	assert.NoError(t, err)
+	require.EqualValues(t, 2, res.RowsAffected())
+	require.Nil(t, res.LastInsertKey())
+
+	// only the last statement is described
+	tx, err := db.Begin(true)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	res, err = tx.ExecResult("DELETE FROM test WHERE a = 1; DELETE FROM test")
+	assert.NoError(t, err)
+	require.EqualValues(t, 2, res.RowsAffected())
+
+	err = tx.Exec("CREATE TABLE pk(a INT, b TEXT, PRIMARY KEY (a, b))")
+	assert.NoError(t, err)
+
+	res, err = tx.ExecResult("INSERT INTO pk(a, b) VALUES (1, 'a'), (2, 'b') ON CONFLICT DO NOTHING")
+	assert.NoError(t, err)
+	require.EqualValues(t, 2, res.RowsAffected())
+	require.Len(t, res.LastInsertKey(), 2)
+	require.Equal(t, int64(2), res.LastInsertKey()[0].V())
+	require.Equal(t, "b", res.LastInsertKey()[1].V())
+	_, err = res.LastInsertID()
+	assert.Error(t, err)
+
+	res, err = tx.ExecResult("INSERT INTO pk(a, b) VALUES (1, 'a'), (3, 'c') ON CONFLICT DO NOTHING")
+	assert.NoError(t, err)
+	require.EqualValues(t, 1, res.RowsAffected())
+
+	res, err = tx.ExecResult("INSERT INTO pk(a, b) VALUES (1, 'a') ON CONFLICT DO REPLACE")
+	assert.NoError(t, err)
+	require.EqualValues(t, 1, res.RowsAffected())
+}
+
+func TestResultColumns(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE test(a INT PRIMARY KEY, b TEXT NOT NULL, c DOUBLE, d.e BOOL);
+		CREATE INDEX test_c ON test(c);
+	`)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		query   string
+		columns []genji.Column
+	}{
+		{"SELECT * FROM test", []genji.Column{{Name: "*", Type: types.DocumentValue}}},
+		{"SELECT a, b, c, d.e, f FROM test", []genji.Column{
+			{Name: "a", Type: types.IntegerValue},
+			{Name: "b", Type: types.TextValue},
+			{Name: "c", Type: types.DoubleValue, Nullable: true},
+			{Name: "d.e", Type: types.BoolValue, Nullable: true},
+			{Name: "f", Type: types.AnyType, Nullable: true},
+		}},
+		{"SELECT a + 1 AS x, a + c, a / 2, a % 2, a & 1, b || 'foo', a > 1, c IS NOT NULL, CAST(a AS TEXT), [a], {a: a}, NULL FROM test WHERE c = 1.5", []genji.Column{
+			// the addition of integers may overflow to a double
+			{Name: "x", Type: types.AnyType, Nullable: true},
+			{Name: "a + c", Type: types.DoubleValue, Nullable: true},
+			{Name: "a / 2", Type: types.IntegerValue, Nullable: true},
+			{Name: "a % 2", Type: types.IntegerValue, Nullable: true},
+			{Name: "a & 1", Type: types.IntegerValue},
+			{Name: "b || \"foo\"", Type: types.TextValue, Nullable: true},
+			{Name: "a > 1", Type: types.BoolValue},
+			{Name: "c IS NOT NULL", Type: types.BoolValue},
+			{Name: "CAST(a AS text)", Type: types.TextValue},
+			{Name: "[a]", Type: types.ArrayValue},
+			{Name: "{a: a}", Type: types.DocumentValue},
+			{Name: "NULL", Type: types.AnyType, Nullable: true},
+		}},
+		{"SELECT b, COUNT(*), MIN(a), AVG(c) FROM test GROUP BY b", []genji.Column{
+			{Name: "b", Type: types.TextValue},
+			{Name: "COUNT(*)", Type: types.IntegerValue},
+			{Name: "MIN(a)", Type: types.IntegerValue, Nullable: true},
+			{Name: "AVG(c)", Type: types.DoubleValue, Nullable: true},
+		}},
+		{"SELECT 1, 'a', TYPEOF(d) FROM test", []genji.Column{
+			{Name: "1", Type: types.IntegerValue},
+			{Name: "\"a\"", Type: types.TextValue},
+			{Name: "typeof(d)", Type: types.TextValue},
+		}},
+		{"INSERT INTO test (a, b) VALUES (1, 'a') RETURNING a, c", []genji.Column{
+			{Name: "a", Type: types.IntegerValue},
+			{Name: "c", Type: types.DoubleValue, Nullable: true},
+		}},
+		{"CREATE TABLE foo", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			res, err := db.Query(test.query)
+			assert.NoError(t, err)
+			defer res.Close()
+
+			require.Equal(t, test.columns, res.Columns())
+
+			var fields []string
+			for _, c := range test.columns {
+				fields = append(fields, c.Name)
+			}
+			require.Equal(t, fields, res.Fields())
+		})
+	}
+}
+
+func TestQueryCancel(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE test(a INT PRIMARY KEY, b INT); CREATE INDEX test_b ON test(b)")
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		err = db.Exec("INSERT INTO test(a, b) VALUES (?, ?)", i, i%3)
+		assert.NoError(t, err)
+	}
+
+	queries := []string{
+		"SELECT * FROM test",
+		"SELECT * FROM test WHERE b = 1",
+		"SELECT * FROM test ORDER BY b",
+		"SELECT b, COUNT(*) FROM test GROUP BY b",
+	}
+
+	for _, q := range queries {
+		t.Run(q, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			res, err := db.WithContext(ctx).Query(q)
+			assert.NoError(t, err)
+			defer res.Close()
+
+			var n int
+			err = res.Iterate(func(d types.Document) error {
+				n++
+				cancel()
+				return nil
+			})
+			require.Equal(t, context.Canceled, err)
+			require.Equal(t, 1, n)
+		})
+	}
+}
+
+func TestOpenWithTTL(t *testing.T) {
+	count := func(t *testing.T, db *genji.DB) int {
+		d, err := db.QueryDocument("SELECT COUNT(*) FROM test")
+		assert.NoError(t, err)
+		var n int
+		assert.NoError(t, document.Scan(d, &n))
+		return n
+	}
+
+	open := func(t *testing.T, opts *genji.Options) *genji.DB {
+		db, err := genji.OpenWith(":memory:", opts)
+		assert.NoError(t, err)
+
+		err = db.Exec(`
+			CREATE TABLE test (a INT, exp TEXT) WITH TTL ON exp;
+			INSERT INTO test (a, exp) VALUES (1, "2000-01-01T00:00:00Z"), (2, "3000-01-01T00:00:00Z");
+		`)
+		assert.NoError(t, err)
+		return db
+	}
+
+	t.Run("interval", func(t *testing.T) {
+		db := open(t, &genji.Options{TTLInterval: 10 * time.Millisecond})
+		defer db.Close()
+
+		require.Eventually(t, func() bool {
+			return count(t, db) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		db := open(t, &genji.Options{TTLInterval: -1})
+		defer db.Close()
+
+		time.Sleep(20 * time.Millisecond)
+		require.Equal(t, 2, count(t, db))
+	})
+
+	t.Run("hide expired documents", func(t *testing.T) {
+		db := open(t, &genji.Options{TTLInterval: -1, HideExpiredDocuments: true})
+		defer db.Close()
+
+		require.Equal(t, 1, count(t, db))
+	})
+}
+
+func TestStatementTimeout(t *testing.T) {
+	db, err := genji.OpenWith(":memory:", &genji.Options{StatementTimeout: time.Hour})
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE test(a INT); INSERT INTO test(a) VALUES (1), (2)")
+	assert.NoError(t, err)
+
+	err = db.Exec("SET statement_timeout = '10ms'")
+	assert.NoError(t, err)
+
+	res, err := db.Query("SELECT * FROM test")
+	assert.NoError(t, err)
+	defer res.Close()
+
+	err = res.Iterate(func(d types.Document) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	// the timeout can be disabled
+	err = db.Exec("SET statement_timeout TO 0")
+	assert.NoError(t, err)
+
+	res, err = db.Query("SELECT * FROM test")
+	assert.NoError(t, err)
+	defer res.Close()
+
+	err = res.Iterate(func(d types.Document) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = db.Exec("SET statement_timeout = '-1s'")
+	assert.Error(t, err)
+	err = db.Exec("SET unknown = 1")
+	assert.Error(t, err)
+
+	// the timeout only applies to the session
+	err = db.Exec("SET statement_timeout = '10ms'")
+	assert.NoError(t, err)
+	require.Equal(t, time.Hour, db.DB.StatementTimeout())
+
+	other := db.NewSession()
+	res, err = other.Query("SELECT * FROM test")
+	assert.NoError(t, err)
+	defer res.Close()
+
+	err = res.Iterate(func(d types.Document) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// DEFAULT resets the timeout to the one of the database
+	err = db.Exec("SET statement_timeout TO DEFAULT")
+	assert.NoError(t, err)
+	require.Equal(t, time.Hour, db.Session.StatementTimeout(db.DB))
+}
+
+func TestSavepoint(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE test(a INT PRIMARY KEY)")
+	assert.NoError(t, err)
+
+	err = db.Update(func(tx *genji.Tx) error {
+		// the second batch conflicts with the first one
+		batches := [][]int{{1, 2}, {3, 1}, {4, 5}}
+
+		for _, batch := range batches {
+			sp := tx.Savepoint()
+
+			for _, a := range batch {
+				err := tx.Exec("INSERT INTO test (a) VALUES (?)", a)
+				if err != nil {
+					err = sp.Rollback()
+					if err != nil {
+						return err
+					}
+					break
+				}
+			}
+
+			err := sp.Release()
+			if err != nil {
+				return err
+			}
+		}
+
+		// released savepoints cannot be used anymore
+		sp := tx.Savepoint()
+		assert.NoError(t, sp.Release())
+		require.Error(t, sp.Rollback())
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	res, err := db.Query("SELECT * FROM test")
+	assert.NoError(t, err)
+	defer res.Close()
+
+	testutil.RequireStreamEq(t, `{"a": 1} {"a": 2} {"a": 4} {"a": 5}`, res, false)
+}
+
 func TestPrepareThreadSafe(t *testing.T) {
 	db, err := genji.Open(":memory:")
 	assert.NoError(t, err)
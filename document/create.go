@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"math"
 	"reflect"
-	"strings"
 	"time"
 
 	"github.com/buger/jsonparser"
@@ -110,11 +109,9 @@ func NewFromStruct(s interface{}) (types.Document, error) {
 
 func newFromStruct(ref reflect.Value) (types.Document, error) {
 	var fb FieldBuffer
-	l := ref.NumField()
-	tp := ref.Type()
 
-	for i := 0; i < l; i++ {
-		f := ref.Field(i)
+	for _, sf := range structFields(ref.Type()) {
+		f := ref.Field(sf.index)
 		if !f.IsValid() {
 			continue
 		}
@@ -127,12 +124,8 @@ func newFromStruct(ref reflect.Value) (types.Document, error) {
 			f = f.Elem()
 		}
 
-		sf := tp.Field(i)
-
-		isUnexported := sf.PkgPath != ""
-
-		if sf.Anonymous {
-			if isUnexported && f.Kind() != reflect.Struct {
+		if sf.anonymous {
+			if sf.unexported && f.Kind() != reflect.Struct {
 				continue
 			}
 			d, err := newFromStruct(f)
@@ -147,7 +140,7 @@ func newFromStruct(ref reflect.Value) (types.Document, error) {
 				return nil, err
 			}
 			continue
-		} else if isUnexported {
+		} else if sf.unexported || sf.skip {
 			continue
 		}
 
@@ -156,15 +149,7 @@ func newFromStruct(ref reflect.Value) (types.Document, error) {
 			return nil, err
 		}
 
-		field := strings.ToLower(sf.Name)
-		if gtag, ok := sf.Tag.Lookup("genji"); ok {
-			if gtag == "-" {
-				continue
-			}
-			field = gtag
-		}
-
-		fb.Add(field, v)
+		fb.Add(sf.name, v)
 	}
 
 	return &fb, nil
@@ -25,7 +25,7 @@ func diff(path Path, d1, d2 types.Document) ([]Op, error) {
 	var i, j int
 	for {
 		for i < len(f1) && (j >= len(f2) || f1[i] < f2[j]) {
-			v, err := d1.GetByField(f1[j])
+			v, err := d1.GetByField(f1[i])
 			if err != nil {
 				return nil, err
 			}
@@ -37,6 +37,16 @@ func TestDiff(t *testing.T) {
 				{"delete", document.NewPath("a"), types.NewIntegerValue(1)},
 			},
 		},
+		{
+			name: "add and remove fields",
+			d1:   `{"c": 3, "d": 4}`,
+			d2:   `{"a": 1, "b": 2, "c": 3}`,
+			want: []document.Op{
+				{"set", document.NewPath("a"), types.NewIntegerValue(1)},
+				{"set", document.NewPath("b"), types.NewIntegerValue(2)},
+				{"delete", document.NewPath("d"), types.NewIntegerValue(4)},
+			},
+		},
 		{
 			name: "same",
 			d1:   `{"a": 1}`,
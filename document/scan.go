@@ -3,7 +3,6 @@ package document
 import (
 	"fmt"
 	"reflect"
-	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -43,8 +42,8 @@ func Scan(d types.Document, targets ...interface{}) error {
 // field type when possible, otherwise an error is returned.
 // The decoding of each struct field can be customized by the format string stored
 // under the "genji" key stored in the struct field's tag.
-// The content of the format string is used instead of the struct field name and passed
-// to the GetByField method.
+// The name found before the first comma of the format string is used instead of the struct
+// field name and passed to the GetByField method. The options following it are ignored.
 func StructScan(d types.Document, t interface{}) error {
 	ref := reflect.ValueOf(t)
 
@@ -65,29 +64,19 @@ func structScan(d types.Document, ref reflect.Value) error {
 	}
 
 	sref := reflect.Indirect(ref)
-	stp := sref.Type()
-	l := sref.NumField()
-	for i := 0; i < l; i++ {
-		f := sref.Field(i)
-		sf := stp.Field(i)
-		if sf.Anonymous {
+	for _, sf := range structFields(sref.Type()) {
+		f := sref.Field(sf.index)
+		if sf.anonymous {
 			err := structScan(d, f)
 			if err != nil {
 				return err
 			}
 			continue
 		}
-		var name string
-		if gtag, ok := sf.Tag.Lookup("genji"); ok {
-			if gtag == "-" {
-				continue
-			}
-
-			name = gtag
-		} else {
-			name = strings.ToLower(sf.Name)
+		if sf.skip {
+			continue
 		}
-		v, err := d.GetByField(name)
+		v, err := d.GetByField(sf.name)
 		if errors.Is(err, types.ErrFieldNotFound) {
 			v = types.NewNullValue()
 		} else if err != nil {
@@ -0,0 +1,131 @@
+package document
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// StructTag describes the content of the "genji" tag of a struct field.
+// The tag starts with the name of the field, followed by an optional list
+// of comma separated options:
+//
+//	Name string `genji:"name,pk,unique,notnull,default=<expr>"`
+//
+// If the name is empty, the lowercased struct field name is used.
+// If the tag is "-", the field is ignored.
+// The default option consumes the rest of the tag, so it must be the last one.
+//
+// The options describe the constraints of the field and are only used
+// when a table is created from a struct.
+type StructTag struct {
+	Name       string
+	Skip       bool
+	PrimaryKey bool
+	Unique     bool
+	NotNull    bool
+	// SQL expression of the default value, if any.
+	Default string
+}
+
+// ParseStructTag parses the "genji" tag of the given struct field.
+func ParseStructTag(sf reflect.StructField) (*StructTag, error) {
+	tag := parseTagName(sf)
+
+	gtag := sf.Tag.Get("genji")
+	idx := strings.IndexByte(gtag, ',')
+	if tag.Skip || idx == -1 {
+		return tag, nil
+	}
+
+	opts := gtag[idx+1:]
+	for opts != "" {
+		if strings.HasPrefix(opts, "default=") {
+			tag.Default = strings.TrimPrefix(opts, "default=")
+			if tag.Default == "" {
+				return nil, fmt.Errorf("field %q: missing default value", sf.Name)
+			}
+			break
+		}
+
+		var opt string
+		opt, opts = opts, ""
+		if i := strings.IndexByte(opt, ','); i != -1 {
+			opt, opts = opt[:i], opt[i+1:]
+		}
+
+		switch strings.TrimSpace(opt) {
+		case "pk":
+			tag.PrimaryKey = true
+		case "unique":
+			tag.Unique = true
+		case "notnull":
+			tag.NotNull = true
+		default:
+			return nil, fmt.Errorf("field %q: unknown tag option %q", sf.Name, opt)
+		}
+	}
+
+	return tag, nil
+}
+
+// parseTagName returns the name of the field described by the "genji" tag,
+// ignoring the options.
+func parseTagName(sf reflect.StructField) *StructTag {
+	gtag, ok := sf.Tag.Lookup("genji")
+	if gtag == "-" {
+		return &StructTag{Skip: true}
+	}
+
+	var name string
+	if ok {
+		name = gtag
+		if idx := strings.IndexByte(gtag, ','); idx != -1 {
+			name = gtag[:idx]
+		}
+	}
+	if name == "" {
+		name = strings.ToLower(sf.Name)
+	}
+
+	return &StructTag{Name: name}
+}
+
+// structField describes a field of a struct type,
+// as used to convert structs to documents and back.
+type structField struct {
+	index      int
+	name       string
+	skip       bool
+	anonymous  bool
+	unexported bool
+}
+
+// cache of the fields of the struct types, by reflect.Type.
+var structFieldsCache sync.Map
+
+// structFields returns the fields of the given struct type.
+// The tags of a type are only parsed the first time it is used.
+func structFields(tp reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(tp); ok {
+		return fields.([]structField)
+	}
+
+	fields := make([]structField, tp.NumField())
+	for i := range fields {
+		sf := tp.Field(i)
+		tag := parseTagName(sf)
+
+		fields[i] = structField{
+			index:      i,
+			name:       tag.Name,
+			skip:       tag.Skip,
+			anonymous:  sf.Anonymous,
+			unexported: sf.PkgPath != "",
+		}
+	}
+
+	structFieldsCache.Store(tp, fields)
+	return fields
+}
@@ -0,0 +1,73 @@
+package document_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStructTag(t *testing.T) {
+	type foo struct {
+		A  int
+		B  int `genji:"bb"`
+		C  int `genji:"-"`
+		D  int `genji:",pk,notnull"`
+		E  int `genji:"e,unique,default=[1, 2]"`
+		F  int `genji:"f,bar"`
+		G  int `genji:"g,default="`
+		H  int `genji:"h,"`
+		II int `genji:""`
+	}
+
+	tests := []struct {
+		field    string
+		expected *document.StructTag
+		fails    bool
+	}{
+		{"A", &document.StructTag{Name: "a"}, false},
+		{"B", &document.StructTag{Name: "bb"}, false},
+		{"C", &document.StructTag{Skip: true}, false},
+		{"D", &document.StructTag{Name: "d", PrimaryKey: true, NotNull: true}, false},
+		{"E", &document.StructTag{Name: "e", Unique: true, Default: "[1, 2]"}, false},
+		{"F", nil, true},
+		{"G", nil, true},
+		{"H", &document.StructTag{Name: "h"}, false},
+		{"II", &document.StructTag{Name: "ii"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.field, func(t *testing.T) {
+			sf, ok := reflect.TypeOf(foo{}).FieldByName(test.field)
+			require.True(t, ok)
+
+			tag, err := document.ParseStructTag(sf)
+			if test.fails {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Equal(t, test.expected, tag)
+		})
+	}
+
+	t.Run("options are ignored by NewFromStruct and StructScan", func(t *testing.T) {
+		type bar struct {
+			A int    `genji:"aa,pk,notnull"`
+			B string `genji:",default='x'"`
+		}
+
+		d, err := document.NewFromStruct(bar{A: 1, B: "b"})
+		assert.NoError(t, err)
+
+		data, err := document.MarshalJSON(d)
+		assert.NoError(t, err)
+		require.JSONEq(t, `{"aa": 1, "b": "b"}`, string(data))
+
+		var b bar
+		assert.NoError(t, document.StructScan(d, &b))
+		require.Equal(t, bar{A: 1, B: "b"}, b)
+	})
+}
@@ -5,8 +5,14 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"io"
+	"net/url"
+	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/genjidb/genji"
@@ -32,36 +38,104 @@ func (d sqlDriver) Open(name string) (driver.Conn, error) {
 	return nil, errors.New("requires go1.10 or greater")
 }
 
+// OpenConnector opens the database described by name and returns a connector using it.
+// The name is the path of the database, or ":memory:", optionally followed by
+// options passed as query parameters:
+//
+//	/path/to/db?read_only=true&cache_size=67108864
+//
+// The supported options are read_only, cache_size, memtable_size, no_sync, disable_wal,
+// hash_aggregate_memory_limit and statement_timeout, which is a duration such as 30s.
+// They correspond to the fields of genji.Options.
 func (d sqlDriver) OpenConnector(name string) (driver.Connector, error) {
-	db, err := genji.Open(name)
+	path, opts, err := parseDSN(name)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := genji.OpenWith(path, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	c := &connector{
-		db:     db,
-		driver: d,
+		db:       db,
+		driver:   d,
+		readOnly: opts.ReadOnly,
 	}
 	runtime.SetFinalizer(c, (*connector).Close)
 
 	return c, nil
 }
 
+// parseDSN returns the path and the options described by the data source name.
+func parseDSN(name string) (string, *genji.Options, error) {
+	var opts genji.Options
+
+	idx := strings.LastIndexByte(name, '?')
+	if idx == -1 {
+		return name, &opts, nil
+	}
+
+	values, err := url.ParseQuery(name[idx+1:])
+	if err != nil {
+		return "", nil, errors.Wrap(err, "invalid data source name")
+	}
+
+	for k := range values {
+		v := values.Get(k)
+
+		switch k {
+		case "read_only":
+			opts.ReadOnly, err = strconv.ParseBool(v)
+		case "cache_size":
+			opts.CacheSize, err = strconv.ParseInt(v, 10, 64)
+		case "memtable_size":
+			opts.MemTableSize, err = strconv.Atoi(v)
+		case "no_sync":
+			opts.NoSync, err = strconv.ParseBool(v)
+		case "disable_wal":
+			opts.DisableWAL, err = strconv.ParseBool(v)
+		case "statement_timeout":
+			opts.StatementTimeout, err = time.ParseDuration(v)
+		case "hash_aggregate_memory_limit":
+			opts.HashAggregateMemoryLimit, err = strconv.ParseInt(v, 10, 64)
+		default:
+			return "", nil, errors.Errorf("unknown option %q", k)
+		}
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "invalid value for option %q", k)
+		}
+	}
+
+	return name[:idx], &opts, nil
+}
+
 var (
 	_ driver.Connector = (*connector)(nil)
 	_ io.Closer        = (*connector)(nil)
 )
 
+// connector opens connections sharing the same database.
+// Connections can be used concurrently, each of them running its own transactions.
 type connector struct {
 	driver driver.Driver
 
 	db *genji.DB
+	// true if the database was opened in read-only mode.
+	readOnly bool
 
 	closeOnce sync.Once
+	closed    int32
 }
 
 func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
-	return &conn{db: c.db}, nil
+	if atomic.LoadInt32(&c.closed) == 1 {
+		return nil, errors.New("database is closed")
+	}
+
+	// parameters changed with SET only apply to the connection
+	return &conn{connector: c, db: c.db.NewSession()}, nil
 }
 
 func (c *connector) Driver() driver.Driver {
@@ -71,16 +145,28 @@ func (c *connector) Driver() driver.Driver {
 func (c *connector) Close() error {
 	var err error
 	c.closeOnce.Do(func() {
+		atomic.StoreInt32(&c.closed, 1)
 		err = c.db.Close()
 	})
 	return err
 }
 
+var (
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.SessionResetter    = (*conn)(nil)
+	_ driver.Validator          = (*conn)(nil)
+)
+
 // conn represents a connection to the Genji database.
 // It implements the database/sql/driver.Conn interface.
 type conn struct {
-	db *genji.DB
-	tx *genji.Tx
+	connector *connector
+	db        *genji.DB
+	tx        *genji.Tx
+	// true if the ongoing transaction is read/write.
+	writable bool
+	closed   bool
 }
 
 // Prepare returns a prepared statement, bound to this connection.
@@ -90,32 +176,62 @@ func (c *conn) Prepare(q string) (driver.Stmt, error) {
 
 // PrepareContext returns a prepared statement, bound to this connection.
 func (c *conn) PrepareContext(ctx context.Context, q string) (driver.Stmt, error) {
-	var s *genji.Statement
-	var err error
-
-	if c.tx != nil {
-		s, err = c.tx.Prepare(q)
-	} else {
-		s, err = c.db.Prepare(q)
-	}
+	s, err := c.prepare(q)
 	if err != nil {
 		return nil, err
 	}
 
-	return stmt{
+	return &stmt{
+		conn: c,
+		q:    q,
+		tx:   c.tx,
 		stmt: s,
 	}, nil
 }
 
+// prepare the query within the ongoing transaction, if any.
+func (c *conn) prepare(q string) (*genji.Statement, error) {
+	if c.tx != nil {
+		return c.tx.Prepare(q)
+	}
+
+	return c.db.Prepare(q)
+}
+
 // Close closes any ongoing transaction.
 func (c *conn) Close() error {
+	c.closed = true
+
+	if c.tx != nil {
+		tx := c.tx
+		c.tx = nil
+		return tx.Rollback()
+	}
+
+	return nil
+}
+
+// ResetSession is called before the connection is reused.
+// It rolls back the transaction that may have been left open and
+// returns driver.ErrBadConn if the connection cannot be used anymore.
+func (c *conn) ResetSession(ctx context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+
 	if c.tx != nil {
-		return c.tx.Rollback()
+		return c.Rollback()
 	}
 
 	return nil
 }
 
+// IsValid reports whether the connection can be used,
+// which is not the case once it or the database is closed.
+func (c *conn) IsValid() bool {
+	return !c.closed && atomic.LoadInt32(&c.connector.closed) == 0
+}
+
 // Begin starts and returns a new transaction.
 func (c *conn) Begin() (driver.Tx, error) {
 	return c.BeginTx(context.Background(), driver.TxOptions{})
@@ -123,53 +239,110 @@ func (c *conn) Begin() (driver.Tx, error) {
 
 // BeginTx starts and returns a new transaction.
 // It uses the ReadOnly option to determine whether to start a read-only or read/write transaction.
-// If the Isolation option is non zero, an error is returned.
+// Transactions are always read-only if the database was opened in read-only mode.
+//
+// Transactions are serializable: a read/write transaction cannot run concurrently with any other
+// transaction, while read-only transactions can run concurrently with each other.
+// Any isolation level up to sql.LevelSerializable is thus supported, higher levels return an error.
+// BeginTx blocks until the transaction can be started or ctx is canceled.
+// Read-only transactions wait behind the read/write transactions waiting to start:
+// a goroutine holding a read-only transaction must not start another one on a different
+// connection without a deadline, as it would wait for a writer itself waiting for the first one.
 func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
-	if opts.Isolation != 0 {
-		return nil, errors.New("isolation levels are not supported")
+	if c.tx != nil {
+		return nil, errors.New("cannot begin a transaction within a transaction")
 	}
 
-	db := c.db.WithContext(ctx)
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault, sql.LevelReadUncommitted, sql.LevelReadCommitted, sql.LevelWriteCommitted,
+		sql.LevelRepeatableRead, sql.LevelSnapshot, sql.LevelSerializable:
+	default:
+		return nil, errors.Errorf("isolation level %s is not supported", sql.IsolationLevel(opts.Isolation))
+	}
 
-	// if the ReadOnly flag is explicitly specified, create a read-only transaction,
-	// otherwise create a read/write transaction.
-	var err error
-	c.tx, err = db.Begin(!opts.ReadOnly)
+	writable := !opts.ReadOnly && !c.connector.readOnly
 
-	return c, err
+	tx, err := c.db.WithContext(ctx).Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+
+	c.tx = tx
+	c.writable = writable
+	return c, nil
 }
 
+// Commit the ongoing transaction.
+// Read-only transactions have nothing to commit and are rolled back.
 func (c *conn) Commit() error {
-	err := c.tx.Commit()
+	if c.tx == nil {
+		return errors.New("no active transaction")
+	}
+
+	tx := c.tx
 	c.tx = nil
-	return err
+
+	if !c.writable {
+		return tx.Rollback()
+	}
+
+	return tx.Commit()
 }
 
+// Rollback the ongoing transaction.
 func (c *conn) Rollback() error {
-	err := c.tx.Rollback()
+	if c.tx == nil {
+		return errors.New("no active transaction")
+	}
+
+	tx := c.tx
 	c.tx = nil
-	return err
+	return tx.Rollback()
 }
 
 // Stmt is a prepared statement. It is bound to a Conn and not
 // used by multiple goroutines concurrently.
+// It runs within the transaction of the connection, if any, even if
+// it was prepared before the transaction was started.
 type stmt struct {
+	conn *conn
+	q    string
+
+	// transaction the statement was prepared with.
+	tx   *genji.Tx
 	stmt *genji.Statement
 }
 
+// statement returns the prepared statement, prepared again if
+// the transaction of the connection changed since.
+func (s *stmt) statement() (*genji.Statement, error) {
+	if s.tx == s.conn.tx {
+		return s.stmt, nil
+	}
+
+	st, err := s.conn.prepare(s.q)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tx = s.conn.tx
+	s.stmt = st
+	return st, nil
+}
+
 // NumInput returns the number of placeholder parameters.
-func (s stmt) NumInput() int { return -1 }
+func (s *stmt) NumInput() int { return -1 }
 
 // Exec executes a query that doesn't return rows, such
 // as an INSERT or UPDATE.
-func (s stmt) Exec(args []driver.Value) (driver.Result, error) {
-	return nil, errors.New("not implemented")
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), driverValueToNamedValues(args))
 }
 
 // CheckNamedValue has the same behaviour as driver.DefaultParameterConverter, except that
 // it allows types.Document to be passed as parameters.
 // It implements the driver.NamedValueChecker interface.
-func (s stmt) CheckNamedValue(nv *driver.NamedValue) error {
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
 	if _, ok := nv.Value.(types.Document); ok {
 		return nil
 	}
@@ -190,48 +363,69 @@ func (s stmt) CheckNamedValue(nv *driver.NamedValue) error {
 
 // ExecContext executes a query that doesn't return rows, such
 // as an INSERT or UPDATE.
-func (s stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
 
-	return result{}, s.stmt.Exec(driverNamedValueToParams(args)...)
+	st, err := s.statement()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := st.WithContext(ctx).ExecResult(driverNamedValueToParams(args)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result{res: res}, nil
 }
 
-type result struct{}
+type result struct {
+	res *genji.ExecResult
+}
 
-// LastInsertId is not supported and returns an error.
+// LastInsertId returns the key of the last inserted document if it is an integer,
+// such as the docid of tables without a primary key.
+// Otherwise, it returns an error.
 func (r result) LastInsertId() (int64, error) {
-	return 0, errors.New("not supported")
+	return r.res.LastInsertID()
 }
 
-// RowsAffected is not supported and returns an error.
+// RowsAffected returns the number of documents inserted, updated or deleted by the statement.
 func (r result) RowsAffected() (int64, error) {
-	return 0, errors.New("not supported")
+	return r.res.RowsAffected(), nil
 }
 
-func (s stmt) Query(args []driver.Value) (driver.Rows, error) {
-	return nil, errors.New("not implemented")
+// Query executes a query that may return rows, such as a
+// SELECT.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), driverValueToNamedValues(args))
 }
 
 // QueryContext executes a query that may return rows, such as a
 // SELECT.
-func (s stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
 
-	res, err := s.stmt.Query(driverNamedValueToParams(args)...)
+	st, err := s.statement()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := st.WithContext(ctx).Query(driverNamedValueToParams(args)...)
 	if err != nil {
 		return nil, err
 	}
 
 	rs := newRecordStream(res)
-	rs.fields = res.Fields()
+	rs.columns = res.Columns()
 	return rs, nil
 }
 
@@ -247,19 +441,37 @@ func driverNamedValueToParams(args []driver.NamedValue) []interface{} {
 	return params
 }
 
+func driverValueToNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{
+			Ordinal: i + 1,
+			Value:   arg,
+		}
+	}
+
+	return named
+}
+
 // Close does nothing.
-func (s stmt) Close() error {
+func (s *stmt) Close() error {
 	return nil
 }
 
 var errStop = errors.New("stop")
 
+var (
+	_ driver.RowsColumnTypeDatabaseTypeName = (*documentStream)(nil)
+	_ driver.RowsColumnTypeScanType         = (*documentStream)(nil)
+	_ driver.RowsColumnTypeNullable         = (*documentStream)(nil)
+)
+
 type documentStream struct {
 	res      *genji.Result
 	cancelFn func()
 	c        chan doc
 	wg       sync.WaitGroup
-	fields   []string
+	columns  []genji.Column
 }
 
 type doc struct {
@@ -322,7 +534,57 @@ func (rs *documentStream) iterate(ctx context.Context) {
 
 // Columns returns the fields selected by the SELECT statement.
 func (rs *documentStream) Columns() []string {
-	return rs.res.Fields()
+	fields := make([]string, len(rs.columns))
+	for i := range rs.columns {
+		fields[i] = rs.columns[i].Name
+	}
+
+	return fields
+}
+
+// ColumnTypeDatabaseTypeName returns the type of the column, in uppercase.
+// It returns an empty string if the type of the column is unknown.
+func (rs *documentStream) ColumnTypeDatabaseTypeName(index int) string {
+	return strings.ToUpper(rs.columns[index].Type.String())
+}
+
+var (
+	documentType = reflect.TypeOf((*types.Document)(nil)).Elem()
+	arrayType    = reflect.TypeOf((*types.Array)(nil)).Elem()
+	anyType      = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// ColumnTypeScanType returns the Go type of the values returned for the column.
+func (rs *documentStream) ColumnTypeScanType(index int) reflect.Type {
+	switch rs.columns[index].Type {
+	case types.BoolValue:
+		return reflect.TypeOf(false)
+	case types.IntegerValue:
+		return reflect.TypeOf(int64(0))
+	case types.DoubleValue:
+		return reflect.TypeOf(float64(0))
+	case types.TextValue:
+		return reflect.TypeOf("")
+	case types.BlobValue:
+		return reflect.TypeOf([]byte(nil))
+	case types.ArrayValue:
+		return arrayType
+	case types.DocumentValue:
+		return documentType
+	}
+
+	return anyType
+}
+
+// ColumnTypeNullable reports whether the column may be null.
+// ok is false if the type of the column is unknown.
+func (rs *documentStream) ColumnTypeNullable(index int) (nullable, ok bool) {
+	c := rs.columns[index]
+	if c.Type.IsAny() {
+		return true, false
+	}
+
+	return c.Nullable, true
 }
 
 // Close closes the rows iterator.
@@ -344,14 +606,14 @@ func (rs *documentStream) Next(dest []driver.Value) error {
 		return doc.err
 	}
 
-	for i := range rs.fields {
-		if rs.fields[i] == "*" {
+	for i := range rs.columns {
+		if rs.columns[i].Name == "*" {
 			dest[i] = doc.d
 
 			continue
 		}
 
-		f, err := doc.d.GetByField(rs.fields[i])
+		f, err := doc.d.GetByField(rs.columns[i].Name)
 		if err != nil {
 			return err
 		}
@@ -3,9 +3,16 @@ package driver
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/genjidb/genji"
 	"github.com/genjidb/genji/internal/testutil/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,12 +33,18 @@ func TestDriver(t *testing.T) {
 	res, err := db.Exec("CREATE TABLE test")
 	assert.NoError(t, err)
 	n, err := res.RowsAffected()
-	assert.Error(t, err)
+	assert.NoError(t, err)
 	require.EqualValues(t, 0, n)
 
 	for i := 0; i < 10; i++ {
-		_, err = db.Exec("INSERT INTO test (a, b, c) VALUES (?, ?, ?)", i, []int{i + 1, i + 2, i + 3}, &foo{Foo: "bar"})
+		res, err = db.Exec("INSERT INTO test (a, b, c) VALUES (?, ?, ?)", i, []int{i + 1, i + 2, i + 3}, &foo{Foo: "bar"})
+		assert.NoError(t, err)
+		n, err = res.RowsAffected()
+		assert.NoError(t, err)
+		require.EqualValues(t, 1, n)
+		id, err := res.LastInsertId()
 		assert.NoError(t, err)
+		require.EqualValues(t, i+1, id)
 	}
 
 	t.Run("Wildcard", func(t *testing.T) {
@@ -282,3 +295,308 @@ func TestDriverWithTimeValues(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, now, tt)
 }
+
+func TestDriverExecResult(t *testing.T) {
+	db, err := sql.Open("genji", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE test (id TEXT PRIMARY KEY, version INT)")
+	assert.NoError(t, err)
+
+	res, err := db.Exec("INSERT INTO test (id, version) VALUES ('a', 1), ('b', 1)")
+	assert.NoError(t, err)
+	n, err := res.RowsAffected()
+	assert.NoError(t, err)
+	require.EqualValues(t, 2, n)
+	// the primary key is not an integer
+	_, err = res.LastInsertId()
+	assert.Error(t, err)
+
+	// optimistic locking
+	res, err = db.Exec("UPDATE test SET version = version + 1 WHERE id = 'a' AND version = ?", 1)
+	assert.NoError(t, err)
+	n, err = res.RowsAffected()
+	assert.NoError(t, err)
+	require.EqualValues(t, 1, n)
+
+	res, err = db.Exec("UPDATE test SET version = version + 1 WHERE id = 'a' AND version = ?", 1)
+	assert.NoError(t, err)
+	n, err = res.RowsAffected()
+	assert.NoError(t, err)
+	require.EqualValues(t, 0, n)
+
+	res, err = db.Exec("DELETE FROM test")
+	assert.NoError(t, err)
+	n, err = res.RowsAffected()
+	assert.NoError(t, err)
+	require.EqualValues(t, 2, n)
+}
+
+func TestDriverTransactions(t *testing.T) {
+	db, err := sql.Open("genji", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE test (a INT)")
+	assert.NoError(t, err)
+
+	t.Run("Isolation levels", func(t *testing.T) {
+		for _, level := range []sql.IsolationLevel{sql.LevelDefault, sql.LevelReadCommitted, sql.LevelSnapshot, sql.LevelSerializable} {
+			tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: level})
+			assert.NoError(t, err)
+			assert.NoError(t, tx.Rollback())
+		}
+
+		_, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelLinearizable})
+		require.EqualError(t, err, "isolation level Linearizable is not supported")
+	})
+
+	t.Run("Read-only", func(t *testing.T) {
+		tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+		assert.NoError(t, err)
+
+		_, err = tx.Exec("INSERT INTO test (a) VALUES (1)")
+		assert.Error(t, err)
+
+		// committing a read-only transaction ends it
+		assert.NoError(t, tx.Commit())
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		tx, err = db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Rollback())
+	})
+
+	t.Run("Concurrent transactions", func(t *testing.T) {
+		r1, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+		assert.NoError(t, err)
+		defer r1.Rollback()
+
+		done := make(chan error)
+		go func() {
+			tx, err := db.Begin()
+			if err != nil {
+				done <- err
+				return
+			}
+			_, err = tx.Exec("INSERT INTO test (a) VALUES (1)")
+			if err != nil {
+				done <- err
+				return
+			}
+			done <- tx.Commit()
+		}()
+
+		// the read/write transaction waits for r1 to end,
+		// and new transactions wait behind it
+		time.Sleep(10 * time.Millisecond)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		assert.NoError(t, r1.Rollback())
+		assert.NoError(t, <-done)
+
+		var n int
+		err = db.QueryRow("SELECT COUNT(*) FROM test").Scan(&n)
+		assert.NoError(t, err)
+		require.Equal(t, 1, n)
+	})
+
+	t.Run("Statement prepared before the transaction", func(t *testing.T) {
+		conn, err := db.Conn(context.Background())
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		st, err := conn.PrepareContext(context.Background(), "INSERT INTO test (a) VALUES (?)")
+		assert.NoError(t, err)
+		defer st.Close()
+
+		tx, err := conn.BeginTx(context.Background(), nil)
+		assert.NoError(t, err)
+
+		// the statement runs within the transaction of the connection
+		_, err = tx.Stmt(st).Exec(2)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Rollback())
+
+		_, err = st.Exec(3)
+		assert.NoError(t, err)
+
+		var n int
+		err = db.QueryRow("SELECT COUNT(*) FROM test WHERE a > 1").Scan(&n)
+		assert.NoError(t, err)
+		require.Equal(t, 1, n)
+	})
+}
+
+func TestConn(t *testing.T) {
+	c, err := sqlDriver{}.OpenConnector(":memory:")
+	assert.NoError(t, err)
+	defer c.(io.Closer).Close()
+
+	dc, err := c.Connect(context.Background())
+	assert.NoError(t, err)
+	cn := dc.(*conn)
+
+	st, err := cn.Prepare("CREATE TABLE test (a INT); INSERT INTO test (a) VALUES (?)")
+	assert.NoError(t, err)
+	res, err := st.Exec([]driver.Value{int64(1)})
+	assert.NoError(t, err)
+	n, err := res.RowsAffected()
+	assert.NoError(t, err)
+	require.EqualValues(t, 1, n)
+
+	st, err = cn.Prepare("SELECT a FROM test WHERE a = ?")
+	assert.NoError(t, err)
+	rows, err := st.Query([]driver.Value{int64(1)})
+	assert.NoError(t, err)
+	dest := make([]driver.Value, 1)
+	assert.NoError(t, rows.Next(dest))
+	require.Equal(t, int64(1), dest[0])
+	assert.NoError(t, rows.Close())
+
+	assert.NoError(t, cn.ResetSession(context.Background()))
+	require.True(t, cn.IsValid())
+
+	// parameters are set for the connection only
+	st, err = cn.Prepare("SET statement_timeout = '10ms'")
+	assert.NoError(t, err)
+	_, err = st.Exec(nil)
+	assert.NoError(t, err)
+	require.Equal(t, 10*time.Millisecond, cn.db.Session.StatementTimeout(cn.db.DB))
+
+	other, err := c.Connect(context.Background())
+	assert.NoError(t, err)
+	defer other.Close()
+	require.Zero(t, other.(*conn).db.Session.StatementTimeout(cn.db.DB))
+
+	// the connection is not valid once closed
+	assert.NoError(t, cn.Close())
+	require.False(t, cn.IsValid())
+	require.Equal(t, driver.ErrBadConn, cn.ResetSession(context.Background()))
+
+	// nor once the database is closed
+	dc, err = c.Connect(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, c.(io.Closer).Close())
+	require.False(t, dc.(*conn).IsValid())
+	_, err = c.Connect(context.Background())
+	assert.Error(t, err)
+}
+
+func TestDriverColumnTypes(t *testing.T) {
+	db, err := sql.Open("genji", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE test (id INT PRIMARY KEY, name TEXT NOT NULL, score DOUBLE)")
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO test (id, name, score, data) VALUES (1, 'foo', 1.5, [1])")
+	assert.NoError(t, err)
+
+	rows, err := db.Query("SELECT id, name, score, data, id + 1 AS n FROM test")
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	cols, err := rows.ColumnTypes()
+	assert.NoError(t, err)
+	require.Len(t, cols, 5)
+
+	tests := []struct {
+		name         string
+		databaseType string
+		scanType     reflect.Type
+		nullable     bool
+		ok           bool
+	}{
+		{"id", "INTEGER", reflect.TypeOf(int64(0)), false, true},
+		{"name", "TEXT", reflect.TypeOf(""), false, true},
+		{"score", "DOUBLE", reflect.TypeOf(float64(0)), true, true},
+		{"data", "", reflect.TypeOf((*interface{})(nil)).Elem(), true, false},
+		// the addition of integers may overflow to a double
+		{"n", "", reflect.TypeOf((*interface{})(nil)).Elem(), true, false},
+	}
+
+	for i, test := range tests {
+		require.Equal(t, test.name, cols[i].Name())
+		require.Equal(t, test.databaseType, cols[i].DatabaseTypeName(), test.name)
+		require.Equal(t, test.scanType, cols[i].ScanType(), test.name)
+		nullable, ok := cols[i].Nullable()
+		require.Equal(t, test.nullable, nullable, test.name)
+		require.Equal(t, test.ok, ok, test.name)
+	}
+
+	require.True(t, rows.Next())
+	var id, n int64
+	var name string
+	var score float64
+	var data interface{}
+	err = rows.Scan(&id, &name, &score, &data, &n)
+	assert.NoError(t, err)
+	require.Equal(t, int64(1), id)
+	require.Equal(t, "foo", name)
+	require.Equal(t, 1.5, score)
+	require.Equal(t, int64(2), n)
+}
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		opts  genji.Options
+		fails bool
+	}{
+		{":memory:", ":memory:", genji.Options{}, false},
+		{"/tmp/db", "/tmp/db", genji.Options{}, false},
+		{"/tmp/db?read_only=true&cache_size=1024", "/tmp/db", genji.Options{ReadOnly: true, CacheSize: 1024}, false},
+		{":memory:?memtable_size=2048&no_sync=1&disable_wal=true", ":memory:", genji.Options{MemTableSize: 2048, NoSync: true, DisableWAL: true}, false},
+		{"/tmp/db?statement_timeout=1m30s", "/tmp/db", genji.Options{StatementTimeout: 90 * time.Second}, false},
+		{"/tmp/db?hash_aggregate_memory_limit=4096", "/tmp/db", genji.Options{HashAggregateMemoryLimit: 4096}, false},
+		{"/tmp/db?hash_aggregate_memory_limit=4MB", "", genji.Options{}, true},
+		{"/tmp/db?read_only=foo", "", genji.Options{}, true},
+		{"/tmp/db?statement_timeout=10", "", genji.Options{}, true},
+		{"/tmp/db?foo=bar", "", genji.Options{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, opts, err := parseDSN(test.name)
+			if test.fails {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Equal(t, test.path, path)
+			require.Equal(t, test.opts, *opts)
+		})
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "genji")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "testdb")
+
+		db, err := sql.Open("genji", path)
+		assert.NoError(t, err)
+		_, err = db.Exec("CREATE TABLE test")
+		assert.NoError(t, err)
+		assert.NoError(t, db.Close())
+
+		db, err = sql.Open("genji", path+"?read_only=true")
+		assert.NoError(t, err)
+		defer db.Close()
+
+		var n int
+		err = db.QueryRow("SELECT COUNT(*) FROM test").Scan(&n)
+		assert.NoError(t, err)
+		require.Equal(t, 0, n)
+
+		_, err = db.Exec("INSERT INTO test (a) VALUES (1)")
+		assert.Error(t, err)
+	})
+}
@@ -15,6 +15,17 @@ var (
 	// ErrDuplicateDocument is returned when another document is already associated with a given key, primary key,
 	// or if there is a unique index violation.
 	ErrDuplicateDocument = errors.New("duplicate document")
+
+	// ErrSubscriptionClosed is returned when reading the changes of a closed subscription.
+	ErrSubscriptionClosed = errors.New("subscription closed")
+
+	// ErrSubscriberLagged is returned when reading the changes of a subscription
+	// that was closed because it didn't keep up with the changes.
+	ErrSubscriberLagged = errors.New("subscriber lagged")
+
+	// ErrDatabaseClosed is returned when reading the changes of a subscription
+	// that was closed because its database was closed.
+	ErrDatabaseClosed = errors.New("database closed")
 )
 
 // AlreadyExistsError is returned when to create a table, an index or a sequence
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"sync/atomic"
 
 	"github.com/cockroachdb/errors"
 	"github.com/genjidb/genji/document"
@@ -15,17 +16,30 @@ import (
 
 // System tables
 const (
-	CatalogTableName                      = InternalPrefix + "catalog"
-	CatalogTableNamespace  kv.NamespaceID = 1
-	SequenceTableName                     = InternalPrefix + "sequence"
-	SequenceTableNamespace kv.NamespaceID = 2
+	CatalogTableName                          = InternalPrefix + "catalog"
+	CatalogTableNamespace      kv.NamespaceID = 1
+	SequenceTableName                         = InternalPrefix + "sequence"
+	SequenceTableNamespace     kv.NamespaceID = 2
+	StatisticsTableName                       = InternalPrefix + "statistics"
+	StatisticsTableNamespace   kv.NamespaceID = 3
+	ChangeLogTableName                        = InternalPrefix + "changes"
+	ChangeLogTableNamespace    kv.NamespaceID = 4
+	SubscriptionTableName                     = InternalPrefix + "subscriptions"
+	SubscriptionTableNamespace kv.NamespaceID = 5
 )
 
+// MigrationsTableName is the name of the table recording the migrations applied
+// by the migrate package. Unlike the other system tables, it is created with
+// a regular CREATE TABLE statement the first time a migration is applied.
+const MigrationsTableName = InternalPrefix + "migrations"
+
 // Relation types
 const (
 	RelationTableType    = "table"
 	RelationIndexType    = "index"
 	RelationSequenceType = "sequence"
+	RelationViewType     = "view"
+	RelationTriggerType  = "trigger"
 )
 
 // System sequences
@@ -33,7 +47,7 @@ const (
 	StoreSequence = InternalPrefix + "store_seq"
 )
 
-// Catalog manages all database objects such as tables, indexes and sequences.
+// Catalog manages all database objects such as tables, indexes, sequences, views and triggers.
 // It stores all these objects in memory for fast access. Any modification
 // is persisted into the __genji_catalog table.
 type Catalog struct {
@@ -48,6 +62,14 @@ func NewCatalog() *Catalog {
 	}
 }
 
+// Version returns the version of the catalog. It changes every time an object
+// of the catalog or its statistics are created, modified or deleted, even if the
+// modification is not committed yet, and when such a modification is rolled back.
+// Query plans prepared with a given version must not be used with another one.
+func (c *Catalog) Version() int64 {
+	return atomic.LoadInt64(c.Cache.version)
+}
+
 func (c *Catalog) Init(tx *Transaction) error {
 	// ensure the store sequence exists
 	return c.ensureSequenceExists(tx, &SequenceInfo{
@@ -89,13 +111,11 @@ func (c *Catalog) generateStoreName(tx *Transaction) (kv.NamespaceID, error) {
 }
 
 func (c *Catalog) GetTable(tx *Transaction, tableName string) (*Table, error) {
-	o, err := c.Cache.Get(RelationTableType, tableName)
+	ti, err := c.GetTableInfo(tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	ti := o.(*TableInfo)
-
 	s := tx.Session.GetNamespace(ti.StoreNamespace)
 
 	return &Table{
@@ -106,9 +126,41 @@ func (c *Catalog) GetTable(tx *Transaction, tableName string) (*Table, error) {
 	}, nil
 }
 
+// getOrCreateSystemTable returns the internal table described by info,
+// creating it if it doesn't exist yet.
+func (c *Catalog) getOrCreateSystemTable(tx *Transaction, info *TableInfo) (*Table, error) {
+	tb, err := c.GetTable(tx, info.TableName)
+	if err == nil || !errs.IsNotFoundError(err) {
+		return tb, err
+	}
+
+	err = c.CreateTable(tx, info.TableName, info.Clone())
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetTable(tx, info.TableName)
+}
+
 // GetTableInfo returns the table info for the given table name.
+// If the name refers to a materialized view, it returns the info of a read-only table
+// holding the stored results of the view.
 func (c *Catalog) GetTableInfo(tableName string) (*TableInfo, error) {
+	// system tables are generated from the catalog and are not stored
+	if IsSystemTable(tableName) {
+		return nil, fmt.Errorf("system table %s is read-only", tableName)
+	}
+
 	r, err := c.Cache.Get(RelationTableType, tableName)
+	if errs.IsNotFoundError(err) {
+		if v, verr := c.GetViewInfo(tableName); verr == nil && v.Materialized {
+			return &TableInfo{
+				TableName:      v.ViewName,
+				StoreNamespace: v.StoreNamespace,
+				ReadOnly:       true,
+			}, nil
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -142,6 +194,11 @@ func (c *Catalog) CreateTable(tx *Transaction, tableName string, info *TableInfo
 		return err
 	}
 
+	err = info.validateTTL()
+	if err != nil {
+		return err
+	}
+
 	if info.StoreNamespace == 0 {
 		info.StoreNamespace, err = c.generateStoreName(tx)
 		if err != nil {
@@ -149,13 +206,15 @@ func (c *Catalog) CreateTable(tx *Transaction, tableName string, info *TableInfo
 		}
 	}
 
-	// bind default values with catalog
+	// bind default values and generated expressions with catalog
 	for _, fc := range info.FieldConstraints {
-		if fc.DefaultValue == nil {
-			continue
+		if fc.DefaultValue != nil {
+			fc.DefaultValue.Bind(c)
 		}
 
-		fc.DefaultValue.Bind(c)
+		if fc.GeneratedExpr != nil {
+			fc.GeneratedExpr.Bind(c)
+		}
 	}
 
 	err = c.CatalogTable.Insert(tx, info)
@@ -177,6 +236,16 @@ func (c *Catalog) DropTable(tx *Transaction, tableName string) error {
 		return errors.New("cannot write to read-only table")
 	}
 
+	err = c.checkNoDependentViews(tableName, "drop")
+	if err != nil {
+		return err
+	}
+
+	err = c.deleteTableStatistics(tx, tableName)
+	if err != nil {
+		return err
+	}
+
 	for _, idx := range c.Cache.GetTableIndexes(tableName) {
 		_, err = c.Cache.Delete(tx, RelationIndexType, idx.IndexName)
 		if err != nil {
@@ -189,6 +258,13 @@ func (c *Catalog) DropTable(tx *Transaction, tableName string) error {
 		}
 	}
 
+	for _, tr := range c.GetTableTriggers(tableName) {
+		err = c.DropTrigger(tx, tr.TriggerName)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = c.Cache.Delete(tx, RelationTableType, tableName)
 	if err != nil {
 		return err
@@ -206,11 +282,15 @@ func (c *Catalog) DropTable(tx *Transaction, tableName string) error {
 // If it already exists, returns errs.ErrIndexAlreadyExists.
 func (c *Catalog) CreateIndex(tx *Transaction, info *IndexInfo) error {
 	// check if the associated table exists
-	_, err := c.GetTableInfo(info.TableName)
+	ti, err := c.GetTableInfo(info.TableName)
 	if err != nil {
 		return err
 	}
 
+	if ti.ReadOnly {
+		return errors.New("cannot create index on read-only table")
+	}
+
 	info.StoreNamespace, err = c.generateStoreName(tx)
 	if err != nil {
 		return err
@@ -282,6 +362,11 @@ func (c *Catalog) DropIndex(tx *Transaction, name string) error {
 		return err
 	}
 
+	err = c.DeleteStatistics(tx, name)
+	if err != nil {
+		return err
+	}
+
 	return c.dropIndex(tx, info)
 }
 
@@ -308,6 +393,10 @@ func (c *Catalog) AddFieldConstraint(tx *Transaction, tableName string, fc *Fiel
 		if err != nil {
 			return err
 		}
+
+		if fc.GeneratedExpr != nil {
+			fc.GeneratedExpr.Bind(c)
+		}
 	}
 
 	err = clone.TableConstraints.Merge(tcs)
@@ -315,19 +404,248 @@ func (c *Catalog) AddFieldConstraint(tx *Transaction, tableName string, fc *Fiel
 		return err
 	}
 
-	err = c.Cache.Replace(tx, clone)
+	return c.replaceTableInfo(tx, clone)
+}
+
+// DropFieldConstraint removes the constraints of a top-level field, and of its children, from the table.
+// Fields used by the primary key or by an index cannot be dropped.
+func (c *Catalog) DropFieldConstraint(tx *Transaction, tableName string, field string) error {
+	ti, err := c.GetTableInfo(tableName)
+	if err != nil {
+		return err
+	}
+
+	for _, tc := range ti.TableConstraints {
+		if (tc.PrimaryKey || tc.Unique) && pathsUseField(tc.Paths, field) {
+			return fmt.Errorf("cannot drop field %q: it is used by the constraint %s", field, tc)
+		}
+	}
+
+	for _, info := range c.Cache.GetTableIndexes(tableName) {
+		if pathsUseField(info.Paths, field) {
+			return fmt.Errorf("cannot drop field %q: it is used by the index %s", field, info.IndexName)
+		}
+	}
+
+	if ti.TTLPath != nil && pathsUseField([]document.Path{ti.TTLPath}, field) {
+		return fmt.Errorf("cannot drop field %q: it is used by the TTL of the table", field)
+	}
+
+	var fcs FieldConstraints
+	for _, fc := range ti.FieldConstraints {
+		if fc.IsInferred || fc.Path[0].FieldName == field {
+			continue
+		}
+
+		cp := *fc
+		fcs = append(fcs, &cp)
+	}
+
+	clone := ti.Clone()
+	// constraints inferred from the dropped ones are removed as well
+	clone.FieldConstraints, err = NewFieldConstraints(fcs)
+	if err != nil {
+		return err
+	}
+
+	return c.replaceTableInfo(tx, clone)
+}
+
+// RenameFieldConstraint renames a top-level field in the constraints of the table
+// and in the indexes using it.
+func (c *Catalog) RenameFieldConstraint(tx *Transaction, tableName string, field, newName string) error {
+	ti, err := c.GetTableInfo(tableName)
+	if err != nil {
+		return err
+	}
+
+	for _, fc := range ti.FieldConstraints {
+		if fc.Path[0].FieldName == newName {
+			return errors.WithStack(errs.AlreadyExistsError{Name: newName})
+		}
+	}
+
+	clone := ti.Clone()
+	for i, fc := range clone.FieldConstraints {
+		cp := *fc
+		cp.Path = renamePathField(fc.Path, field, newName)
+		cp.InferredBy = nil
+		for _, by := range fc.InferredBy {
+			cp.InferredBy = append(cp.InferredBy, renamePathField(by, field, newName))
+		}
+		clone.FieldConstraints[i] = &cp
+	}
+
+	for i, tc := range clone.TableConstraints {
+		cp := *tc
+		cp.Paths = renamePathsField(tc.Paths, field, newName)
+		clone.TableConstraints[i] = &cp
+	}
+
+	clone.TTLPath = renamePathField(ti.TTLPath, field, newName)
+
+	err = c.replaceTableInfo(tx, clone)
+	if err != nil {
+		return err
+	}
+
+	// the values stored in the indexes don't change, only their paths
+	for _, info := range c.Cache.GetTableIndexes(tableName) {
+		if !pathsUseField(info.Paths, field) {
+			continue
+		}
+
+		idxClone := info.Clone()
+		idxClone.Paths = renamePathsField(info.Paths, field, newName)
+		idxClone.Owner.Paths = renamePathsField(info.Owner.Paths, field, newName)
+
+		err = c.Cache.Replace(tx, idxClone)
+		if err != nil {
+			return err
+		}
+
+		err = c.CatalogTable.Replace(tx, idxClone.IndexName, idxClone)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AlterFieldConstraintType sets the type of the field constraint of the given path,
+// creating the constraint if it doesn't exist.
+// The type of fields used by the primary key and of inferred constraints cannot be altered.
+func (c *Catalog) AlterFieldConstraintType(tx *Transaction, tableName string, path document.Path, tp types.ValueType) error {
+	ti, err := c.GetTableInfo(tableName)
+	if err != nil {
+		return err
+	}
+
+	// changing the type of the primary key would change the key of the documents
+	if pk := ti.GetPrimaryKey(); pk != nil {
+		for _, pp := range pk.Paths {
+			if pathHasPrefix(pp, path) || pathHasPrefix(path, pp) {
+				return fmt.Errorf("cannot alter type of field %q: it is used by the primary key", path)
+			}
+		}
+	}
+
+	var fcs FieldConstraints
+	found := false
+	for _, fc := range ti.FieldConstraints {
+		if fc.Path.IsEqual(path) {
+			if fc.IsInferred {
+				return fmt.Errorf("cannot alter type of field %q: its type is inferred by %s", path, document.Paths(fc.InferredBy))
+			}
+
+			cp := *fc
+			cp.Type = tp
+			fcs = append(fcs, &cp)
+			found = true
+			continue
+		}
+
+		if fc.IsInferred {
+			continue
+		}
+
+		cp := *fc
+		fcs = append(fcs, &cp)
+	}
+
+	if !found {
+		fcs = append(fcs, &FieldConstraint{
+			Path: path,
+			Type: tp,
+		})
+	}
+
+	clone := ti.Clone()
+	clone.FieldConstraints, err = NewFieldConstraints(fcs)
+	if err != nil {
+		return err
+	}
+
+	return c.replaceTableInfo(tx, clone)
+}
+
+func (c *Catalog) replaceTableInfo(tx *Transaction, ti *TableInfo) error {
+	err := ti.validateTTL()
+	if err != nil {
+		return err
+	}
+
+	err = c.Cache.Replace(tx, ti)
 	if err != nil {
 		return err
 	}
 
-	return c.CatalogTable.Replace(tx, tableName, clone)
+	return c.CatalogTable.Replace(tx, ti.TableName, ti)
+}
+
+// pathsUseField returns whether one of the paths starts with the given top-level field.
+func pathsUseField(paths []document.Path, field string) bool {
+	for _, p := range paths {
+		if len(p) > 0 && p[0].FieldName == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pathHasPrefix returns whether p starts with all the fragments of prefix.
+func pathHasPrefix(p, prefix document.Path) bool {
+	if len(prefix) > len(p) {
+		return false
+	}
+
+	return p[:len(prefix)].IsEqual(prefix)
+}
+
+// renamePathField returns a copy of the path whose top-level field is renamed,
+// if it matches the given field.
+func renamePathField(p document.Path, field, newName string) document.Path {
+	if len(p) == 0 || p[0].FieldName != field {
+		return p
+	}
+
+	cp := p.Clone()
+	cp[0].FieldName = newName
+	return cp
+}
+
+func renamePathsField(paths []document.Path, field, newName string) []document.Path {
+	if paths == nil {
+		return nil
+	}
+
+	renamed := make([]document.Path, len(paths))
+	for i, p := range paths {
+		renamed[i] = renamePathField(p, field, newName)
+	}
+
+	return renamed
 }
 
 // RenameTable renames a table.
 // If it doesn't exist, it returns errs.ErrTableNotFound.
 func (c *Catalog) RenameTable(tx *Transaction, oldName, newName string) error {
+	// views refer to the table by its name
+	err := c.checkNoDependentViews(oldName, "rename")
+	if err != nil {
+		return err
+	}
+
+	// Statistics are bound to the table name, they must be collected again.
+	err = c.deleteTableStatistics(tx, oldName)
+	if err != nil {
+		return err
+	}
+
 	// Delete the old table info.
-	err := c.CatalogTable.Delete(tx, oldName)
+	err = c.CatalogTable.Delete(tx, oldName)
 	if errors.Is(err, errs.ErrDocumentNotFound) {
 		return errors.WithStack(errs.NotFoundError{Name: oldName})
 	}
@@ -404,6 +722,21 @@ func (c *Catalog) RenameTable(tx *Transaction, oldName, newName string) error {
 		}
 	}
 
+	for _, tr := range c.GetTableTriggers(oldName) {
+		clone := tr.Clone()
+		clone.TableName = newName
+
+		err = c.Cache.Replace(tx, clone)
+		if err != nil {
+			return err
+		}
+
+		err = c.CatalogTable.Replace(tx, clone.TriggerName, clone)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -443,6 +776,48 @@ func (c *Catalog) CreateSequence(tx *Transaction, info *SequenceInfo) error {
 	return seq.Init(tx, c)
 }
 
+// AlterSequence replaces the options of a sequence.
+// If restart is not nil, the next value returned by the sequence is restart.
+func (c *Catalog) AlterSequence(tx *Transaction, name string, info *SequenceInfo, restart *int64) error {
+	seq, err := c.GetSequence(name)
+	if err != nil {
+		return err
+	}
+
+	clone := seq.Clone()
+	clone.Info = info
+
+	// store the current value, the next lease will be computed
+	// using the new options.
+	err = clone.Release(tx, c)
+	if err != nil {
+		return err
+	}
+
+	if restart != nil {
+		// the sequence is set to the value preceding restart
+		prev := *restart - info.IncrementBy
+		if (info.IncrementBy > 0) != (prev < *restart) {
+			return fmt.Errorf("cannot restart sequence %s at %d", name, *restart)
+		}
+
+		err = clone.SetLease(tx, c, name, prev)
+		if err != nil {
+			return err
+		}
+
+		clone.CurrentValue = &prev
+		clone.Cached = info.Cache
+	}
+
+	err = c.Cache.Replace(tx, clone)
+	if err != nil {
+		return err
+	}
+
+	return c.CatalogTable.Replace(tx, name, clone)
+}
+
 // DropSequence deletes a sequence from the catalog.
 func (c *Catalog) DropSequence(tx *Transaction, name string) error {
 	r, err := c.Cache.Delete(tx, RelationSequenceType, name)
@@ -464,6 +839,151 @@ func (c *Catalog) ListSequences() []string {
 	return c.Cache.ListObjects(RelationSequenceType)
 }
 
+// GetViewInfo returns the view info for the given view name.
+func (c *Catalog) GetViewInfo(name string) (*ViewInfo, error) {
+	r, err := c.Cache.Get(RelationViewType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.(*ViewInfo), nil
+}
+
+// CreateView creates a view with the given name.
+// If the view is materialized, a store is associated with it.
+func (c *Catalog) CreateView(tx *Transaction, info *ViewInfo) error {
+	if info.ViewName == "" {
+		return errors.New("view name required")
+	}
+
+	if info.Query == nil {
+		return errors.New("view query required")
+	}
+
+	var err error
+	if info.Materialized && info.StoreNamespace == 0 {
+		info.StoreNamespace, err = c.generateStoreName(tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.Cache.Add(tx, info)
+	if err != nil {
+		return err
+	}
+
+	return c.CatalogTable.Insert(tx, info)
+}
+
+// DropView deletes a view from the catalog.
+// If the view is materialized, its stored results are deleted as well.
+func (c *Catalog) DropView(tx *Transaction, name string) error {
+	_, err := c.GetViewInfo(name)
+	if errs.IsNotFoundError(err) {
+		if _, terr := c.GetTableInfo(name); terr == nil {
+			return errors.Errorf("%s is not a view", name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	err = c.checkNoDependentViews(name, "drop")
+	if err != nil {
+		return err
+	}
+
+	r, err := c.Cache.Delete(tx, RelationViewType, name)
+	if err != nil {
+		return err
+	}
+
+	err = c.CatalogTable.Delete(tx, name)
+	if err != nil {
+		return err
+	}
+
+	info := r.(*ViewInfo)
+	if !info.Materialized {
+		return nil
+	}
+
+	return tx.Session.GetNamespace(info.StoreNamespace).Truncate()
+}
+
+// checkNoDependentViews returns an error if a view reads from the given table or view.
+// The action is the one described by the error, e.g. "drop".
+func (c *Catalog) checkNoDependentViews(name, action string) error {
+	for _, viewName := range c.ListViews() {
+		v, err := c.GetViewInfo(viewName)
+		if err != nil {
+			return err
+		}
+
+		for _, rel := range v.Query.ReadsFrom() {
+			if rel == name {
+				return errors.Errorf("cannot %s %s: view %s depends on it", action, name, viewName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListViews returns all view names sorted lexicographically.
+func (c *Catalog) ListViews() []string {
+	return c.Cache.ListObjects(RelationViewType)
+}
+
+// GetTriggerInfo returns the trigger info for the given trigger name.
+func (c *Catalog) GetTriggerInfo(name string) (*TriggerInfo, error) {
+	r, err := c.Cache.Get(RelationTriggerType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.(*TriggerInfo), nil
+}
+
+// CreateTrigger creates a trigger on the table specified in the trigger info.
+func (c *Catalog) CreateTrigger(tx *Transaction, info *TriggerInfo) error {
+	if info.Action == nil {
+		return errors.New("trigger action required")
+	}
+
+	ti, err := c.GetTableInfo(info.TableName)
+	if err != nil {
+		return err
+	}
+
+	if ti.ReadOnly {
+		return errors.New("cannot create trigger on read-only table")
+	}
+
+	err = c.Cache.Add(tx, info)
+	if err != nil {
+		return err
+	}
+
+	return c.CatalogTable.Insert(tx, info)
+}
+
+// DropTrigger deletes a trigger from the catalog.
+func (c *Catalog) DropTrigger(tx *Transaction, name string) error {
+	_, err := c.Cache.Delete(tx, RelationTriggerType, name)
+	if err != nil {
+		return err
+	}
+
+	return c.CatalogTable.Delete(tx, name)
+}
+
+// GetTableTriggers returns the triggers of the given table, sorted by name.
+func (c *Catalog) GetTableTriggers(tableName string) []*TriggerInfo {
+	return c.Cache.GetTableTriggers(tableName)
+}
+
 type Relation interface {
 	Type() string
 	Name() string
@@ -472,20 +992,32 @@ type Relation interface {
 }
 
 type catalogCache struct {
-	tables    map[string]Relation
-	indexes   map[string]Relation
-	sequences map[string]Relation
+	tables     map[string]Relation
+	indexes    map[string]Relation
+	sequences  map[string]Relation
+	views      map[string]Relation
+	triggers   map[string]Relation
+	statistics map[string]*Statistics
+
+	// incremented every time the cache is modified,
+	// including when a modification is rolled back.
+	// It is shared with the clones of the cache.
+	version *int64
 }
 
 func newCatalogCache() *catalogCache {
 	return &catalogCache{
-		tables:    make(map[string]Relation),
-		indexes:   make(map[string]Relation),
-		sequences: make(map[string]Relation),
+		tables:     make(map[string]Relation),
+		indexes:    make(map[string]Relation),
+		sequences:  make(map[string]Relation),
+		views:      make(map[string]Relation),
+		triggers:   make(map[string]Relation),
+		statistics: make(map[string]*Statistics),
+		version:    new(int64),
 	}
 }
 
-func (c *catalogCache) Load(tables []TableInfo, indexes []IndexInfo, sequences []Sequence) {
+func (c *catalogCache) Load(tables []TableInfo, indexes []IndexInfo, sequences []Sequence, views []ViewInfo, triggers []TriggerInfo) {
 	for i := range tables {
 		c.tables[tables[i].TableName] = &tables[i]
 	}
@@ -497,6 +1029,14 @@ func (c *catalogCache) Load(tables []TableInfo, indexes []IndexInfo, sequences [
 	for i := range sequences {
 		c.sequences[sequences[i].Info.Name] = &sequences[i]
 	}
+
+	for i := range views {
+		c.views[views[i].ViewName] = &views[i]
+	}
+
+	for i := range triggers {
+		c.triggers[triggers[i].TriggerName] = &triggers[i]
+	}
 }
 
 // TODO put in tests
@@ -512,11 +1052,26 @@ func (c *catalogCache) Clone() *catalogCache {
 	for k, v := range c.sequences {
 		clone.sequences[k] = v
 	}
+	for k, v := range c.views {
+		clone.views[k] = v
+	}
+	for k, v := range c.triggers {
+		clone.triggers[k] = v
+	}
+	for k, v := range c.statistics {
+		clone.statistics[k] = v
+	}
+	clone.version = c.version
 
 	return clone
 }
 
 func (c *catalogCache) objectExists(name string) bool {
+	// system table names are reserved
+	if IsSystemTable(name) {
+		return true
+	}
+
 	// checking if table exists with the same name
 	if _, ok := c.tables[name]; ok {
 		return true
@@ -532,6 +1087,16 @@ func (c *catalogCache) objectExists(name string) bool {
 		return true
 	}
 
+	// checking if view exists with the same name
+	if _, ok := c.views[name]; ok {
+		return true
+	}
+
+	// checking if trigger exists with the same name
+	if _, ok := c.triggers[name]; ok {
+		return true
+	}
+
 	return false
 }
 
@@ -558,6 +1123,10 @@ func (c *catalogCache) getMapByType(tp string) map[string]Relation {
 		return c.indexes
 	case RelationSequenceType:
 		return c.sequences
+	case RelationViewType:
+		return c.views
+	case RelationTriggerType:
+		return c.triggers
 	}
 
 	panic(fmt.Sprintf("unknown catalog object type %q", tp))
@@ -579,9 +1148,11 @@ func (c *catalogCache) Add(tx *Transaction, o Relation) error {
 
 	m := c.getMapByType(o.Type())
 	m[name] = o
+	c.bumpVersion()
 
 	tx.OnRollbackHooks = append(tx.OnRollbackHooks, func() {
 		delete(m, name)
+		c.bumpVersion()
 	})
 
 	return nil
@@ -596,9 +1167,11 @@ func (c *catalogCache) Replace(tx *Transaction, o Relation) error {
 	}
 
 	m[o.Name()] = o
+	c.bumpVersion()
 
 	tx.OnRollbackHooks = append(tx.OnRollbackHooks, func() {
 		m[o.Name()] = old
+		c.bumpVersion()
 	})
 
 	return nil
@@ -613,14 +1186,22 @@ func (c *catalogCache) Delete(tx *Transaction, tp, name string) (Relation, error
 	}
 
 	delete(m, name)
+	c.bumpVersion()
 
 	tx.OnRollbackHooks = append(tx.OnRollbackHooks, func() {
 		m[name] = o
+		c.bumpVersion()
 	})
 
 	return o, nil
 }
 
+// bumpVersion invalidates the plans prepared with
+// the previous content of the cache.
+func (c *catalogCache) bumpVersion() {
+	atomic.AddInt64(c.version, 1)
+}
+
 func (c *catalogCache) Get(tp, name string) (Relation, error) {
 	m := c.getMapByType(tp)
 
@@ -644,6 +1225,37 @@ func (c *catalogCache) ListObjects(tp string) []string {
 	return list
 }
 
+func (c *catalogCache) setStatistics(tx *Transaction, s *Statistics) {
+	old, ok := c.statistics[s.Name]
+
+	c.statistics[s.Name] = s
+	c.bumpVersion()
+
+	tx.OnRollbackHooks = append(tx.OnRollbackHooks, func() {
+		if ok {
+			c.statistics[s.Name] = old
+		} else {
+			delete(c.statistics, s.Name)
+		}
+		c.bumpVersion()
+	})
+}
+
+func (c *catalogCache) deleteStatistics(tx *Transaction, name string) {
+	old, ok := c.statistics[name]
+	if !ok {
+		return
+	}
+
+	delete(c.statistics, name)
+	c.bumpVersion()
+
+	tx.OnRollbackHooks = append(tx.OnRollbackHooks, func() {
+		c.statistics[name] = old
+		c.bumpVersion()
+	})
+}
+
 func (c *catalogCache) GetTableIndexes(tableName string) []*IndexInfo {
 	var indexes []*IndexInfo
 	for _, o := range c.indexes {
@@ -657,6 +1269,23 @@ func (c *catalogCache) GetTableIndexes(tableName string) []*IndexInfo {
 	return indexes
 }
 
+func (c *catalogCache) GetTableTriggers(tableName string) []*TriggerInfo {
+	var triggers []*TriggerInfo
+	for _, o := range c.triggers {
+		tr := o.(*TriggerInfo)
+		if tr.TableName != tableName {
+			continue
+		}
+		triggers = append(triggers, tr)
+	}
+
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].TriggerName < triggers[j].TriggerName
+	})
+
+	return triggers
+}
+
 type CatalogStore struct {
 	Catalog *Catalog
 	info    *TableInfo
@@ -779,6 +1408,10 @@ func relationToDocument(r Relation) types.Document {
 		return indexInfoToDocument(t)
 	case *Sequence:
 		return sequenceInfoToDocument(t.Info)
+	case *ViewInfo:
+		return viewInfoToDocument(t)
+	case *TriggerInfo:
+		return triggerInfoToDocument(t)
 	}
 
 	panic(fmt.Sprintf("objectToDocument: unknown type %q", r.Type()))
@@ -824,6 +1457,28 @@ func sequenceInfoToDocument(seq *SequenceInfo) types.Document {
 	return buf
 }
 
+func viewInfoToDocument(v *ViewInfo) types.Document {
+	buf := document.NewFieldBuffer()
+	buf.Add("name", types.NewTextValue(v.ViewName))
+	buf.Add("type", types.NewTextValue(RelationViewType))
+	if v.Materialized {
+		buf.Add("namespace", types.NewIntegerValue(int64(v.StoreNamespace)))
+	}
+	buf.Add("sql", types.NewTextValue(v.String()))
+
+	return buf
+}
+
+func triggerInfoToDocument(t *TriggerInfo) types.Document {
+	buf := document.NewFieldBuffer()
+	buf.Add("name", types.NewTextValue(t.TriggerName))
+	buf.Add("type", types.NewTextValue(RelationTriggerType))
+	buf.Add("table_name", types.NewTextValue(t.TableName))
+	buf.Add("sql", types.NewTextValue(t.String()))
+
+	return buf
+}
+
 func ownerToDocument(owner *Owner) types.Document {
 	buf := document.NewFieldBuffer().Add("table_name", types.NewTextValue(owner.TableName))
 	if owner.Paths != nil {
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/errors"
+	errs "github.com/genjidb/genji/errors"
 	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/kv"
 	"github.com/genjidb/genji/internal/query/statement"
@@ -16,19 +17,21 @@ func LoadCatalog(pdb kv.PebbleStore, c *database.Catalog) error {
 	tx := database.Transaction{
 		Session: kv.NewSession(pdb, true),
 	}
-	tables, indexes, sequences, err := loadCatalogStore(&tx, c.CatalogTable)
+	tables, indexes, sequences, views, triggers, err := loadCatalogStore(&tx, c.CatalogTable)
 	if err != nil {
 		return err
 	}
 
 	for _, tb := range tables {
-		// bind default values with catalog
+		// bind default values and generated expressions with catalog
 		for _, fc := range tb.FieldConstraints {
-			if fc.DefaultValue == nil {
-				continue
+			if fc.DefaultValue != nil {
+				fc.DefaultValue.Bind(c)
 			}
 
-			fc.DefaultValue.Bind(c)
+			if fc.GeneratedExpr != nil {
+				fc.GeneratedExpr.Bind(c)
+			}
 		}
 	}
 
@@ -40,7 +43,7 @@ func LoadCatalog(pdb kv.PebbleStore, c *database.Catalog) error {
 	tables = append(tables, *ti)
 
 	// load tables and indexes first
-	c.Cache.Load(tables, indexes, nil)
+	c.Cache.Load(tables, indexes, nil, views, triggers)
 
 	if len(sequences) > 0 {
 		var seqList []database.Sequence
@@ -49,12 +52,42 @@ func LoadCatalog(pdb kv.PebbleStore, c *database.Catalog) error {
 			return err
 		}
 
-		c.Cache.Load(nil, nil, seqList)
+		c.Cache.Load(nil, nil, seqList, nil, nil)
+	}
+
+	stats, err := loadStatistics(&tx, c)
+	if err != nil {
+		return err
 	}
+	c.LoadStatistics(stats)
 
 	return nil
 }
 
+func loadStatistics(tx *database.Transaction, c *database.Catalog) ([]*database.Statistics, error) {
+	tb, err := c.GetTable(tx, database.StatisticsTableName)
+	if err != nil {
+		if errs.IsNotFoundError(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var stats []*database.Statistics
+	err = tb.IterateOnRange(nil, false, func(key tree.Key, d types.Document) error {
+		s, err := database.StatisticsFromDocument(d)
+		if err != nil {
+			return err
+		}
+
+		stats = append(stats, s)
+		return nil
+	})
+
+	return stats, err
+}
+
 func loadSequences(tx *database.Transaction, c *database.Catalog, info []database.SequenceInfo) ([]database.Sequence, error) {
 	tb, err := c.GetTable(tx, database.SequenceTableName)
 	if err != nil {
@@ -89,7 +122,7 @@ func loadSequences(tx *database.Transaction, c *database.Catalog, info []databas
 	return sequences, nil
 }
 
-func loadCatalogStore(tx *database.Transaction, s *database.CatalogStore) (tables []database.TableInfo, indexes []database.IndexInfo, sequences []database.SequenceInfo, err error) {
+func loadCatalogStore(tx *database.Transaction, s *database.CatalogStore) (tables []database.TableInfo, indexes []database.IndexInfo, sequences []database.SequenceInfo, views []database.ViewInfo, triggers []database.TriggerInfo, err error) {
 	tb := s.Table(tx)
 
 	err = tb.IterateOnRange(nil, false, func(key tree.Key, d types.Document) error {
@@ -118,6 +151,18 @@ func loadCatalogStore(tx *database.Transaction, s *database.CatalogStore) (table
 				return err
 			}
 			sequences = append(sequences, *i)
+		case database.RelationViewType:
+			v, err := viewInfoFromDocument(d)
+			if err != nil {
+				return err
+			}
+			views = append(views, *v)
+		case database.RelationTriggerType:
+			t, err := triggerInfoFromDocument(d)
+			if err != nil {
+				return err
+			}
+			triggers = append(triggers, *t)
 		}
 
 		return nil
@@ -228,6 +273,54 @@ func sequenceInfoFromDocument(d types.Document) (*database.SequenceInfo, error)
 	return &i, nil
 }
 
+func viewInfoFromDocument(d types.Document) (*database.ViewInfo, error) {
+	s, err := d.GetByField("sql")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := parser.NewParser(strings.NewReader(s.V().(string))).ParseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	v := stmt.(*statement.CreateViewStmt).Info
+
+	if !v.Materialized {
+		return &v, nil
+	}
+
+	ns, err := d.GetByField("namespace")
+	if err != nil {
+		return nil, err
+	}
+
+	storeNamespace := ns.V().(int64)
+	if storeNamespace <= 0 {
+		return nil, errors.Errorf("invalid store namespace: %v", storeNamespace)
+	}
+
+	v.StoreNamespace = kv.NamespaceID(storeNamespace)
+
+	return &v, nil
+}
+
+func triggerInfoFromDocument(d types.Document) (*database.TriggerInfo, error) {
+	s, err := d.GetByField("sql")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := parser.NewParser(strings.NewReader(s.V().(string))).ParseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	t := stmt.(*statement.CreateTriggerStmt).Info
+
+	return &t, nil
+}
+
 func ownerFromDocument(d types.Document) (*database.Owner, error) {
 	var owner database.Owner
 
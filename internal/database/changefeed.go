@@ -0,0 +1,920 @@
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/document"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/internal/tree"
+	"github.com/genjidb/genji/types"
+	"github.com/genjidb/genji/types/encoding"
+)
+
+// ChangeType describes how a document was modified.
+type ChangeType uint8
+
+// Change types
+const (
+	ChangeInsert ChangeType = iota + 1
+	ChangeUpdate
+	ChangeDelete
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeInsert:
+		return "insert"
+	case ChangeUpdate:
+		return "update"
+	case ChangeDelete:
+		return "delete"
+	}
+
+	return fmt.Sprintf("ChangeType(%d)", uint8(t))
+}
+
+// A Change describes the modification of a document by a committed transaction.
+type Change struct {
+	// Commit sequence of the transaction. All the changes of a transaction
+	// share the same sequence, which increases with every transaction
+	// that modifies at least one document.
+	Seq   int64
+	Table string
+	Type  ChangeType
+	Key   tree.Key
+	// Document before the change, nil for insertions.
+	Before types.Document
+	// Document after the change, nil for deletions.
+	After types.Document
+}
+
+var changeLogTableInfo = &TableInfo{
+	TableName:      ChangeLogTableName,
+	StoreNamespace: ChangeLogTableNamespace,
+	FieldConstraints: []*FieldConstraint{
+		{
+			Path:      document.NewPath("seq"),
+			Type:      types.IntegerValue,
+			IsNotNull: true,
+		},
+		{
+			Path:      document.NewPath("n"),
+			Type:      types.IntegerValue,
+			IsNotNull: true,
+		},
+		{
+			Path:      document.NewPath("table_name"),
+			Type:      types.TextValue,
+			IsNotNull: true,
+		},
+		{
+			Path:      document.NewPath("type"),
+			Type:      types.IntegerValue,
+			IsNotNull: true,
+		},
+		{
+			Path:      document.NewPath("pk"),
+			Type:      types.BlobValue,
+			IsNotNull: true,
+		},
+		{
+			Path: document.NewPath("before"),
+			Type: types.DocumentValue,
+		},
+		{
+			Path: document.NewPath("after"),
+			Type: types.DocumentValue,
+		},
+	},
+	TableConstraints: []*TableConstraint{
+		{
+			Paths: []document.Path{
+				document.NewPath("seq"),
+				document.NewPath("n"),
+			},
+			PrimaryKey: true,
+		},
+	},
+}
+
+var subscriptionTableInfo = &TableInfo{
+	TableName:      SubscriptionTableName,
+	StoreNamespace: SubscriptionTableNamespace,
+	FieldConstraints: []*FieldConstraint{
+		{
+			Path:      document.NewPath("name"),
+			Type:      types.TextValue,
+			IsNotNull: true,
+		},
+		{
+			Path:      document.NewPath("seq"),
+			Type:      types.IntegerValue,
+			IsNotNull: true,
+		},
+	},
+	TableConstraints: []*TableConstraint{
+		{
+			Paths: []document.Path{
+				document.NewPath("name"),
+			},
+			PrimaryKey: true,
+		},
+	},
+}
+
+// DefaultMaxPendingChanges is the default maximum number of changes
+// buffered for a subscriber.
+const DefaultMaxPendingChanges = 10000
+
+// ChangeFeed dispatches the changes made by committed transactions
+// to the subscribers.
+// While at least one durable subscription exists, the changes are also written
+// to the change log, in the same transaction, so that durable subscriptions
+// can resume from their last acknowledged position, even after a restart.
+type ChangeFeed struct {
+	// Maximum number of changes buffered for a subscriber. Subscribers that
+	// exceed it are closed with errs.ErrSubscriberLagged. If zero, there is no limit.
+	MaxPending int
+
+	mu sync.Mutex
+	// sequence of the last committed transaction that modified a document.
+	lastSeq     int64
+	subscribers map[*Subscriber]struct{}
+	// positions of the durable subscriptions, by name.
+	positions map[string]int64
+	// if true, the database was closed and no subscriber can be registered.
+	closed bool
+}
+
+func newChangeFeed() *ChangeFeed {
+	return &ChangeFeed{
+		MaxPending:  DefaultMaxPendingChanges,
+		subscribers: make(map[*Subscriber]struct{}),
+		positions:   make(map[string]int64),
+	}
+}
+
+// changeSet holds the changes made by a transaction.
+type changeSet struct {
+	seq int64
+	// if true, the changes are written to the change log.
+	log     bool
+	changes []*Change
+}
+
+// begin returns the change set of a new read/write transaction,
+// or nil if nobody is interested in its changes.
+func (f *ChangeFeed) begin() *changeSet {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.subscribers) == 0 && len(f.positions) == 0 {
+		return nil
+	}
+
+	// read/write transactions are serialized, there is no need
+	// to reserve the sequence
+	return &changeSet{
+		seq: f.lastSeq + 1,
+		log: len(f.positions) > 0,
+	}
+}
+
+// publish dispatches the changes of a committed transaction to the subscribers.
+func (f *ChangeFeed) publish(cs *changeSet) {
+	if len(cs.changes) == 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastSeq = cs.seq
+
+	for s := range f.subscribers {
+		if !s.push(cs.changes, f.MaxPending) {
+			// the subscriber doesn't keep up with the writers,
+			// durable subscribers can resume from their position.
+			delete(f.subscribers, s)
+			s.close(errs.ErrSubscriberLagged)
+		}
+	}
+}
+
+func (f *ChangeFeed) subscribe(s *Subscriber) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return errors.WithStack(errs.ErrDatabaseClosed)
+	}
+
+	if s.Name != "" {
+		for other := range f.subscribers {
+			if other.Name == s.Name {
+				return fmt.Errorf("subscription %q is already in use", s.Name)
+			}
+		}
+	}
+
+	f.subscribers[s] = struct{}{}
+	return nil
+}
+
+func (f *ChangeFeed) unsubscribe(s *Subscriber) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.subscribers, s)
+}
+
+// close unregisters and closes all the subscribers with errs.ErrDatabaseClosed.
+func (f *ChangeFeed) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	for s := range f.subscribers {
+		delete(f.subscribers, s)
+		s.close(errs.ErrDatabaseClosed)
+	}
+}
+
+func (f *ChangeFeed) setPosition(name string, seq int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.positions[name] = seq
+}
+
+func (f *ChangeFeed) deletePosition(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.positions, name)
+}
+
+// minPosition returns the lowest position of the durable subscriptions,
+// ignoring the one given.
+func (f *ChangeFeed) minPosition(seq int64, ignore string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for name, pos := range f.positions {
+		if name != ignore && pos < seq {
+			seq = pos
+		}
+	}
+
+	return seq
+}
+
+// A Subscriber receives the changes made to a set of tables.
+// It is not safe for concurrent use.
+type Subscriber struct {
+	// Name of the durable subscription, empty if the subscriber is not durable.
+	Name string
+	// Tables the subscriber is interested in. If empty, all the tables are.
+	Tables []string
+
+	feed    *ChangeFeed
+	mu      sync.Mutex
+	pending []*Change
+	// number of pending changes read from the change log,
+	// which don't count towards the limit of the feed.
+	backlog int
+	// error returned by Next once the subscriber is closed, if any.
+	err    error
+	notify chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newSubscriber(feed *ChangeFeed, name string, tables []string) *Subscriber {
+	return &Subscriber{
+		Name:   name,
+		Tables: tables,
+		feed:   feed,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+func (s *Subscriber) matches(tableName string) bool {
+	if len(s.Tables) == 0 {
+		return true
+	}
+
+	for _, t := range s.Tables {
+		if t == tableName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// push adds the changes of the tables the subscriber is interested in to the pending changes.
+// If that would exceed max pending changes, nothing is added and it returns false.
+// If max is zero, there is no limit.
+func (s *Subscriber) push(changes []*Change, max int) bool {
+	var matching []*Change
+	for _, c := range changes {
+		if s.matches(c.Table) {
+			matching = append(matching, c)
+		}
+	}
+
+	s.mu.Lock()
+	if max > 0 && len(s.pending)-s.backlog+len(matching) > max {
+		s.mu.Unlock()
+		return false
+	}
+	s.pending = append(s.pending, matching...)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+
+	return true
+}
+
+// Next returns the next change, waiting for a transaction to commit if necessary.
+// It returns errs.ErrSubscriptionClosed once the subscriber is closed,
+// errs.ErrSubscriberLagged if it was closed for having too many pending changes,
+// or errs.ErrDatabaseClosed if it was closed with the database.
+func (s *Subscriber) Next() (*Change, error) {
+	for {
+		select {
+		case <-s.done:
+			s.mu.Lock()
+			err := s.err
+			s.mu.Unlock()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			return nil, errors.WithStack(errs.ErrSubscriptionClosed)
+		default:
+		}
+
+		s.mu.Lock()
+		if len(s.pending) > 0 {
+			c := s.pending[0]
+			s.pending[0] = nil
+			s.pending = s.pending[1:]
+			if s.backlog > 0 {
+				s.backlog--
+			}
+			s.mu.Unlock()
+			return c, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-s.notify:
+		case <-s.done:
+		}
+	}
+}
+
+// Done returns a channel that is closed once the subscriber is closed.
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close unregisters the subscriber. Changes that were not read yet are discarded.
+// The position of a durable subscription is kept.
+func (s *Subscriber) Close() {
+	s.feed.unsubscribe(s)
+	s.close(nil)
+}
+
+// close discards the pending changes and closes the done channel.
+// Once closed, Next returns err, if not nil.
+// The subscriber must already be unregistered from the feed.
+func (s *Subscriber) close(err error) {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.err = err
+		s.pending = nil
+		s.backlog = 0
+		s.mu.Unlock()
+
+		close(s.done)
+	})
+}
+
+// Subscribe registers a subscriber that receives the changes made to the given tables
+// by the transactions committed from now on. If no table is given, the subscriber receives
+// the changes made to all the tables.
+func (db *Database) Subscribe(tables ...string) (*Subscriber, error) {
+	// wait for the current read/write transaction, if any, so that
+	// the subscriber receives the changes of every transaction that
+	// begins after this call
+	tx, err := db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = db.checkTablesExist(tables)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSubscriber(db.ChangeFeed, "", tables)
+	err = db.ChangeFeed.subscribe(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// SubscribeFrom registers a subscriber for the durable subscription called name.
+// If the subscription already exists, the subscriber first receives the changes committed
+// after its last acknowledged position, then the new ones. Otherwise, the subscription is created
+// and the subscriber receives the changes made by the transactions committed from now on.
+// Positions are acknowledged with SetSubscriptionPosition.
+func (db *Database) SubscribeFrom(name string, tables ...string) (*Subscriber, error) {
+	if name == "" {
+		return nil, errors.New("subscription name cannot be empty")
+	}
+
+	tx, err := db.Begin(true)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = db.checkTablesExist(tables)
+	if err != nil {
+		return nil, err
+	}
+
+	pos, ok, err := db.getSubscriptionPosition(tx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		db.ChangeFeed.mu.Lock()
+		pos = db.ChangeFeed.lastSeq
+		db.ChangeFeed.mu.Unlock()
+
+		// the change log must exist before the transactions record their changes
+		_, err = db.Catalog.getOrCreateSystemTable(tx, changeLogTableInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		err = db.putSubscriptionPosition(tx, name, pos)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s := newSubscriber(db.ChangeFeed, name, tables)
+
+	// no transaction can commit in the meantime: the subscriber
+	// receives every change exactly once
+	changes, err := db.readChangeLog(tx, pos)
+	if err != nil {
+		return nil, err
+	}
+	s.push(changes, 0)
+	s.backlog = len(s.pending)
+
+	err = db.ChangeFeed.subscribe(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// the position must be known before the next transaction begins
+	tx.OnCommitHooks = append(tx.OnCommitHooks, func() {
+		db.ChangeFeed.setPosition(name, pos)
+	})
+
+	err = tx.Commit()
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// SetSubscriptionPosition acknowledges every change up to the given sequence for the
+// durable subscription called name. These changes won't be delivered again and are
+// removed from the change log once every durable subscription has acknowledged them.
+func (db *Database) SetSubscriptionPosition(name string, seq int64) error {
+	tx, err := db.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	pos, ok, err := db.getSubscriptionPosition(tx, name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.WithStack(errs.NotFoundError{Name: name})
+	}
+
+	db.ChangeFeed.mu.Lock()
+	lastSeq := db.ChangeFeed.lastSeq
+	db.ChangeFeed.mu.Unlock()
+
+	if seq < pos || seq > lastSeq {
+		return fmt.Errorf("invalid position %d for subscription %q: must be between %d and %d", seq, name, pos, lastSeq)
+	}
+
+	err = db.putSubscriptionPosition(tx, name, seq)
+	if err != nil {
+		return err
+	}
+
+	err = db.pruneChangeLog(tx, db.ChangeFeed.minPosition(seq, name))
+	if err != nil {
+		return err
+	}
+
+	tx.OnCommitHooks = append(tx.OnCommitHooks, func() {
+		db.ChangeFeed.setPosition(name, seq)
+	})
+
+	return tx.Commit()
+}
+
+// DropSubscription deletes the durable subscription called name.
+// Once no durable subscription remains, the changes are not logged anymore.
+func (db *Database) DropSubscription(name string) error {
+	tx, err := db.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, ok, err := db.getSubscriptionPosition(tx, name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.WithStack(errs.NotFoundError{Name: name})
+	}
+
+	tb, err := db.Catalog.GetTable(tx, SubscriptionTableName)
+	if err != nil {
+		return err
+	}
+
+	key, err := tree.NewKey(types.NewTextValue(name))
+	if err != nil {
+		return err
+	}
+
+	err = tb.Delete(key)
+	if err != nil {
+		return err
+	}
+
+	db.ChangeFeed.mu.Lock()
+	lastSeq := db.ChangeFeed.lastSeq
+	db.ChangeFeed.mu.Unlock()
+
+	err = db.pruneChangeLog(tx, db.ChangeFeed.minPosition(lastSeq, name))
+	if err != nil {
+		return err
+	}
+
+	tx.OnCommitHooks = append(tx.OnCommitHooks, func() {
+		db.ChangeFeed.deletePosition(name)
+	})
+
+	return tx.Commit()
+}
+
+// LoadChangeFeed loads the positions of the durable subscriptions
+// and the last commit sequence from the store.
+func (db *Database) LoadChangeFeed() error {
+	tx, err := db.Begin(false)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	f := db.ChangeFeed
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tb, err := db.Catalog.GetTable(tx, SubscriptionTableName)
+	if err != nil {
+		if errs.IsNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	err = tb.IterateOnRange(nil, false, func(key tree.Key, d types.Document) error {
+		name, err := d.GetByField("name")
+		if err != nil {
+			return err
+		}
+		seq, err := d.GetByField("seq")
+		if err != nil {
+			return err
+		}
+
+		pos := seq.V().(int64)
+		f.positions[name.V().(string)] = pos
+		if pos > f.lastSeq {
+			f.lastSeq = pos
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tb, err = db.Catalog.GetTable(tx, ChangeLogTableName)
+	if err != nil {
+		if errs.IsNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	// the last entry of the change log holds the last sequence
+	err = tb.IterateOnRange(nil, true, func(key tree.Key, d types.Document) error {
+		seq, err := d.GetByField("seq")
+		if err != nil {
+			return err
+		}
+
+		if s := seq.V().(int64); s > f.lastSeq {
+			f.lastSeq = s
+		}
+		return errStopIteration
+	})
+	if errors.Is(err, errStopIteration) {
+		return nil
+	}
+
+	return err
+}
+
+var errStopIteration = errors.New("stop iteration")
+
+func (db *Database) checkTablesExist(tables []string) error {
+	for _, t := range tables {
+		if _, err := db.Catalog.GetTableInfo(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *Database) getSubscriptionPosition(tx *Transaction, name string) (int64, bool, error) {
+	tb, err := db.Catalog.GetTable(tx, SubscriptionTableName)
+	if err != nil {
+		if errs.IsNotFoundError(err) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	key, err := tree.NewKey(types.NewTextValue(name))
+	if err != nil {
+		return 0, false, err
+	}
+
+	d, err := tb.GetDocument(key)
+	if err != nil {
+		if errors.Is(err, errs.ErrDocumentNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	v, err := d.GetByField("seq")
+	if err != nil {
+		return 0, false, err
+	}
+
+	return v.V().(int64), true, nil
+}
+
+func (db *Database) putSubscriptionPosition(tx *Transaction, name string, seq int64) error {
+	tb, err := db.Catalog.getOrCreateSystemTable(tx, subscriptionTableInfo)
+	if err != nil {
+		return err
+	}
+
+	key, err := tree.NewKey(types.NewTextValue(name))
+	if err != nil {
+		return err
+	}
+
+	fb := document.NewFieldBuffer()
+	fb.Add("name", types.NewTextValue(name))
+	fb.Add("seq", types.NewIntegerValue(seq))
+
+	return tb.Tree.Put(key, types.NewDocumentValue(fb))
+}
+
+// readChangeLog returns the logged changes whose sequence is greater than seq.
+func (db *Database) readChangeLog(tx *Transaction, seq int64) ([]*Change, error) {
+	tb, err := db.Catalog.GetTable(tx, ChangeLogTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*Change
+	err = tb.IterateOnRange(nil, false, func(key tree.Key, d types.Document) error {
+		c, err := changeFromDocument(d)
+		if err != nil {
+			return err
+		}
+
+		if c.Seq > seq {
+			changes = append(changes, c)
+		}
+		return nil
+	})
+
+	return changes, err
+}
+
+// pruneChangeLog removes the logged changes whose sequence is lower than or equal to seq.
+func (db *Database) pruneChangeLog(tx *Transaction, seq int64) error {
+	tb, err := db.Catalog.GetTable(tx, ChangeLogTableName)
+	if err != nil {
+		return err
+	}
+
+	var keys []tree.Key
+	err = tb.IterateOnRange(nil, false, func(key tree.Key, d types.Document) error {
+		v, err := d.GetByField("seq")
+		if err != nil {
+			return err
+		}
+		if v.V().(int64) > seq {
+			return errStopIteration
+		}
+
+		keys = append(keys, append(tree.Key{}, key...))
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopIteration) {
+		return err
+	}
+
+	for _, k := range keys {
+		err = tb.Tree.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// capturesChanges returns true if the modifications of the table
+// must be recorded for the change feed.
+func (t *Table) capturesChanges() bool {
+	return t.Tx != nil && t.Tx.changes != nil && !strings.HasPrefix(t.Info.TableName, InternalPrefix)
+}
+
+// recordChange adds a change to the change set of the transaction
+// and writes it to the change log if necessary.
+// The documents are copied, as they may be reused by the caller.
+func (t *Table) recordChange(typ ChangeType, key tree.Key, before, after types.Document) error {
+	cs := t.Tx.changes
+
+	c := Change{
+		Seq:   cs.seq,
+		Table: t.Info.TableName,
+		Type:  typ,
+		Key:   append(tree.Key{}, key...),
+	}
+
+	var err error
+	if before != nil {
+		c.Before, err = copyDocument(before)
+		if err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		c.After, err = copyDocument(after)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cs.log {
+		tb, err := t.Catalog.GetTable(t.Tx, ChangeLogTableName)
+		if err != nil {
+			return err
+		}
+
+		k, err := tree.NewKey(types.NewIntegerValue(cs.seq), types.NewIntegerValue(int64(len(cs.changes))))
+		if err != nil {
+			return err
+		}
+
+		err = tb.Tree.Put(k, types.NewDocumentValue(changeToDocument(&c)))
+		if err != nil {
+			return err
+		}
+	}
+
+	cs.changes = append(cs.changes, &c)
+	return nil
+}
+
+func copyDocument(d types.Document) (types.Document, error) {
+	var buf bytes.Buffer
+
+	err := encoding.EncodeValue(&buf, types.NewDocumentValue(d))
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := encoding.DecodeValue(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return v.V().(types.Document), nil
+}
+
+func changeToDocument(c *Change) types.Document {
+	fb := document.NewFieldBuffer()
+	fb.Add("seq", types.NewIntegerValue(c.Seq))
+	fb.Add("table_name", types.NewTextValue(c.Table))
+	fb.Add("type", types.NewIntegerValue(int64(c.Type)))
+	fb.Add("pk", types.NewBlobValue(c.Key))
+	if c.Before != nil {
+		fb.Add("before", types.NewDocumentValue(c.Before))
+	}
+	if c.After != nil {
+		fb.Add("after", types.NewDocumentValue(c.After))
+	}
+
+	return fb
+}
+
+func changeFromDocument(d types.Document) (*Change, error) {
+	var c Change
+
+	v, err := d.GetByField("seq")
+	if err != nil {
+		return nil, err
+	}
+	c.Seq = v.V().(int64)
+
+	v, err = d.GetByField("table_name")
+	if err != nil {
+		return nil, err
+	}
+	c.Table = v.V().(string)
+
+	v, err = d.GetByField("type")
+	if err != nil {
+		return nil, err
+	}
+	c.Type = ChangeType(v.V().(int64))
+
+	v, err = d.GetByField("pk")
+	if err != nil {
+		return nil, err
+	}
+	c.Key = tree.Key(v.V().([]byte))
+
+	v, err = d.GetByField("before")
+	if err != nil && !errors.Is(err, types.ErrFieldNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		c.Before = v.V().(types.Document)
+	}
+
+	v, err = d.GetByField("after")
+	if err != nil && !errors.Is(err, types.ErrFieldNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		c.After = v.V().(types.Document)
+	}
+
+	return &c, nil
+}
@@ -19,6 +19,10 @@ type FieldConstraint struct {
 	DefaultValue TableExpression
 	IsInferred   bool
 	InferredBy   []document.Path
+
+	// GeneratedExpr is evaluated every time a document is written
+	// and its result is stored at Path.
+	GeneratedExpr TableExpression
 }
 
 // IsEqual compares f with other member by member.
@@ -46,11 +50,21 @@ func (f *FieldConstraint) IsEqual(other *FieldConstraint) bool {
 		}
 	}
 
+	if f.IsGenerated() != other.IsGenerated() {
+		return false
+	}
+
+	if f.IsGenerated() {
+		if !f.GeneratedExpr.IsEqual(other.GeneratedExpr) {
+			return false
+		}
+	}
+
 	return true
 }
 
 func (f *FieldConstraint) IsEmpty() bool {
-	return f.Type.IsAny() && !f.IsNotNull && f.DefaultValue == nil
+	return f.Type.IsAny() && !f.IsNotNull && f.DefaultValue == nil && f.GeneratedExpr == nil
 }
 
 func (f *FieldConstraint) String() string {
@@ -69,6 +83,12 @@ func (f *FieldConstraint) String() string {
 		s.WriteString(f.DefaultValue.String())
 	}
 
+	if f.IsGenerated() {
+		s.WriteString(" GENERATED ALWAYS AS (")
+		s.WriteString(f.GeneratedExpr.String())
+		s.WriteString(")")
+	}
+
 	return s.String()
 }
 
@@ -94,6 +114,11 @@ func (f *FieldConstraint) HasDefaultValue() bool {
 	return f.DefaultValue != nil
 }
 
+// IsGenerated returns whether the value of this field is computed from the rest of the document.
+func (f *FieldConstraint) IsGenerated() bool {
+	return f.GeneratedExpr != nil
+}
+
 // FieldConstraints is a list of field constraints.
 type FieldConstraints []*FieldConstraint
 
@@ -210,6 +235,11 @@ func (f *FieldConstraints) Add(newFc *FieldConstraint) error {
 				return err
 			}
 
+			err = f.validateGeneratedExpr(newFc)
+			if err != nil {
+				return err
+			}
+
 			// if both inferred, merge the InferredBy member
 			if newFc.IsInferred && c.IsInferred {
 				c.MergeInferred(newFc)
@@ -233,6 +263,11 @@ func (f *FieldConstraints) Add(newFc *FieldConstraint) error {
 		return err
 	}
 
+	err = f.validateGeneratedExpr(newFc)
+	if err != nil {
+		return err
+	}
+
 	*f = append(*f, newFc)
 	return nil
 }
@@ -273,8 +308,43 @@ func (f *FieldConstraints) validateDefaultValue(newFc *FieldConstraint) error {
 	return nil
 }
 
+func (f *FieldConstraints) validateGeneratedExpr(newFc *FieldConstraint) error {
+	if newFc.GeneratedExpr == nil {
+		return nil
+	}
+
+	// generated fields are removed from stored documents before being computed again,
+	// which is only supported for top-level fields.
+	if len(newFc.Path) != 1 {
+		return fmt.Errorf("generated fields must be top-level fields (%q)", newFc.Path)
+	}
+
+	if newFc.DefaultValue != nil {
+		return fmt.Errorf("generated field %q cannot have a default value", newFc.Path)
+	}
+
+	return nil
+}
+
 // ValidateDocument calls Convert then ensures the document validates against the field constraints.
+// Generated fields are computed and must not be present in the document.
 func (f FieldConstraints) ValidateDocument(tx *Transaction, fb *document.FieldBuffer) (*document.FieldBuffer, error) {
+	// ensure generated fields are not written directly
+	for _, fc := range f {
+		if fc.GeneratedExpr == nil {
+			continue
+		}
+
+		_, err := fc.Path.GetValueFromDocument(fb)
+		if err == nil {
+			return nil, fmt.Errorf("cannot write to generated field %q", fc.Path)
+		}
+
+		if !errors.Is(err, types.ErrFieldNotFound) {
+			return nil, err
+		}
+	}
+
 	// generate default values for all fields
 	for _, fc := range f {
 		if fc.DefaultValue == nil {
@@ -305,6 +375,28 @@ func (f FieldConstraints) ValidateDocument(tx *Transaction, fb *document.FieldBu
 		return nil, err
 	}
 
+	// compute generated fields, in order, once the other fields are converted
+	for _, fc := range f {
+		if fc.GeneratedExpr == nil {
+			continue
+		}
+
+		v, err := fc.GeneratedExpr.Eval(tx, fb)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err = f.ConvertValueAtPath(fc.Path, v, CastConversion)
+		if err != nil {
+			return nil, err
+		}
+
+		err = fb.Set(fc.Path, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// ensure no field is missing
 	for _, fc := range f {
 		if !fc.IsNotNull {
@@ -4,6 +4,8 @@ package database
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
@@ -12,6 +14,10 @@ import (
 
 const (
 	InternalPrefix = "__genji_"
+
+	// DefaultHashAggregateMemoryLimit is the default amount of memory, in bytes,
+	// a hash aggregation can use before spilling to a transient store.
+	DefaultHashAggregateMemoryLimit = 16 << 20
 )
 
 type Database struct {
@@ -26,11 +32,39 @@ type Database struct {
 	attachedTxMu        sync.Mutex
 
 	// This controls concurrency on read-only and read/write transactions.
-	txmu *sync.RWMutex
+	txLock *txLock
 
 	// Pool of reusable transient engines to use for temporary indices.
 	TransientStorePool *TransientStorePool
 
+	// Amount of memory, in bytes, a hash aggregation can use
+	// before spilling to a transient store.
+	HashAggregateMemoryLimit int64
+
+	// Delay between two deletions of the expired documents by the TTL worker.
+	TTLInterval time.Duration
+	// Maximum number of expired documents deleted by a single transaction.
+	TTLBatchSize int
+	// If set to true, table and index scans skip the expired documents
+	// that were not deleted yet.
+	HideExpiredDocuments bool
+
+	// Dispatches the changes made by the committed transactions to the subscribers.
+	ChangeFeed *ChangeFeed
+
+	// If set to true, read/write transactions cannot be opened.
+	ReadOnly bool
+	// If set to true, commits don't wait for the write-ahead log
+	// to be synced to disk.
+	NoSync bool
+
+	// maximum duration of a statement, in nanoseconds.
+	// Zero means no limit.
+	statementTimeout int64
+
+	ttlCancel context.CancelFunc
+	ttlWg     sync.WaitGroup
+
 	closeOnce sync.Once
 }
 
@@ -49,11 +83,21 @@ func New(ctx context.Context, pdb *pebble.DB, opts *pebble.Options) (*Database,
 	db := Database{
 		DB:      pdb,
 		Catalog: NewCatalog(),
-		txmu:    &sync.RWMutex{},
+		txLock:  newTxLock(),
 		TransientStorePool: &TransientStorePool{
 			pdb:  pdb,
 			opts: opts,
 		},
+		HashAggregateMemoryLimit: DefaultHashAggregateMemoryLimit,
+		TTLInterval:              DefaultTTLInterval,
+		TTLBatchSize:             DefaultTTLBatchSize,
+		ReadOnly:                 opts != nil && opts.ReadOnly,
+		ChangeFeed:               newChangeFeed(),
+	}
+
+	// read-only databases must already be initialized
+	if db.ReadOnly {
+		return &db, nil
 	}
 
 	tx, err := db.Begin(true)
@@ -104,8 +148,22 @@ func (db *Database) closeDatabase() error {
 	if tx := db.GetAttachedTx(); tx != nil {
 		_ = tx.Rollback()
 	}
-	db.txmu.Lock()
-	defer db.txmu.Unlock()
+
+	db.stopTTLWorker()
+
+	// unblock the subscribers waiting for changes
+	db.ChangeFeed.close()
+
+	// sequences can't be modified in read-only mode
+	if db.ReadOnly {
+		return nil
+	}
+
+	err := db.txLock.lock(context.Background(), true)
+	if err != nil {
+		return err
+	}
+	defer db.txLock.unlock(true)
 
 	// release all sequences
 	tx, err := db.beginTx(context.Background(), nil)
@@ -129,6 +187,19 @@ func (db *Database) closeDatabase() error {
 	return tx.Session.Commit()
 }
 
+// StatementTimeout returns the maximum duration of a statement.
+// Zero means no limit.
+func (db *Database) StatementTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&db.statementTimeout))
+}
+
+// SetStatementTimeout sets the maximum duration of the statements that will be run
+// from now on. Statements running for longer are interrupted and return
+// context.DeadlineExceeded. Zero disables the timeout.
+func (db *Database) SetStatementTimeout(d time.Duration) {
+	atomic.StoreInt64(&db.statementTimeout, int64(d))
+}
+
 // GetAttachedTx returns the transaction attached to the database. It returns nil if there is no
 // such transaction.
 // The returned transaction is not thread safe.
@@ -150,6 +221,9 @@ func (db *Database) Begin(writable bool) (*Transaction, error) {
 // BeginTx starts a new transaction with the given options.
 // If opts is empty, it will use the default options.
 // The returned transaction must be closed either by calling Rollback or Commit.
+// It blocks until the transaction can be opened: read-only transactions wait for
+// the running and waiting read/write transactions to end, and a read/write transaction waits for all
+// the other transactions to end. If ctx is canceled while waiting, ctx.Err() is returned.
 // If the Attached option is passed, it opens a database level transaction, which gets
 // attached to the database and prevents any other transaction to be opened afterwards
 // until it gets rolled back or commited.
@@ -158,16 +232,20 @@ func (db *Database) BeginTx(ctx context.Context, opts *TxOptions) (*Transaction,
 		opts = new(TxOptions)
 	}
 
-	if !opts.ReadOnly {
-		db.txmu.Lock()
-	} else {
-		db.txmu.RLock()
+	if !opts.ReadOnly && db.ReadOnly {
+		return nil, errors.New("cannot open a read/write transaction: the database is read-only")
+	}
+
+	err := db.txLock.lock(ctx, !opts.ReadOnly)
+	if err != nil {
+		return nil, err
 	}
 
 	db.attachedTxMu.Lock()
 	defer db.attachedTxMu.Unlock()
 
 	if db.attachedTransaction != nil {
+		db.txLock.unlock(!opts.ReadOnly)
 		return nil, errors.New("cannot open a transaction within a transaction")
 	}
 
@@ -187,10 +265,24 @@ func (db *Database) beginTx(ctx context.Context, opts *TxOptions) (*Transaction,
 		st = db.DB.NewIndexedBatch()
 	}
 
+	session := kv.NewSession(st, opts.ReadOnly)
+	if db.NoSync {
+		session.WriteOptions = pebble.NoSync
+	}
+
 	tx := Transaction{
-		Session:  kv.NewSession(st, opts.ReadOnly),
+		Session:  session,
 		Writable: !opts.ReadOnly,
-		DBMu:     db.txmu,
+		dbLock:   db.txLock,
+	}
+
+	if tx.Writable {
+		if cs := db.ChangeFeed.begin(); cs != nil {
+			tx.changes = cs
+			tx.OnCommitHooks = append(tx.OnCommitHooks, func() {
+				db.ChangeFeed.publish(cs)
+			})
+		}
 	}
 
 	if opts.Attached {
@@ -1,11 +1,15 @@
 package database_test
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // See issue https://github.com/genjidb/genji/issues/298
@@ -67,3 +71,132 @@ func TestConcurrentTransactionManagement(t *testing.T) {
 		t.Fatal("deadlock")
 	}
 }
+
+func TestTransactionLock(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	begin := func(timeout time.Duration, writable bool) (*database.Transaction, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		return db.DB.BeginTx(ctx, &database.TxOptions{ReadOnly: !writable})
+	}
+
+	r1, err := begin(time.Second, false)
+	assert.NoError(t, err)
+
+	// a read/write transaction waits for the read-only transactions to end
+	_, err = begin(10*time.Millisecond, true)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// once it gave up, read-only transactions can be opened again
+	r2, err := begin(time.Second, false)
+	assert.NoError(t, err)
+	assert.NoError(t, r2.Rollback())
+
+	done := make(chan error)
+	go func() {
+		tx, err := begin(time.Second, true)
+		if err == nil {
+			err = tx.Rollback()
+		}
+		done <- err
+	}()
+
+	// read-only transactions wait behind a waiting read/write transaction
+	time.Sleep(10 * time.Millisecond)
+	_, err = begin(10*time.Millisecond, false)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.NoError(t, r1.Rollback())
+	assert.NoError(t, <-done)
+
+	// the transaction is released when the commit fails
+	tx, err := begin(time.Second, true)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Session.Close())
+	assert.Error(t, tx.Commit())
+
+	tx, err = begin(time.Second, true)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Rollback())
+}
+
+func TestTransactionLockWriterStarvation(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	// the readers must be stopped before closing the database
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+
+	// overlapping read-only transactions, such that there is
+	// always at least one of them running
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				tx, err := db.DB.Begin(false)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				time.Sleep(time.Millisecond)
+				_ = tx.Rollback()
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Rollback())
+}
+
+func TestTransactionDoubleRollback(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	for _, writable := range []bool{false, true} {
+		tx, err := db.DB.Begin(writable)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Rollback())
+		assert.NoError(t, tx.Rollback())
+		if writable {
+			assert.Error(t, tx.Commit())
+		}
+	}
+
+	// the lock was only released once
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	tx, err := db.DB.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, tx.Rollback())
+	assert.Error(t, tx.Commit())
+
+	tx, err = db.DB.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Rollback())
+}
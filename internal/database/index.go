@@ -160,21 +160,44 @@ func (idx *Index) Delete(vs []types.Value, key tree.Key) error {
 //
 // Any other variation of a pivot are invalid and will panic.
 func (idx *Index) IterateOnRange(rng *tree.Range, reverse bool, fn func(key tree.Key) error) error {
+	scopeRange(rng)
+
+	return idx.iterateOnRange(rng, reverse, func(itmKey, key tree.Key) error {
+		return fn(key)
+	})
+}
+
+// IterateValuesOnRange does the same as IterateOnRange but also decodes the values
+// stored in the index and passes them to the given function, along with the key of the document.
+// If rng is nil, it iterates over the whole index.
+func (idx *Index) IterateValuesOnRange(rng *tree.Range, reverse bool, fn func(vs []types.Value, key tree.Key) error) error {
+	if rng != nil {
+		scopeRange(rng)
+	}
+
+	return idx.Tree.IterateOnRange(rng, reverse, func(k tree.Key, v types.Value) error {
+		vs, err := k.Decode()
+		if err != nil {
+			return err
+		}
+
+		// the key of the document is the last element of the encoded array
+		return fn(vs[:len(vs)-1], tree.Key(vs[len(vs)-1].V().([]byte)))
+	})
+}
+
+// scopeRange ensures that if one of the boundaries is nil, the iteration only returns
+// keys of the same type as the other boundary's first value.
+func scopeRange(rng *tree.Range) {
 	if rng.Min == nil && rng.Max == nil {
 		panic("range cannot be empty")
 	}
 
-	// if one of the boundaries is nil, ensure the iteration only returns
-	// keys of the same type as the other boundary's first value.
 	if rng.Min == nil {
 		rng.Min = tree.NewMinKeyForType(types.ValueType(rng.Max[0]))
 	} else if rng.Max == nil {
 		rng.Max = tree.NewMaxKeyForType(types.ValueType(rng.Min[0]))
 	}
-
-	return idx.iterateOnRange(rng, reverse, func(itmKey, key tree.Key) error {
-		return fn(key)
-	})
 }
 
 func (idx *Index) Iterate(reverse bool, fn func(key tree.Key) error) error {
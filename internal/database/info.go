@@ -24,6 +24,10 @@ type TableInfo struct {
 
 	// Name of the docid sequence if any.
 	DocidSequenceName string
+
+	// Path of the expiration time of the documents, if any.
+	// Expired documents are deleted in the background by the database.
+	TTLPath document.Path
 }
 
 func (ti *TableInfo) Type() string {
@@ -134,6 +138,10 @@ func (ti *TableInfo) String() string {
 		s.WriteString(")")
 	}
 
+	if ti.TTLPath != nil {
+		fmt.Fprintf(&s, " WITH TTL ON %s", ti.TTLPath)
+	}
+
 	return s.String()
 }
 
@@ -236,6 +244,61 @@ func (i IndexInfo) Clone() *IndexInfo {
 	return &c
 }
 
+// ViewQuery is the query of a view.
+// It is implemented by the SELECT statement of the statement package.
+type ViewQuery interface {
+	String() string
+	// ReadsFrom returns the names of the tables and views read by the query.
+	ReadsFrom() []string
+}
+
+// ViewInfo holds the configuration of a view.
+type ViewInfo struct {
+	ViewName string
+	Query    ViewQuery
+
+	// If set to true, the result of the query is stored
+	// and only updated when the view is refreshed.
+	Materialized bool
+	// namespace of the store associated with a materialized view.
+	StoreNamespace kv.NamespaceID
+}
+
+func (v *ViewInfo) Type() string {
+	return "view"
+}
+
+func (v *ViewInfo) Name() string {
+	return v.ViewName
+}
+
+func (v *ViewInfo) SetName(name string) {
+	v.ViewName = name
+}
+
+func (v *ViewInfo) GenerateBaseName() string {
+	return v.ViewName
+}
+
+// String returns a SQL representation.
+func (v *ViewInfo) String() string {
+	var s strings.Builder
+
+	s.WriteString("CREATE ")
+	if v.Materialized {
+		s.WriteString("MATERIALIZED ")
+	}
+
+	fmt.Fprintf(&s, "VIEW %s AS %s", stringutil.NormalizeIdentifier(v.ViewName, '`'), v.Query)
+
+	return s.String()
+}
+
+// Clone returns a copy of the view information.
+func (v ViewInfo) Clone() *ViewInfo {
+	return &v
+}
+
 // SequenceInfo holds the configuration of a sequence.
 type SequenceInfo struct {
 	Name        string
@@ -297,3 +360,98 @@ type Owner struct {
 	TableName string
 	Paths     document.Paths
 }
+
+// TriggerTime determines when a trigger is fired, relative to the event.
+type TriggerTime int
+
+const (
+	// TriggerBefore fires the trigger before the document is written.
+	TriggerBefore TriggerTime = iota + 1
+
+	// TriggerAfter fires the trigger after the document is written.
+	TriggerAfter
+)
+
+func (t TriggerTime) String() string {
+	switch t {
+	case TriggerBefore:
+		return "BEFORE"
+	case TriggerAfter:
+		return "AFTER"
+	}
+
+	return ""
+}
+
+// TriggerEvent is the write operation that fires a trigger.
+type TriggerEvent int
+
+const (
+	// TriggerInsert fires the trigger for every inserted document.
+	TriggerInsert TriggerEvent = iota + 1
+
+	// TriggerUpdate fires the trigger for every replaced document.
+	TriggerUpdate
+
+	// TriggerDelete fires the trigger for every deleted document.
+	TriggerDelete
+)
+
+func (e TriggerEvent) String() string {
+	switch e {
+	case TriggerInsert:
+		return "INSERT"
+	case TriggerUpdate:
+		return "UPDATE"
+	case TriggerDelete:
+		return "DELETE"
+	}
+
+	return ""
+}
+
+// TriggerAction is the condition and the list of statements run by a trigger.
+// It is implemented by the statement package.
+type TriggerAction interface {
+	String() string
+}
+
+// TriggerInfo holds the configuration of a trigger.
+type TriggerInfo struct {
+	TriggerName string
+	TableName   string
+	Time        TriggerTime
+	Event       TriggerEvent
+	Action      TriggerAction
+}
+
+func (t *TriggerInfo) Type() string {
+	return "trigger"
+}
+
+func (t *TriggerInfo) Name() string {
+	return t.TriggerName
+}
+
+func (t *TriggerInfo) SetName(name string) {
+	t.TriggerName = name
+}
+
+func (t *TriggerInfo) GenerateBaseName() string {
+	return fmt.Sprintf("%s_%s_%s", t.TableName, strings.ToLower(t.Time.String()), strings.ToLower(t.Event.String()))
+}
+
+// String returns a SQL representation.
+func (t *TriggerInfo) String() string {
+	return fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW %s",
+		stringutil.NormalizeIdentifier(t.TriggerName, '`'),
+		t.Time,
+		t.Event,
+		stringutil.NormalizeIdentifier(t.TableName, '`'),
+		t.Action)
+}
+
+// Clone returns a copy of the trigger information.
+func (t TriggerInfo) Clone() *TriggerInfo {
+	return &t
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/genjidb/genji/internal/kv"
+)
+
+// CheckIntegrity ensures the relations of the catalog are consistent with each other:
+// every index, trigger and owned sequence refers to an existing table, every store namespace
+// is used by a single relation and was allocated by the store sequence, and every unique
+// constraint is backed by a unique index.
+// It doesn't read the documents of the tables.
+func (c *Catalog) CheckIntegrity() error {
+	storeSeq, err := c.GetSequence(StoreSequence)
+	if err != nil {
+		return fmt.Errorf("store sequence: %w", err)
+	}
+	var lastNamespace int64
+	if storeSeq.CurrentValue != nil {
+		lastNamespace = *storeSeq.CurrentValue
+	}
+
+	namespaces := make(map[kv.NamespaceID]string)
+	addNamespace := func(ns kv.NamespaceID, name string) error {
+		if other, ok := namespaces[ns]; ok {
+			return fmt.Errorf("%s and %s use the same namespace %d", other, name, ns)
+		}
+		namespaces[ns] = name
+
+		// the first namespaces are reserved for the system tables
+		if ns > 100 && int64(ns) > lastNamespace {
+			return fmt.Errorf("namespace %d of %s was not allocated by the store sequence", ns, name)
+		}
+
+		return nil
+	}
+
+	for _, name := range c.Cache.ListObjects(RelationTableType) {
+		ti, err := c.GetTableInfo(name)
+		if err != nil {
+			return err
+		}
+
+		err = addNamespace(ti.StoreNamespace, "table "+name)
+		if err != nil {
+			return err
+		}
+
+		if ti.DocidSequenceName != "" {
+			if _, err := c.GetSequence(ti.DocidSequenceName); err != nil {
+				return fmt.Errorf("table %s: docid sequence %s: %w", name, ti.DocidSequenceName, err)
+			}
+		}
+
+		for _, tc := range ti.TableConstraints {
+			if !tc.Unique {
+				continue
+			}
+
+			var found bool
+			for _, idx := range c.Cache.GetTableIndexes(name) {
+				if idx.Unique && tc.Paths.IsEqual(idx.Paths) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("table %s: no unique index for the constraint on (%s)", name, tc.Paths)
+			}
+		}
+	}
+
+	for _, name := range c.Cache.ListObjects(RelationIndexType) {
+		info, err := c.GetIndexInfo(name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.GetTableInfo(info.TableName); err != nil {
+			return fmt.Errorf("index %s: table %s: %w", name, info.TableName, err)
+		}
+
+		err = addNamespace(info.StoreNamespace, "index "+name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range c.ListViews() {
+		v, err := c.GetViewInfo(name)
+		if err != nil {
+			return err
+		}
+
+		if v.Materialized {
+			err = addNamespace(v.StoreNamespace, "view "+name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range c.ListSequences() {
+		seq, err := c.GetSequence(name)
+		if err != nil {
+			return err
+		}
+
+		owner := seq.Info.Owner.TableName
+		if owner == "" || owner == CatalogTableName {
+			continue
+		}
+
+		if _, err := c.GetTableInfo(owner); err != nil {
+			return fmt.Errorf("sequence %s: owner %s: %w", name, owner, err)
+		}
+	}
+
+	for _, name := range c.Cache.ListObjects(RelationTriggerType) {
+		t, err := c.GetTriggerInfo(name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.GetTableInfo(t.TableName); err != nil {
+			return fmt.Errorf("trigger %s: table %s: %w", name, t.TableName, err)
+		}
+	}
+
+	return nil
+}
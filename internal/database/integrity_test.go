@@ -0,0 +1,52 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/testutil"
+	"github.com/genjidb/genji/internal/testutil/assert"
+)
+
+func TestCatalogCheckIntegrity(t *testing.T) {
+	db := testutil.NewTestDB(t)
+
+	err := testutil.Exec(db, nil, `
+		CREATE TABLE foo (a INT UNIQUE, b TEXT);
+		CREATE INDEX foo_b ON foo (b);
+		CREATE TABLE bar (a INT PRIMARY KEY);
+		CREATE SEQUENCE seq;
+		CREATE TRIGGER bar_log AFTER INSERT ON bar BEGIN INSERT INTO foo (a) VALUES (NEW.a); END;
+	`)
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.Catalog.CheckIntegrity())
+
+	tests := []struct {
+		name string
+		tp   string
+		rel  string
+	}{
+		{"unique index", database.RelationIndexType, "foo_a_idx"},
+		{"table of an index", database.RelationTableType, "foo"},
+		{"table of a trigger", database.RelationTableType, "bar"},
+		{"docid sequence", database.RelationSequenceType, "foo_seq"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tx, err := db.Begin(true)
+			assert.NoError(t, err)
+			defer tx.Rollback()
+
+			// remove the relation from the catalog only,
+			// it is restored when the transaction is rolled back.
+			_, err = db.Catalog.Cache.Delete(tx, test.tp, test.rel)
+			assert.NoError(t, err)
+
+			assert.Error(t, db.Catalog.CheckIntegrity())
+		})
+	}
+
+	assert.NoError(t, db.Catalog.CheckIntegrity())
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"sync"
+)
+
+// txLock controls concurrency on read-only and read/write transactions:
+// it is held by any number of read-only transactions or by a single read/write transaction.
+// Like with sync.RWMutex, read-only transactions wait behind a read/write transaction
+// waiting for the lock, so that writers are not starved by a steady flow of readers.
+// Unlike sync.RWMutex, waiting for the lock can be canceled using a context:
+// a goroutine opening a read-only transaction while it already holds one can
+// thus give up instead of blocking forever.
+type txLock struct {
+	mu      sync.Mutex
+	readers int
+	writer  bool
+	// number of read/write transactions waiting for the lock.
+	waitingWriters int
+	// closed when the lock is released, to wake up the waiting transactions.
+	released chan struct{}
+}
+
+func newTxLock() *txLock {
+	return &txLock{
+		released: make(chan struct{}),
+	}
+}
+
+// lock blocks until the lock is acquired or the context is canceled.
+func (l *txLock) lock(ctx context.Context, writable bool) error {
+	var waiting bool
+
+	for {
+		l.mu.Lock()
+		if writable && !l.writer && l.readers == 0 {
+			if waiting {
+				l.waitingWriters--
+			}
+			l.writer = true
+			l.mu.Unlock()
+			return nil
+		}
+		if !writable && !l.writer && l.waitingWriters == 0 {
+			l.readers++
+			l.mu.Unlock()
+			return nil
+		}
+		if writable && !waiting {
+			waiting = true
+			l.waitingWriters++
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			if waiting {
+				l.mu.Lock()
+				l.waitingWriters--
+				// the readers waiting behind this writer may proceed
+				l.wakeUp()
+				l.mu.Unlock()
+			}
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// unlock releases the lock. Like with sync.RWMutex, it panics
+// if the lock is not held in the given mode.
+func (l *txLock) unlock(writable bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if writable {
+		if !l.writer {
+			panic("database: unlock of a transaction lock not held by a read/write transaction")
+		}
+		l.writer = false
+	} else {
+		if l.readers == 0 {
+			panic("database: unlock of a transaction lock not held by a read-only transaction")
+		}
+		l.readers--
+	}
+
+	l.wakeUp()
+}
+
+// wakeUp wakes up the transactions waiting for the lock.
+// It must be called with l.mu held.
+func (l *txLock) wakeUp() {
+	close(l.released)
+	l.released = make(chan struct{})
+}
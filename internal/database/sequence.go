@@ -142,6 +142,7 @@ func (s *Sequence) Next(tx *Transaction, catalog *Catalog) (int64, error) {
 	// we don't increase the lease.
 	if s.CurrentValue != nil && s.Cached <= s.Info.Cache {
 		s.CurrentValue = &newValue
+		s.recordValue(tx, newValue)
 		return newValue, nil
 	}
 
@@ -150,15 +151,21 @@ func (s *Sequence) Next(tx *Transaction, catalog *Catalog) (int64, error) {
 		s.Cached = 1
 	}
 
+	// sequences without cache lease their values one by one
+	cache := int64(s.Info.Cache)
+	if cache == 0 {
+		cache = 1
+	}
+
 	// calculate the new lease depending on the direction
 	// of the sequence
 	if s.Info.IncrementBy > 0 {
-		newLease = newValue + int64(s.Info.Cache) - 1
+		newLease = newValue + cache - 1
 		if newLease > s.Info.Max {
 			newLease = s.Info.Max
 		}
 	} else {
-		newLease = newValue - int64(s.Info.Cache) + 1
+		newLease = newValue - cache + 1
 		if newLease < s.Info.Min {
 			newLease = s.Info.Min
 		}
@@ -171,9 +178,52 @@ func (s *Sequence) Next(tx *Transaction, catalog *Catalog) (int64, error) {
 	}
 
 	s.CurrentValue = &newValue
+	s.recordValue(tx, newValue)
 	return newValue, nil
 }
 
+// SetValue sets the current value of the sequence. The next call to Next
+// returns the value that follows v.
+func (s *Sequence) SetValue(tx *Transaction, catalog *Catalog, v int64) error {
+	if !tx.Writable {
+		return errors.New("cannot set sequence value on read-only transaction")
+	}
+
+	if v < s.Info.Min || v > s.Info.Max {
+		return fmt.Errorf("value %d is out of bounds for sequence %s (%d..%d)", v, s.Info.Name, s.Info.Min, s.Info.Max)
+	}
+
+	// the cache is marked as exhausted for the next call to Next
+	// to extend the lease from v.
+	err := s.SetLease(tx, catalog, s.Info.Name, v)
+	if err != nil {
+		return err
+	}
+
+	// the lease is restored on rollback, so must be the in-memory state
+	prevValue, prevCached := s.CurrentValue, s.Cached
+	tx.OnRollbackHooks = append(tx.OnRollbackHooks, func() {
+		s.CurrentValue = prevValue
+		s.Cached = prevCached
+	})
+
+	s.CurrentValue = &v
+	s.Cached = s.Info.Cache
+	s.recordValue(tx, v)
+	return nil
+}
+
+// recordValue stores the value returned by the sequence in the transaction,
+// for currval and lastval. Sequences owned by a table, used to generate document ids,
+// are not recorded.
+func (s *Sequence) recordValue(tx *Transaction, v int64) {
+	if s.Info.Owner.TableName != "" {
+		return
+	}
+
+	tx.setSequenceValue(s.Info.Name, v)
+}
+
 func (s *Sequence) SetLease(tx *Transaction, catalog *Catalog, name string, v int64) error {
 	tb, err := s.GetOrCreateTable(tx, catalog)
 	if err != nil {
@@ -231,6 +231,31 @@ func TestSequence(t *testing.T) {
 		require.Equal(t, int64(5), *got)
 	})
 
+	t.Run("no cache", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		tx, err := db.Begin(true)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		err = db.Catalog.CreateSequence(tx, &database.SequenceInfo{
+			Name:        "a",
+			IncrementBy: 1,
+			Min:         1, Max: 5,
+			Start: 1,
+			Cache: 0,
+		})
+		assert.NoError(t, err)
+
+		seq, err := db.Catalog.GetSequence("a")
+		assert.NoError(t, err)
+
+		// the lease must never be lower than the returned values
+		next(seq, tx, db.Catalog, 1, 1)
+		next(seq, tx, db.Catalog, 2, 2)
+		next(seq, tx, db.Catalog, 3, 3)
+	})
+
 	t.Run("cache", func(t *testing.T) {
 		db := testutil.NewTestDB(t)
 
@@ -401,4 +426,191 @@ func TestSequence(t *testing.T) {
 
 		next(seq, tx, db.Catalog, 5, 9)
 	})
+
+	t.Run("set value", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		tx, err := db.Begin(true)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		err = db.Catalog.CreateSequence(tx, &database.SequenceInfo{
+			Name:        "a",
+			IncrementBy: 1,
+			Min:         1, Max: 20,
+			Start: 1,
+			Cache: 5,
+		})
+		assert.NoError(t, err)
+
+		seq, err := db.Catalog.GetSequence("a")
+		assert.NoError(t, err)
+
+		next(seq, tx, db.Catalog, 1, 5)
+
+		// the lease is set to the new value and the next call extends it
+		err = seq.SetValue(tx, db.Catalog, 10)
+		assert.NoError(t, err)
+		got, err := getLease(t, tx, db.Catalog, "a")
+		assert.NoError(t, err)
+		require.Equal(t, int64(10), *got)
+
+		next(seq, tx, db.Catalog, 11, 15)
+		next(seq, tx, db.Catalog, 12, 15)
+
+		// out of bounds
+		err = seq.SetValue(tx, db.Catalog, 21)
+		assert.Error(t, err)
+		err = seq.SetValue(tx, db.Catalog, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("set value rollback", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		tx, err := db.Begin(true)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		err = db.Catalog.CreateSequence(tx, &database.SequenceInfo{
+			Name:        "a",
+			IncrementBy: 1,
+			Min:         1, Max: 20,
+			Start: 1,
+			Cache: 5,
+		})
+		assert.NoError(t, err)
+
+		seq, err := db.Catalog.GetSequence("a")
+		assert.NoError(t, err)
+
+		next(seq, tx, db.Catalog, 1, 5)
+		assert.NoError(t, tx.Commit())
+
+		tx, err = db.Begin(true)
+		assert.NoError(t, err)
+		err = seq.SetValue(tx, db.Catalog, 10)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Rollback())
+
+		// the sequence continues from its value before the rollback
+		tx, err = db.Begin(true)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		next(seq, tx, db.Catalog, 2, 5)
+	})
+
+	t.Run("alter", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		tx, err := db.Begin(true)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		err = db.Catalog.CreateSequence(tx, &database.SequenceInfo{
+			Name:        "a",
+			IncrementBy: 1,
+			Min:         1, Max: 100,
+			Start: 1,
+			Cache: 5,
+		})
+		assert.NoError(t, err)
+
+		seq, err := db.Catalog.GetSequence("a")
+		assert.NoError(t, err)
+
+		next(seq, tx, db.Catalog, 1, 5)
+		next(seq, tx, db.Catalog, 2, 5)
+
+		// changing the increment releases the cached values
+		info := seq.Info.Clone()
+		info.IncrementBy = 10
+		err = db.Catalog.AlterSequence(tx, "a", info, nil)
+		assert.NoError(t, err)
+
+		seq, err = db.Catalog.GetSequence("a")
+		assert.NoError(t, err)
+		got, err := getLease(t, tx, db.Catalog, "a")
+		assert.NoError(t, err)
+		require.Equal(t, int64(2), *got)
+
+		next(seq, tx, db.Catalog, 12, 16)
+
+		// restarting the sequence
+		err = db.Catalog.AlterSequence(tx, "a", seq.Info.Clone(), testutil.Int64Ptr(50))
+		assert.NoError(t, err)
+
+		seq, err = db.Catalog.GetSequence("a")
+		assert.NoError(t, err)
+		next(seq, tx, db.Catalog, 50, 54)
+
+		// the new options are stored in the catalog
+		c := database.NewCatalog()
+		err = c.Init(tx)
+		assert.NoError(t, err)
+
+		err = catalogstore.LoadCatalog(tx.Session.DB, c)
+		assert.NoError(t, err)
+
+		seq, err = c.GetSequence("a")
+		assert.NoError(t, err)
+		require.Equal(t, int64(10), seq.Info.IncrementBy)
+		// the sequence restarts after the stored lease
+		next(seq, tx, c, 64, 68)
+	})
+
+	t.Run("transaction values", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		tx, err := db.Begin(true)
+		assert.NoError(t, err)
+
+		for _, name := range []string{"a", "b"} {
+			err = db.Catalog.CreateSequence(tx, &database.SequenceInfo{
+				Name:        name,
+				IncrementBy: 1,
+				Min:         1, Max: 100,
+				Start: 1,
+				Cache: 1,
+			})
+			assert.NoError(t, err)
+		}
+
+		_, ok := tx.LastSequenceValue()
+		require.False(t, ok)
+
+		a, err := db.Catalog.GetSequence("a")
+		assert.NoError(t, err)
+		b, err := db.Catalog.GetSequence("b")
+		assert.NoError(t, err)
+
+		next(a, tx, db.Catalog, 1, 1)
+		next(a, tx, db.Catalog, 2, 2)
+		err = b.SetValue(tx, db.Catalog, 50)
+		assert.NoError(t, err)
+
+		v, ok := tx.CurrentSequenceValue("a")
+		require.True(t, ok)
+		require.Equal(t, int64(2), v)
+		v, ok = tx.CurrentSequenceValue("b")
+		require.True(t, ok)
+		require.Equal(t, int64(50), v)
+		v, ok = tx.LastSequenceValue()
+		require.True(t, ok)
+		require.Equal(t, int64(50), v)
+
+		err = tx.Commit()
+		assert.NoError(t, err)
+
+		// values are not shared between transactions
+		tx, err = db.Begin(true)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		_, ok = tx.CurrentSequenceValue("a")
+		require.False(t, ok)
+		_, ok = tx.LastSequenceValue()
+		require.False(t, ok)
+	})
 }
@@ -0,0 +1,621 @@
+package database
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/document"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/internal/tree"
+	"github.com/genjidb/genji/types"
+)
+
+// DefaultHistogramBuckets is the number of buckets
+// generated for each histogram by CollectStatistics.
+const DefaultHistogramBuckets = 32
+
+// DefaultStatisticsSampleSize is the maximum number of keys
+// used by CollectStatistics to build a histogram.
+const DefaultStatisticsSampleSize = 10000
+
+var statisticsTableInfo = &TableInfo{
+	TableName:      StatisticsTableName,
+	StoreNamespace: StatisticsTableNamespace,
+	FieldConstraints: []*FieldConstraint{
+		{
+			Path:      document.NewPath("name"),
+			Type:      types.TextValue,
+			IsNotNull: true,
+		},
+		{
+			Path:      document.NewPath("type"),
+			Type:      types.TextValue,
+			IsNotNull: true,
+		},
+		{
+			Path:      document.NewPath("table_name"),
+			Type:      types.TextValue,
+			IsNotNull: true,
+		},
+		{
+			Path:      document.NewPath("row_count"),
+			Type:      types.IntegerValue,
+			IsNotNull: true,
+		},
+		{
+			Path: document.NewPath("distinct_count"),
+			Type: types.ArrayValue,
+		},
+		{
+			Path: document.NewPath("histogram"),
+			Type: types.ArrayValue,
+		},
+	},
+	TableConstraints: []*TableConstraint{
+		{
+			Paths: []document.Path{
+				document.NewPath("name"),
+			},
+			PrimaryKey: true,
+		},
+	},
+}
+
+// Statistics describes the distribution of the data stored
+// in a table or an index. They are collected by the ANALYZE statement
+// and used by the planner to estimate the cost of each plan.
+type Statistics struct {
+	// Name of the table or index.
+	Name string
+	// Type of the relation, either RelationTableType or RelationIndexType.
+	Type string
+	// Name of the table the statistics belong to.
+	TableName string
+	// Number of entries stored in the tree.
+	RowCount int64
+	// Distinct[i] holds the number of distinct combinations
+	// of the i+1 first values of each key.
+	// For a table, these are the primary key values,
+	// for an index, the indexed values.
+	Distinct []int64
+	// Equi-depth histogram of the first value of each key.
+	Histogram []Bucket
+}
+
+// A Bucket of a histogram holds the number of keys whose
+// first value is between Lower and Upper, inclusive.
+type Bucket struct {
+	Lower, Upper types.Value
+	Count        int64
+	Distinct     int64
+}
+
+// CollectStatistics reads every key of the tree and computes statistics
+// about the first arity values of each key.
+// Only keys are decoded, documents are never read.
+// The tree is read only once: the histogram is built from a random
+// sample of at most sampleSize keys, which is scaled to the number of rows.
+func CollectStatistics(tr *tree.Tree, arity, buckets, sampleSize int) (*Statistics, error) {
+	var stats Statistics
+	stats.Distinct = make([]int64, arity)
+
+	// reservoir sampling of the first value of each key.
+	// the position of each sampled key is kept to restore the order of the tree.
+	var sample []sampledValue
+	rnd := rand.New(rand.NewSource(1))
+
+	var prev []types.Value
+	err := tr.IterateOnRange(nil, false, func(k tree.Key, _ types.Value) error {
+		values, err := decodeKeyPrefix(k, arity)
+		if err != nil {
+			return err
+		}
+
+		stats.RowCount++
+
+		// find the first value that differs from the previous key.
+		// every prefix that contains it is a new distinct combination.
+		changedAt := 0
+		if prev != nil {
+			changedAt = len(values)
+			for i := range values {
+				eq, err := types.IsEqual(prev[i], values[i])
+				if err != nil {
+					return err
+				}
+				if !eq {
+					changedAt = i
+					break
+				}
+			}
+		}
+
+		for i := changedAt; i < len(values); i++ {
+			stats.Distinct[i]++
+		}
+
+		prev = values
+
+		if buckets <= 0 || sampleSize <= 0 {
+			return nil
+		}
+
+		sv := sampledValue{pos: stats.RowCount, v: values[0]}
+		if len(sample) < sampleSize {
+			sample = append(sample, sv)
+		} else if j := rnd.Int63n(stats.RowCount); j < int64(sampleSize) {
+			sample[j] = sv
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sample) == 0 {
+		return &stats, nil
+	}
+
+	sort.Slice(sample, func(i, j int) bool {
+		return sample[i].pos < sample[j].pos
+	})
+
+	stats.Histogram, err = buildHistogram(sample, buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	// scale the buckets of the sample to the number of rows
+	if n := int64(len(sample)); n < stats.RowCount {
+		var total int64
+		for i := range stats.Histogram {
+			b := &stats.Histogram[i]
+			unique := b.Distinct == b.Count
+			b.Count = b.Count * stats.RowCount / n
+			// values that were all different in the sample are assumed to be unique
+			if unique {
+				b.Distinct = b.Count
+			}
+			total += b.Count
+		}
+		// distribute the remainder of the divisions to the last bucket
+		stats.Histogram[len(stats.Histogram)-1].Count += stats.RowCount - total
+	}
+
+	return &stats, nil
+}
+
+// sampledValue is the first value of a key sampled by CollectStatistics,
+// along with the position of the key in the tree.
+type sampledValue struct {
+	pos int64
+	v   types.Value
+}
+
+// buildHistogram builds an equi-depth histogram from the sorted sample.
+// A value is never split across two buckets.
+func buildHistogram(sample []sampledValue, buckets int) ([]Bucket, error) {
+	n := int64(len(sample))
+	depth := n / int64(buckets)
+	if n%int64(buckets) != 0 {
+		depth++
+	}
+
+	var histogram []Bucket
+	var cur *Bucket
+	for _, sv := range sample {
+		v := sv.v
+
+		if cur != nil {
+			eq, err := types.IsEqual(cur.Upper, v)
+			if err != nil {
+				return nil, err
+			}
+			if eq {
+				cur.Count++
+				continue
+			}
+
+			if cur.Count < depth {
+				cur.Upper = v
+				cur.Count++
+				cur.Distinct++
+				continue
+			}
+
+			histogram = append(histogram, *cur)
+		}
+
+		cur = &Bucket{Lower: v, Upper: v, Count: 1, Distinct: 1}
+	}
+
+	if cur != nil {
+		histogram = append(histogram, *cur)
+	}
+
+	return histogram, nil
+}
+
+// decodeKeyPrefix decodes the key and returns its n first values.
+// Decoded values are copied to remain valid after the iteration moves on.
+func decodeKeyPrefix(k tree.Key, n int) ([]types.Value, error) {
+	values, err := k.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) > n {
+		values = values[:n]
+	}
+
+	for i := range values {
+		values[i], err = document.CloneValue(values[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// EstimateEqual estimates the number of keys whose n first values
+// are equal to the given values.
+func (s *Statistics) EstimateEqual(values []types.Value) float64 {
+	if s.RowCount == 0 || len(values) == 0 {
+		return 0
+	}
+
+	n := len(values)
+	if n > len(s.Distinct) {
+		n = len(s.Distinct)
+	}
+
+	// for a single value, use the histogram if possible
+	if n == 1 && len(s.Histogram) > 0 {
+		for _, b := range s.Histogram {
+			in, err := b.contains(values[0])
+			if err != nil {
+				break
+			}
+			if in {
+				return float64(b.Count) / float64(b.Distinct)
+			}
+		}
+
+		// the value is not covered by the histogram
+		return 0
+	}
+
+	if s.Distinct[n-1] == 0 {
+		return 0
+	}
+
+	return float64(s.RowCount) / float64(s.Distinct[n-1])
+}
+
+// EstimateRange estimates the number of keys whose first value is between min and max.
+// A nil boundary is considered unbounded.
+func (s *Statistics) EstimateRange(min, max types.Value) float64 {
+	if s.RowCount == 0 {
+		return 0
+	}
+
+	if len(s.Histogram) == 0 {
+		// without histogram, assume a third of the rows match
+		return float64(s.RowCount) / 3
+	}
+
+	var total float64
+	for _, b := range s.Histogram {
+		overlap, err := b.overlap(min, max)
+		if err != nil {
+			return float64(s.RowCount) / 3
+		}
+
+		total += overlap * float64(b.Count)
+	}
+
+	return total
+}
+
+// contains returns whether v is between the boundaries of the bucket.
+func (b *Bucket) contains(v types.Value) (bool, error) {
+	ok, err := types.IsGreaterThanOrEqual(v, b.Lower)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return types.IsLesserThanOrEqual(v, b.Upper)
+}
+
+// overlap returns the fraction of the bucket that is covered by
+// the [min, max] interval. Buckets partially covered are considered
+// half covered.
+func (b *Bucket) overlap(min, max types.Value) (float64, error) {
+	if max != nil {
+		ok, err := types.IsLesserThan(max, b.Lower)
+		if err != nil || ok {
+			return 0, err
+		}
+	}
+
+	if min != nil {
+		ok, err := types.IsGreaterThan(min, b.Upper)
+		if err != nil || ok {
+			return 0, err
+		}
+	}
+
+	full := true
+	if min != nil {
+		ok, err := types.IsLesserThanOrEqual(min, b.Lower)
+		if err != nil {
+			return 0, err
+		}
+		full = ok
+	}
+	if max != nil && full {
+		ok, err := types.IsGreaterThanOrEqual(max, b.Upper)
+		if err != nil {
+			return 0, err
+		}
+		full = ok
+	}
+
+	if full {
+		return 1, nil
+	}
+
+	return 0.5, nil
+}
+
+// AnalyzeTable collects statistics about the table and all of its indexes
+// and stores them in the statistics table.
+func (c *Catalog) AnalyzeTable(tx *Transaction, tableName string) error {
+	tb, err := c.GetTable(tx, tableName)
+	if err != nil {
+		return err
+	}
+
+	// tables are keyed by their primary key, or by a docid
+	arity := 1
+	if pk := tb.Info.GetPrimaryKey(); pk != nil {
+		arity = len(pk.Paths)
+	}
+
+	stats, err := CollectStatistics(tb.Tree, arity, DefaultHistogramBuckets, DefaultStatisticsSampleSize)
+	if err != nil {
+		return err
+	}
+	stats.Name = tableName
+	stats.Type = RelationTableType
+	stats.TableName = tableName
+
+	err = c.SetStatistics(tx, stats)
+	if err != nil {
+		return err
+	}
+
+	for _, idxName := range c.ListIndexes(tableName) {
+		idx, err := c.GetIndex(tx, idxName)
+		if err != nil {
+			return err
+		}
+
+		stats, err := CollectStatistics(idx.Tree, idx.Arity, DefaultHistogramBuckets, DefaultStatisticsSampleSize)
+		if err != nil {
+			return err
+		}
+		stats.Name = idxName
+		stats.Type = RelationIndexType
+		stats.TableName = tableName
+
+		err = c.SetStatistics(tx, stats)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetStatistics returns the statistics of the given table or index.
+// If the relation was never analyzed, it returns errs.NotFoundError.
+func (c *Catalog) GetStatistics(name string) (*Statistics, error) {
+	s, ok := c.Cache.statistics[name]
+	if !ok {
+		return nil, errors.WithStack(errs.NotFoundError{Name: name})
+	}
+
+	return s, nil
+}
+
+// SetStatistics stores the statistics of a table or an index,
+// replacing any existing ones.
+func (c *Catalog) SetStatistics(tx *Transaction, stats *Statistics) error {
+	tb, err := c.getOrCreateSystemTable(tx, statisticsTableInfo)
+	if err != nil {
+		return err
+	}
+
+	key, err := tree.NewKey(types.NewTextValue(stats.Name))
+	if err != nil {
+		return err
+	}
+
+	err = tb.Tree.Put(key, types.NewDocumentValue(statisticsToDocument(stats)))
+	if err != nil {
+		return err
+	}
+
+	c.Cache.setStatistics(tx, stats)
+	return nil
+}
+
+// DeleteStatistics removes the statistics of a table or an index, if any.
+func (c *Catalog) DeleteStatistics(tx *Transaction, name string) error {
+	if _, ok := c.Cache.statistics[name]; !ok {
+		return nil
+	}
+
+	tb, err := c.GetTable(tx, StatisticsTableName)
+	if err != nil {
+		return err
+	}
+
+	key, err := tree.NewKey(types.NewTextValue(name))
+	if err != nil {
+		return err
+	}
+
+	err = tb.Delete(key)
+	if err != nil && !errors.Is(err, errs.ErrDocumentNotFound) {
+		return err
+	}
+
+	c.Cache.deleteStatistics(tx, name)
+	return nil
+}
+
+// deleteTableStatistics removes the statistics of the table and of all of its indexes.
+func (c *Catalog) deleteTableStatistics(tx *Transaction, tableName string) error {
+	for _, s := range c.Cache.statistics {
+		if s.TableName != tableName {
+			continue
+		}
+
+		err := c.DeleteStatistics(tx, s.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadStatistics adds the given statistics to the catalog cache without persisting them.
+func (c *Catalog) LoadStatistics(stats []*Statistics) {
+	for _, s := range stats {
+		c.Cache.statistics[s.Name] = s
+	}
+}
+
+func statisticsToDocument(s *Statistics) types.Document {
+	buf := document.NewFieldBuffer()
+	buf.Add("name", types.NewTextValue(s.Name))
+	buf.Add("type", types.NewTextValue(s.Type))
+	buf.Add("table_name", types.NewTextValue(s.TableName))
+	buf.Add("row_count", types.NewIntegerValue(s.RowCount))
+
+	distinct := document.NewValueBuffer()
+	for _, d := range s.Distinct {
+		distinct.Append(types.NewIntegerValue(d))
+	}
+	buf.Add("distinct_count", types.NewArrayValue(distinct))
+
+	histogram := document.NewValueBuffer()
+	for _, b := range s.Histogram {
+		histogram.Append(types.NewDocumentValue(document.NewFieldBuffer().
+			Add("lower", b.Lower).
+			Add("upper", b.Upper).
+			Add("count", types.NewIntegerValue(b.Count)).
+			Add("distinct_count", types.NewIntegerValue(b.Distinct)),
+		))
+	}
+	buf.Add("histogram", types.NewArrayValue(histogram))
+
+	return buf
+}
+
+// StatisticsFromDocument decodes statistics stored in the statistics table.
+func StatisticsFromDocument(d types.Document) (*Statistics, error) {
+	var s Statistics
+
+	v, err := d.GetByField("name")
+	if err != nil {
+		return nil, err
+	}
+	s.Name = v.V().(string)
+
+	v, err = d.GetByField("type")
+	if err != nil {
+		return nil, err
+	}
+	s.Type = v.V().(string)
+
+	v, err = d.GetByField("table_name")
+	if err != nil {
+		return nil, err
+	}
+	s.TableName = v.V().(string)
+
+	v, err = d.GetByField("row_count")
+	if err != nil {
+		return nil, err
+	}
+	s.RowCount = v.V().(int64)
+
+	v, err = d.GetByField("distinct_count")
+	if err != nil && !errors.Is(err, types.ErrFieldNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		err = v.V().(types.Array).Iterate(func(i int, v types.Value) error {
+			s.Distinct = append(s.Distinct, v.V().(int64))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	v, err = d.GetByField("histogram")
+	if err != nil && !errors.Is(err, types.ErrFieldNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		err = v.V().(types.Array).Iterate(func(i int, v types.Value) error {
+			var b Bucket
+			bd := v.V().(types.Document)
+
+			b.Lower, err = bd.GetByField("lower")
+			if err != nil {
+				return err
+			}
+			b.Lower, err = document.CloneValue(b.Lower)
+			if err != nil {
+				return err
+			}
+			b.Upper, err = bd.GetByField("upper")
+			if err != nil {
+				return err
+			}
+			b.Upper, err = document.CloneValue(b.Upper)
+			if err != nil {
+				return err
+			}
+
+			c, err := bd.GetByField("count")
+			if err != nil {
+				return err
+			}
+			b.Count = c.V().(int64)
+
+			c, err = bd.GetByField("distinct_count")
+			if err != nil {
+				return err
+			}
+			b.Distinct = c.V().(int64)
+
+			s.Histogram = append(s.Histogram, b)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &s, nil
+}
@@ -0,0 +1,124 @@
+package database_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/genjidb/genji"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/environment"
+	"github.com/genjidb/genji/internal/testutil"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/genjidb/genji/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectStatistics(t *testing.T) {
+	db, tx, cleanup := testutil.NewTestTx(t)
+	defer cleanup()
+
+	testutil.MustExec(t, db, tx, `
+		CREATE TABLE test(a int, b int, PRIMARY KEY (a, b));
+		INSERT INTO test (a, b) VALUES (1, 1), (1, 2), (2, 1), (3, 1), (3, 2), (3, 3);
+	`)
+
+	tb, err := db.Catalog.GetTable(tx, "test")
+	assert.NoError(t, err)
+
+	stats, err := database.CollectStatistics(tb.Tree, 2, 2, 100)
+	assert.NoError(t, err)
+
+	require.Equal(t, int64(6), stats.RowCount)
+	require.Equal(t, []int64{3, 6}, stats.Distinct)
+	require.Len(t, stats.Histogram, 2)
+	require.Equal(t, database.Bucket{Lower: types.NewIntegerValue(1), Upper: types.NewIntegerValue(2), Count: 3, Distinct: 2}, stats.Histogram[0])
+	require.Equal(t, database.Bucket{Lower: types.NewIntegerValue(3), Upper: types.NewIntegerValue(3), Count: 3, Distinct: 1}, stats.Histogram[1])
+
+	require.Equal(t, 1.5, stats.EstimateEqual([]types.Value{types.NewIntegerValue(1)}))
+	require.Equal(t, 3.0, stats.EstimateEqual([]types.Value{types.NewIntegerValue(3)}))
+	require.Equal(t, 0.0, stats.EstimateEqual([]types.Value{types.NewIntegerValue(10)}))
+	require.Equal(t, 1.0, stats.EstimateEqual([]types.Value{types.NewIntegerValue(3), types.NewIntegerValue(1)}))
+	require.Equal(t, 3.0, stats.EstimateRange(types.NewIntegerValue(3), nil))
+	require.Equal(t, 6.0, stats.EstimateRange(nil, nil))
+}
+
+func TestCollectStatisticsSample(t *testing.T) {
+	db, tx, cleanup := testutil.NewTestTx(t)
+	defer cleanup()
+
+	testutil.MustExec(t, db, tx, `CREATE TABLE test(a int PRIMARY KEY)`)
+	for i := 0; i < 1000; i++ {
+		testutil.MustExec(t, db, tx, `INSERT INTO test (a) VALUES (?)`, environment.Param{Value: i})
+	}
+
+	tb, err := db.Catalog.GetTable(tx, "test")
+	assert.NoError(t, err)
+
+	stats, err := database.CollectStatistics(tb.Tree, 1, 4, 100)
+	assert.NoError(t, err)
+
+	// counts are exact, the histogram is built from the sample
+	require.Equal(t, int64(1000), stats.RowCount)
+	require.Equal(t, []int64{1000}, stats.Distinct)
+	require.Len(t, stats.Histogram, 4)
+
+	var total int64
+	for _, b := range stats.Histogram {
+		total += b.Count
+		require.Equal(t, b.Count, b.Distinct)
+	}
+	require.Equal(t, int64(1000), total)
+	require.InDelta(t, 1.0, stats.EstimateEqual([]types.Value{types.NewIntegerValue(500)}), 0.01)
+}
+
+func TestCatalogStatistics(t *testing.T) {
+	t.Run("Rollback", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.Catalog) error {
+			return catalog.CreateTable(tx, "test", nil)
+		})
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.Catalog) error {
+			err := catalog.AnalyzeTable(tx, "test")
+			assert.NoError(t, err)
+
+			_, err = catalog.GetStatistics("test")
+			assert.NoError(t, err)
+
+			return errDontCommit
+		})
+
+		_, err := db.Catalog.GetStatistics("test")
+		require.True(t, errs.IsNotFoundError(err))
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		dir := filepath.Join(testutil.TempDir(t), "db")
+
+		db, err := genji.Open(dir)
+		assert.NoError(t, err)
+
+		err = db.Exec(`
+			CREATE TABLE test(a int);
+			CREATE INDEX test_a ON test(a);
+			INSERT INTO test (a) VALUES (1), (2), (2);
+			ANALYZE;
+		`)
+		assert.NoError(t, err)
+		assert.NoError(t, db.Close())
+
+		db, err = genji.Open(dir)
+		assert.NoError(t, err)
+		defer db.Close()
+
+		stats, err := db.DB.Catalog.GetStatistics("test_a")
+		assert.NoError(t, err)
+		require.Equal(t, "test", stats.TableName)
+		require.Equal(t, database.RelationIndexType, stats.Type)
+		require.Equal(t, int64(3), stats.RowCount)
+		require.Equal(t, []int64{2}, stats.Distinct)
+		require.Len(t, stats.Histogram, 2)
+	})
+}
@@ -0,0 +1,217 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/document"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/types"
+)
+
+// System tables are read-only virtual tables describing the objects of the catalog.
+// Unlike the catalog table, the shape of their documents is stable
+// and can be relied upon for introspection.
+// Internal tables and sequences are not listed.
+const (
+	// SystemTablesTableName lists the tables.
+	SystemTablesTableName = InternalPrefix + "tables"
+	// SystemFieldsTableName lists the field constraints of every table.
+	SystemFieldsTableName = InternalPrefix + "fields"
+	// SystemIndexesTableName lists the indexes.
+	SystemIndexesTableName = InternalPrefix + "indexes"
+	// SystemSequencesTableName lists the sequences.
+	SystemSequencesTableName = InternalPrefix + "sequences"
+)
+
+// IsSystemTable returns whether the name refers to a system table.
+func IsSystemTable(name string) bool {
+	switch name {
+	case SystemTablesTableName, SystemFieldsTableName, SystemIndexesTableName, SystemSequencesTableName:
+		return true
+	}
+
+	return false
+}
+
+// IterateSystemTable calls fn for every document of the given system table.
+// Documents are sorted by name.
+func (c *Catalog) IterateSystemTable(name string, fn func(d types.Document) error) error {
+	switch name {
+	case SystemTablesTableName:
+		return c.iterateSystemTables(fn)
+	case SystemFieldsTableName:
+		return c.iterateSystemFields(fn)
+	case SystemIndexesTableName:
+		return c.iterateSystemIndexes(fn)
+	case SystemSequencesTableName:
+		return c.iterateSystemSequences(fn)
+	}
+
+	return errors.WithStack(errs.NotFoundError{Name: name})
+}
+
+// listTables returns the info of the tables that are not internal, sorted by name.
+func (c *Catalog) listTables() []*TableInfo {
+	var infos []*TableInfo
+
+	for _, name := range c.Cache.ListObjects(RelationTableType) {
+		if strings.HasPrefix(name, InternalPrefix) {
+			continue
+		}
+
+		ti, err := c.GetTableInfo(name)
+		if err != nil {
+			continue
+		}
+
+		infos = append(infos, ti)
+	}
+
+	return infos
+}
+
+func pathsToArray(paths []document.Path) types.Value {
+	vb := document.NewValueBuffer()
+	for _, p := range paths {
+		vb.Append(types.NewTextValue(p.String()))
+	}
+
+	return types.NewArrayValue(vb)
+}
+
+func (c *Catalog) iterateSystemTables(fn func(d types.Document) error) error {
+	for _, ti := range c.listTables() {
+		buf := document.NewFieldBuffer()
+		buf.Add("name", types.NewTextValue(ti.TableName))
+		buf.Add("namespace", types.NewIntegerValue(int64(ti.StoreNamespace)))
+		buf.Add("read_only", types.NewBoolValue(ti.ReadOnly))
+		if pk := ti.GetPrimaryKey(); pk != nil {
+			buf.Add("primary_key", pathsToArray(pk.Paths))
+		}
+		if ti.TTLPath != nil {
+			buf.Add("ttl", types.NewTextValue(ti.TTLPath.String()))
+		}
+		buf.Add("sql", types.NewTextValue(ti.String()))
+
+		err := fn(buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Catalog) iterateSystemFields(fn func(d types.Document) error) error {
+	for _, ti := range c.listTables() {
+		var pkPaths []document.Path
+		if pk := ti.GetPrimaryKey(); pk != nil {
+			pkPaths = pk.Paths
+		}
+
+		var position int64
+		for _, fc := range ti.FieldConstraints {
+			// inferred constraints are not declared by the user
+			if fc.IsInferred {
+				continue
+			}
+			position++
+
+			tp := "any"
+			if !fc.Type.IsAny() {
+				tp = fc.Type.String()
+			}
+
+			var isPK bool
+			for _, p := range pkPaths {
+				if p.IsEqual(fc.Path) {
+					isPK = true
+				}
+			}
+
+			buf := document.NewFieldBuffer()
+			buf.Add("table_name", types.NewTextValue(ti.TableName))
+			buf.Add("position", types.NewIntegerValue(position))
+			buf.Add("path", types.NewTextValue(fc.Path.String()))
+			buf.Add("type", types.NewTextValue(tp))
+			buf.Add("not_null", types.NewBoolValue(fc.IsNotNull))
+			buf.Add("primary_key", types.NewBoolValue(isPK))
+			if fc.DefaultValue != nil {
+				buf.Add("default_expr", types.NewTextValue(fc.DefaultValue.String()))
+			}
+			if fc.GeneratedExpr != nil {
+				buf.Add("generated_expr", types.NewTextValue(fc.GeneratedExpr.String()))
+			}
+
+			err := fn(buf)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Catalog) iterateSystemIndexes(fn func(d types.Document) error) error {
+	for _, name := range c.Cache.ListObjects(RelationIndexType) {
+		info, err := c.GetIndexInfo(name)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(info.TableName, InternalPrefix) {
+			continue
+		}
+
+		buf := document.NewFieldBuffer()
+		buf.Add("name", types.NewTextValue(info.IndexName))
+		buf.Add("table_name", types.NewTextValue(info.TableName))
+		buf.Add("namespace", types.NewIntegerValue(int64(info.StoreNamespace)))
+		buf.Add("paths", pathsToArray(info.Paths))
+		buf.Add("is_unique", types.NewBoolValue(info.Unique))
+		buf.Add("sql", types.NewTextValue(info.String()))
+
+		err = fn(buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Catalog) iterateSystemSequences(fn func(d types.Document) error) error {
+	for _, name := range c.Cache.ListObjects(RelationSequenceType) {
+		if strings.HasPrefix(name, InternalPrefix) {
+			continue
+		}
+
+		seq, err := c.GetSequence(name)
+		if err != nil {
+			return err
+		}
+		info := seq.Info
+
+		buf := document.NewFieldBuffer()
+		buf.Add("name", types.NewTextValue(info.Name))
+		buf.Add("increment_by", types.NewIntegerValue(info.IncrementBy))
+		buf.Add("min_value", types.NewIntegerValue(info.Min))
+		buf.Add("max_value", types.NewIntegerValue(info.Max))
+		buf.Add("start_value", types.NewIntegerValue(info.Start))
+		buf.Add("cache_size", types.NewIntegerValue(int64(info.Cache)))
+		buf.Add("is_cyclic", types.NewBoolValue(info.Cycle))
+		if info.Owner.TableName != "" {
+			buf.Add("owner", types.NewTextValue(info.Owner.TableName))
+		}
+		buf.Add("sql", types.NewTextValue(info.String()))
+
+		err = fn(buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -62,6 +62,13 @@ func (t *Table) Insert(d types.Document) (tree.Key, types.Document, error) {
 		return nil, nil, err
 	}
 
+	if t.capturesChanges() {
+		err = t.recordChange(ChangeInsert, key, nil, d)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return key, d, nil
 }
 
@@ -71,12 +78,28 @@ func (t *Table) Delete(key tree.Key) error {
 		return errors.New("cannot write to read-only table")
 	}
 
+	var before types.Document
+	if t.capturesChanges() {
+		d, err := t.GetDocument(key)
+		if err != nil {
+			return err
+		}
+		before = d
+	}
+
 	err := t.Tree.Delete(key)
 	if errors.Is(err, kv.ErrKeyNotFound) {
 		return errs.ErrDocumentNotFound
 	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	if before != nil {
+		return t.recordChange(ChangeDelete, key, before, nil)
+	}
+
+	return nil
 }
 
 // Replace a document by key.
@@ -88,7 +111,7 @@ func (t *Table) Replace(key tree.Key, d types.Document) (types.Document, error)
 	}
 
 	// make sure key exists
-	_, err := t.Tree.Get(key)
+	old, err := t.Tree.Get(key)
 	if err != nil {
 		if errors.Is(err, kv.ErrKeyNotFound) {
 			return nil, errs.ErrDocumentNotFound
@@ -99,7 +122,18 @@ func (t *Table) Replace(key tree.Key, d types.Document) (types.Document, error)
 
 	// replace old document with new document
 	err = t.Tree.Put(key, types.NewDocumentValue(d))
-	return d, err
+	if err != nil {
+		return nil, err
+	}
+
+	if t.capturesChanges() {
+		err = t.recordChange(ChangeUpdate, key, old.V().(types.Document), d)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
 }
 
 // This document implementation waits until
@@ -1,8 +1,6 @@
 package database
 
 import (
-	"sync"
-
 	"github.com/cockroachdb/errors"
 	"github.com/genjidb/genji/internal/kv"
 )
@@ -15,16 +13,36 @@ type Transaction struct {
 	Session  *kv.Session
 	Id       uint32
 	Writable bool
-	DBMu     *sync.RWMutex
+
+	// lock of the database, released when the transaction ends.
+	dbLock *txLock
 
 	// these functions are run after a successful rollback.
 	OnRollbackHooks []func()
 	// these functions are run after a successful commit.
 	OnCommitHooks []func()
+
+	// last values returned by the sequences during the transaction.
+	sequenceValues    map[string]int64
+	lastSequenceValue *int64
+
+	// changes made to the tables, recorded if the change feed has subscribers.
+	changes *changeSet
+
+	// active savepoints, from the oldest to the most recent.
+	savepoints []*Savepoint
+
+	// true once the transaction was committed or rolled back.
+	done bool
 }
 
-// Rollback the transaction. Can be used safely after commit.
+// Rollback the transaction. Can be used safely after commit
+// or after a previous rollback, in which case it does nothing.
 func (tx *Transaction) Rollback() error {
+	if tx.done {
+		return nil
+	}
+
 	if tx.Writable {
 		err := tx.Session.Close()
 		if err != nil {
@@ -32,13 +50,8 @@ func (tx *Transaction) Rollback() error {
 		}
 	}
 
-	defer func() {
-		if tx.Writable {
-			tx.DBMu.Unlock()
-		} else {
-			tx.DBMu.RUnlock()
-		}
-	}()
+	tx.done = true
+	defer tx.dbLock.unlock(tx.Writable)
 
 	for i := len(tx.OnRollbackHooks) - 1; i >= 0; i-- {
 		tx.OnRollbackHooks[i]()
@@ -53,15 +66,23 @@ func (tx *Transaction) Commit() error {
 	if !tx.Writable {
 		return errors.New("cannot commit read-only transaction")
 	}
+	if tx.done {
+		return errors.New("transaction already closed")
+	}
 
 	err := tx.Session.Commit()
+	tx.done = true
 	if err != nil {
+		// the transaction cannot be used anymore:
+		// revert the changes made in memory and release the lock
+		for i := len(tx.OnRollbackHooks) - 1; i >= 0; i-- {
+			tx.OnRollbackHooks[i]()
+		}
+		tx.dbLock.unlock(true)
 		return err
 	}
 
-	defer func() {
-		tx.DBMu.Unlock()
-	}()
+	defer tx.dbLock.unlock(true)
 
 	for i := len(tx.OnCommitHooks) - 1; i >= 0; i-- {
 		tx.OnCommitHooks[i]()
@@ -69,3 +90,139 @@ func (tx *Transaction) Commit() error {
 
 	return nil
 }
+
+// setSequenceValue records the last value returned by a sequence during the transaction.
+func (tx *Transaction) setSequenceValue(name string, v int64) {
+	if tx.sequenceValues == nil {
+		tx.sequenceValues = make(map[string]int64)
+	}
+
+	tx.sequenceValues[name] = v
+	tx.lastSequenceValue = &v
+}
+
+// CurrentSequenceValue returns the last value returned by the given sequence
+// during the transaction.
+func (tx *Transaction) CurrentSequenceValue(name string) (int64, bool) {
+	v, ok := tx.sequenceValues[name]
+	return v, ok
+}
+
+// LastSequenceValue returns the last value returned by any sequence
+// during the transaction.
+func (tx *Transaction) LastSequenceValue() (int64, bool) {
+	if tx.lastSequenceValue == nil {
+		return 0, false
+	}
+
+	return *tx.lastSequenceValue, true
+}
+
+// A Savepoint marks the state of a transaction at a given moment.
+// Rolling back to a savepoint reverts every modification made by the transaction
+// since the savepoint was created, without ending the transaction.
+type Savepoint struct {
+	Name string
+
+	tx *Transaction
+	// undo mark of the session
+	mark int
+	// number of hooks and changes at the time of the savepoint
+	rollbackHooks int
+	commitHooks   int
+	changes       int
+}
+
+// Savepoint creates a savepoint with the given name. Savepoints can be nested:
+// if another active savepoint has the same name, it is hidden by the new one
+// until the new one is released.
+func (tx *Transaction) Savepoint(name string) *Savepoint {
+	sp := Savepoint{
+		Name:          name,
+		tx:            tx,
+		mark:          tx.Session.Savepoint(),
+		rollbackHooks: len(tx.OnRollbackHooks),
+		commitHooks:   len(tx.OnCommitHooks),
+	}
+	if tx.changes != nil {
+		sp.changes = len(tx.changes.changes)
+	}
+
+	tx.savepoints = append(tx.savepoints, &sp)
+	return &sp
+}
+
+// GetSavepoint returns the most recent active savepoint with the given name.
+func (tx *Transaction) GetSavepoint(name string) (*Savepoint, error) {
+	for i := len(tx.savepoints) - 1; i >= 0; i-- {
+		if tx.savepoints[i].Name == name {
+			return tx.savepoints[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("savepoint %q does not exist", name)
+}
+
+// position returns the index of the savepoint in the stack
+// of active savepoints of the transaction.
+func (sp *Savepoint) position() (int, error) {
+	for i := len(sp.tx.savepoints) - 1; i >= 0; i-- {
+		if sp.tx.savepoints[i] == sp {
+			return i, nil
+		}
+	}
+
+	return -1, errors.Errorf("savepoint %q is not active", sp.Name)
+}
+
+// Rollback reverts the modifications made by the transaction since the savepoint
+// was created. The savepoints created after this one are released, while this one
+// remains active and can be rolled back to again.
+func (sp *Savepoint) Rollback() error {
+	pos, err := sp.position()
+	if err != nil {
+		return err
+	}
+
+	tx := sp.tx
+	for i := len(tx.savepoints) - 1; i > pos; i-- {
+		tx.Session.Release()
+	}
+	tx.savepoints = tx.savepoints[:pos+1]
+
+	err = tx.Session.RollbackTo(sp.mark)
+	if err != nil {
+		return err
+	}
+
+	// revert the in-memory modifications, such as the ones
+	// made to the catalog, and forget about the ones to apply on commit.
+	for i := len(tx.OnRollbackHooks) - 1; i >= sp.rollbackHooks; i-- {
+		tx.OnRollbackHooks[i]()
+	}
+	tx.OnRollbackHooks = tx.OnRollbackHooks[:sp.rollbackHooks]
+	tx.OnCommitHooks = tx.OnCommitHooks[:sp.commitHooks]
+
+	if tx.changes != nil {
+		tx.changes.changes = tx.changes.changes[:sp.changes]
+	}
+
+	return nil
+}
+
+// Release the savepoint, keeping the modifications made since it was created.
+// The savepoints created after this one are released as well.
+func (sp *Savepoint) Release() error {
+	pos, err := sp.position()
+	if err != nil {
+		return err
+	}
+
+	tx := sp.tx
+	for i := len(tx.savepoints) - 1; i >= pos; i-- {
+		tx.Session.Release()
+	}
+	tx.savepoints = tx.savepoints[:pos]
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/internal/tree"
+	"github.com/genjidb/genji/types"
+)
+
+const (
+	// DefaultTTLInterval is the default delay between two deletions
+	// of the expired documents.
+	DefaultTTLInterval = time.Minute
+
+	// DefaultTTLBatchSize is the default maximum number of expired documents
+	// deleted by a single transaction.
+	DefaultTTLBatchSize = 100
+)
+
+// ExpiresAt returns the expiration time of the document, read from the TTL path of the table.
+// Text values are parsed as RFC3339 timestamps, the format used to store time.Time values,
+// and numbers are read as Unix timestamps, in seconds.
+// It returns false if the table has no TTL or if the document has no valid expiration time.
+func (ti *TableInfo) ExpiresAt(d types.Document) (time.Time, bool) {
+	if ti.TTLPath == nil {
+		return time.Time{}, false
+	}
+
+	v, err := ti.TTLPath.GetValueFromDocument(d)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch v.Type() {
+	case types.TextValue:
+		t, err := time.Parse(time.RFC3339Nano, v.V().(string))
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	case types.IntegerValue:
+		return time.Unix(v.V().(int64), 0), true
+	case types.DoubleValue:
+		f := v.V().(float64)
+		sec := int64(f)
+		return time.Unix(sec, int64((f-float64(sec))*1e9)), true
+	}
+
+	return time.Time{}, false
+}
+
+// IsExpired returns whether the document has expired at the given time.
+func (ti *TableInfo) IsExpired(d types.Document, now time.Time) bool {
+	t, ok := ti.ExpiresAt(d)
+	return ok && !t.After(now)
+}
+
+// validateTTL ensures the field used by the TTL can hold an expiration time.
+func (ti *TableInfo) validateTTL() error {
+	if ti.TTLPath == nil {
+		return nil
+	}
+
+	fc := ti.GetFieldConstraintForPath(ti.TTLPath)
+	if fc == nil {
+		return nil
+	}
+
+	switch fc.Type {
+	case types.AnyType, types.TextValue, types.IntegerValue, types.DoubleValue:
+		return nil
+	}
+
+	return fmt.Errorf("field %q of type %s cannot be used as a TTL", ti.TTLPath, fc.Type)
+}
+
+// StartTTLWorker starts a goroutine deleting the expired documents of every table
+// with a TTL, every TTLInterval. It is stopped when the database is closed.
+// If TTLInterval is zero or negative, or if the database is read-only,
+// expired documents are never deleted.
+func (db *Database) StartTTLWorker() {
+	if db.TTLInterval <= 0 || db.ReadOnly || db.ttlCancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	db.ttlCancel = cancel
+
+	db.ttlWg.Add(1)
+	go func() {
+		defer db.ttlWg.Done()
+
+		ticker := time.NewTicker(db.TTLInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// documents that couldn't be deleted will be during the next pass
+			_, _ = db.DeleteExpiredDocuments(ctx, time.Now())
+		}
+	}()
+}
+
+// stopTTLWorker stops the TTL worker and waits for it to return.
+func (db *Database) stopTTLWorker() {
+	if db.ttlCancel == nil {
+		return
+	}
+
+	db.ttlCancel()
+	db.ttlWg.Wait()
+}
+
+// DeleteExpiredDocuments deletes the documents that have expired at the given time
+// from every table with a TTL, along with their index entries.
+// Expired documents are looked up by read-only transactions, which scan each table once,
+// TTLBatchSize documents at a time. Each batch is then deleted by a small write transaction,
+// to avoid blocking the other transactions for too long. No write transaction is opened
+// if no document has expired.
+// It returns the number of deleted documents.
+func (db *Database) DeleteExpiredDocuments(ctx context.Context, now time.Time) (int, error) {
+	limit := db.TTLBatchSize
+	if limit <= 0 {
+		limit = DefaultTTLBatchSize
+	}
+
+	tables, err := db.ttlTables(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	for _, tableName := range tables {
+		// key of the last document of the previous batch
+		var from tree.Key
+
+		for {
+			if err := ctx.Err(); err != nil {
+				return total, err
+			}
+
+			keys, err := db.expiredKeys(ctx, tableName, from, now, limit)
+			if err != nil {
+				return total, err
+			}
+			if len(keys) == 0 {
+				break
+			}
+
+			n, err := db.deleteExpiredKeys(ctx, tableName, keys, now)
+			total += n
+			if err != nil {
+				return total, err
+			}
+
+			// the batch wasn't full, the whole table was scanned
+			if len(keys) < limit {
+				break
+			}
+			from = keys[len(keys)-1]
+		}
+	}
+
+	return total, nil
+}
+
+// ttlTables returns the names of the tables with a TTL.
+func (db *Database) ttlTables(ctx context.Context) ([]string, error) {
+	tx, err := db.BeginTx(ctx, &TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var tables []string
+	for _, tableName := range db.Catalog.Cache.ListObjects(RelationTableType) {
+		info, err := db.Catalog.GetTableInfo(tableName)
+		if err != nil {
+			return nil, err
+		}
+		if info.TTLPath != nil && !info.ReadOnly {
+			tables = append(tables, tableName)
+		}
+	}
+
+	return tables, nil
+}
+
+// expiredKeys returns the keys of at most limit documents of the table that
+// have expired at the given time, starting after the from key if it is not nil.
+func (db *Database) expiredKeys(ctx context.Context, tableName string, from tree.Key, now time.Time, limit int) ([]tree.Key, error) {
+	tx, err := db.BeginTx(ctx, &TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	table, err := db.Catalog.GetTable(tx, tableName)
+	if err != nil {
+		// the table was dropped in the meantime
+		if errs.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var rng *tree.Range
+	if from != nil {
+		rng = &tree.Range{Min: from, Exclusive: true}
+	}
+
+	var keys []tree.Key
+	var d lazilyDecodedDocument
+	err = table.Tree.IterateOnRange(rng, false, func(key tree.Key, v types.Value) error {
+		d.Value = v
+		if !table.Info.IsExpired(&d, now) {
+			return nil
+		}
+
+		keys = append(keys, append(tree.Key(nil), key...))
+		if len(keys) >= limit {
+			return errStop
+		}
+
+		return nil
+	})
+	if err != nil && err != errStop {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// deleteExpiredKeys deletes the documents with the given keys in a single write transaction,
+// unless they were deleted or are not expired anymore, and returns the number of deleted documents.
+func (db *Database) deleteExpiredKeys(ctx context.Context, tableName string, keys []tree.Key, now time.Time) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	table, err := db.Catalog.GetTable(tx, tableName)
+	if err != nil {
+		if errs.IsNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var n int
+	for _, key := range keys {
+		// the document may have been modified since it was found
+		d, err := table.GetDocument(key)
+		if err != nil {
+			if errors.Is(err, errs.ErrDocumentNotFound) {
+				continue
+			}
+			return 0, err
+		}
+		if !table.Info.IsExpired(d, now) {
+			continue
+		}
+
+		err = db.deleteDocument(tx, table, key, d)
+		if err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// deleteDocument deletes a document and its index entries.
+func (db *Database) deleteDocument(tx *Transaction, table *Table, key tree.Key, d types.Document) error {
+	for _, info := range db.Catalog.Cache.GetTableIndexes(table.Info.TableName) {
+		idx, err := db.Catalog.GetIndex(tx, info.IndexName)
+		if err != nil {
+			return err
+		}
+
+		vs := make([]types.Value, 0, len(info.Paths))
+		for _, path := range info.Paths {
+			v, err := path.GetValueFromDocument(d)
+			if err != nil {
+				v = types.NewNullValue()
+			}
+			vs = append(vs, v)
+		}
+
+		err = idx.Delete(vs, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return table.Delete(key)
+}
@@ -0,0 +1,162 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/testutil"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/genjidb/genji/internal/tree"
+	"github.com/genjidb/genji/types"
+	"github.com/stretchr/testify/require"
+)
+
+// selectInts runs the query and returns the integer value of the first field of every document.
+func selectInts(t *testing.T, db *database.Database, q string) []int64 {
+	t.Helper()
+
+	res := testutil.MustQuery(t, db, nil, q)
+	defer res.Close()
+
+	ints := []int64{}
+	err := res.Iterate(func(d types.Document) error {
+		var i int64
+		err := document.Scan(d, &i)
+		ints = append(ints, i)
+		return err
+	})
+	assert.NoError(t, err)
+
+	return ints
+}
+
+func TestTableInfoExpiresAt(t *testing.T) {
+	ti := database.TableInfo{TTLPath: document.NewPath("exp")}
+
+	tests := []struct {
+		doc      string
+		expected time.Time
+		ok       bool
+	}{
+		{`{"exp": 10}`, time.Unix(10, 0), true},
+		{`{"exp": 10.5}`, time.Unix(10, 5e8), true},
+		{`{"exp": "2021-01-02T03:04:05Z"}`, time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{`{"exp": "tomorrow"}`, time.Time{}, false},
+		{`{"exp": true}`, time.Time{}, false},
+		{`{"exp": null}`, time.Time{}, false},
+		{`{"a": 10}`, time.Time{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.doc, func(t *testing.T) {
+			exp, ok := ti.ExpiresAt(testutil.MakeDocument(t, test.doc))
+			require.Equal(t, test.ok, ok)
+			require.True(t, test.expected.Equal(exp))
+		})
+	}
+}
+
+func TestDeleteExpiredDocuments(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	db.TTLBatchSize = 2
+
+	testutil.MustExec(t, db, nil, `
+		CREATE TABLE test (a INT PRIMARY KEY, exp INT) WITH TTL ON exp;
+		CREATE INDEX test_exp ON test (exp);
+		CREATE TABLE foo (a INT, exp INT);
+		INSERT INTO test (a, exp) VALUES (1, 100), (2, 2000), (3, 200), (4, 300);
+		INSERT INTO test (a) VALUES (5);
+		INSERT INTO foo (a, exp) VALUES (1, 100);
+	`)
+
+	n, err := db.DeleteExpiredDocuments(context.Background(), time.Unix(1000, 0))
+	assert.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	require.Equal(t, []int64{2, 5}, selectInts(t, db, "SELECT a FROM test"))
+	// tables without a TTL are left untouched
+	require.Equal(t, []int64{1}, selectInts(t, db, "SELECT a FROM foo"))
+
+	// the index entries of the deleted documents are removed
+	tx, err := db.Begin(false)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	idx, err := db.Catalog.GetIndex(tx, "test_exp")
+	assert.NoError(t, err)
+
+	var count int
+	err = idx.Iterate(false, func(key tree.Key) error {
+		count++
+		return nil
+	})
+	assert.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	// no write transaction is opened if no document has expired:
+	// it would wait for the read-only transaction to end
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	n, err = db.DeleteExpiredDocuments(ctx, time.Unix(1000, 0))
+	assert.NoError(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestTTLWorker(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	db.TTLInterval = 10 * time.Millisecond
+	db.StartTTLWorker()
+
+	testutil.MustExec(t, db, nil, `
+		CREATE TABLE test (a INT, exp TEXT) WITH TTL ON exp;
+		INSERT INTO test (a, exp) VALUES (1, "2000-01-01T00:00:00Z"), (2, "3000-01-01T00:00:00Z");
+	`)
+
+	require.Eventually(t, func() bool {
+		return len(selectInts(t, db, "SELECT a FROM test")) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.Equal(t, []int64{2}, selectInts(t, db, "SELECT a FROM test"))
+
+	// closing the database stops the worker
+	assert.NoError(t, db.Close())
+}
+
+func TestHideExpiredDocuments(t *testing.T) {
+	db := testutil.NewTestDB(t)
+
+	testutil.MustExec(t, db, nil, `
+		CREATE TABLE test (a INT, b INT, exp INT) WITH TTL ON exp;
+		CREATE INDEX test_b ON test (b);
+		INSERT INTO test (a, b, exp) VALUES (1, 10, 100), (2, 20, 32503680000), (3, 30, 200);
+	`)
+
+	tests := []struct {
+		name string
+		q    string
+	}{
+		{"table scan", "SELECT a FROM test"},
+		{"index scan", "SELECT a FROM test WHERE b > 0"},
+		{"covering index scan", "SELECT b / 10 FROM test WHERE b > 0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db.HideExpiredDocuments = false
+			require.Equal(t, []int64{1, 2, 3}, selectInts(t, db, test.q))
+
+			db.HideExpiredDocuments = true
+			require.Equal(t, []int64{2}, selectInts(t, db, test.q))
+		})
+	}
+
+	// hidden documents are still indexed
+	db.HideExpiredDocuments = true
+	testutil.MustExec(t, db, nil, "CREATE INDEX test_a ON test (a)")
+
+	db.HideExpiredDocuments = false
+	require.Equal(t, []int64{1, 2, 3}, selectInts(t, db, "SELECT a FROM test WHERE a > 0"))
+}
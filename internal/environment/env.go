@@ -1,18 +1,36 @@
 package environment
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/genjidb/genji/document"
 	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/tree"
 	"github.com/genjidb/genji/types"
 )
 
 var (
 	TableKey = document.Path{document.PathFragment{FieldName: "$table"}}
 	DocPKKey = document.Path{document.PathFragment{FieldName: "$pk"}}
+
+	// NewDocKey and OldDocKey hold the documents written by the statement
+	// that fired a trigger.
+	NewDocKey       = document.Path{document.PathFragment{FieldName: "NEW"}}
+	OldDocKey       = document.Path{document.PathFragment{FieldName: "OLD"}}
+	TriggerDepthKey = document.Path{document.PathFragment{FieldName: "$trigger_depth"}}
+	// WrittenOldDocKey holds the document replaced or deleted by a write operator,
+	// until the AFTER triggers are fired.
+	WrittenOldDocKey = document.Path{document.PathFragment{FieldName: "$old"}}
 )
 
+// A Session holds the parameters of the connection running a statement.
+// It is implemented by the statement package.
+type Session interface {
+	StatementTimeout(db *database.Database) time.Duration
+}
+
 // A Param represents a parameter passed by the user to the statement.
 type Param struct {
 	// Name of the param
@@ -31,10 +49,23 @@ type Environment struct {
 	DB      *database.Database
 	Catalog *database.Catalog
 	Tx      *database.Transaction
+	Stats   *WriteStats
+	// Ctx interrupts the statement once it is done.
+	Ctx context.Context
+	// Session of the connection running the statement, if any.
+	Session Session
 
 	Outer *Environment
 }
 
+// WriteStats counts the documents written by a statement.
+type WriteStats struct {
+	// Number of documents inserted, replaced or deleted.
+	RowsAffected int64
+	// Key of the last inserted document, if any.
+	LastInsertKey tree.Key
+}
+
 func New(d types.Document, params ...Param) *Environment {
 	env := Environment{
 		Params: params,
@@ -150,6 +181,46 @@ func (e *Environment) GetDB() *database.Database {
 	return nil
 }
 
+// GetStats returns the write statistics of the statement, if any.
+func (e *Environment) GetStats() *WriteStats {
+	if e.Stats != nil {
+		return e.Stats
+	}
+
+	if outer := e.GetOuter(); outer != nil {
+		return outer.GetStats()
+	}
+
+	return nil
+}
+
+// GetContext returns the context of the statement.
+// If there is none, it returns context.Background.
+func (e *Environment) GetContext() context.Context {
+	if e.Ctx != nil {
+		return e.Ctx
+	}
+
+	if outer := e.GetOuter(); outer != nil {
+		return outer.GetContext()
+	}
+
+	return context.Background()
+}
+
+// GetSession returns the session of the connection running the statement, if any.
+func (e *Environment) GetSession() Session {
+	if e.Session != nil {
+		return e.Session
+	}
+
+	if outer := e.GetOuter(); outer != nil {
+		return outer.GetSession()
+	}
+
+	return nil
+}
+
 func (e *Environment) GetCatalog() *database.Catalog {
 	if e.Catalog != nil {
 		return e.Catalog
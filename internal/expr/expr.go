@@ -135,6 +135,16 @@ func Walk(e Expr, fn func(Expr) bool) bool {
 	}
 
 	switch t := e.(type) {
+	case *BetweenOperator:
+		if !Walk(t.X, fn) {
+			return false
+		}
+		if !Walk(t.LeftHand(), fn) {
+			return false
+		}
+		if !Walk(t.RightHand(), fn) {
+			return false
+		}
 	case Operator:
 		if !Walk(t.LeftHand(), fn) {
 			return false
@@ -144,6 +154,8 @@ func Walk(e Expr, fn func(Expr) bool) bool {
 		}
 	case *NamedExpr:
 		return Walk(t.Expr, fn)
+	case Parentheses:
+		return Walk(t.E, fn)
 	case Function:
 		for _, p := range t.Params() {
 			if !Walk(p, fn) {
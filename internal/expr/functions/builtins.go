@@ -61,6 +61,27 @@ var builtinFunctions = Definitions{
 			return &Avg{Expr: args[0]}, nil
 		},
 	},
+	"currval": &definition{
+		name:  "currval",
+		arity: 1,
+		constructorFn: func(args ...expr.Expr) (expr.Function, error) {
+			return &CurrVal{Expr: args[0]}, nil
+		},
+	},
+	"setval": &definition{
+		name:  "setval",
+		arity: 2,
+		constructorFn: func(args ...expr.Expr) (expr.Function, error) {
+			return &SetVal{Name: args[0], Value: args[1]}, nil
+		},
+	},
+	"lastval": &definition{
+		name:  "lastval",
+		arity: 0,
+		constructorFn: func(args ...expr.Expr) (expr.Function, error) {
+			return &LastVal{}, nil
+		},
+	},
 }
 
 // BuiltinDefinitions returns a map of builtin functions.
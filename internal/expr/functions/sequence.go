@@ -0,0 +1,175 @@
+package functions
+
+import (
+	"fmt"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/environment"
+	"github.com/genjidb/genji/internal/expr"
+	"github.com/genjidb/genji/types"
+)
+
+// evalSequenceName evaluates e and returns the name of the sequence it refers to.
+func evalSequenceName(env *environment.Environment, e expr.Expr) (string, error) {
+	v, err := e.Eval(env)
+	if err != nil {
+		return "", err
+	}
+
+	if v.Type() != types.TextValue {
+		return "", fmt.Errorf("sequence name must be a text value, got %s", v.Type())
+	}
+
+	return v.V().(string), nil
+}
+
+// getSequence returns the sequence named by e, along with the current transaction and catalog.
+func getSequence(env *environment.Environment, e expr.Expr) (*database.Sequence, *database.Transaction, *database.Catalog, error) {
+	tx := env.GetTx()
+	catalog := env.GetCatalog()
+	if tx == nil || catalog == nil {
+		return nil, nil, nil, fmt.Errorf("sequence functions cannot be evaluated outside of a transaction")
+	}
+
+	name, err := evalSequenceName(env, e)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	seq, err := catalog.GetSequence(name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return seq, tx, catalog, nil
+}
+
+// CurrVal represents the currval() function.
+// It returns the last value returned by the sequence during the current transaction.
+type CurrVal struct {
+	Expr expr.Expr
+}
+
+// Eval returns the current value of the sequence.
+func (c *CurrVal) Eval(env *environment.Environment) (types.Value, error) {
+	seq, tx, _, err := getSequence(env, c.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := tx.CurrentSequenceValue(seq.Info.Name)
+	if !ok {
+		return nil, fmt.Errorf("currval of sequence %q is not yet defined in this transaction", seq.Info.Name)
+	}
+
+	return types.NewIntegerValue(v), nil
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (c *CurrVal) IsEqual(other expr.Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	o, ok := other.(*CurrVal)
+	if !ok {
+		return false
+	}
+
+	return expr.Equal(c.Expr, o.Expr)
+}
+
+func (c *CurrVal) Params() []expr.Expr { return []expr.Expr{c.Expr} }
+
+func (c *CurrVal) String() string {
+	return fmt.Sprintf("currval(%v)", c.Expr)
+}
+
+// SetVal represents the setval() function.
+// It sets the current value of the sequence, the next call to NEXT VALUE FOR
+// returning the following value. It returns the new value.
+type SetVal struct {
+	Name  expr.Expr
+	Value expr.Expr
+}
+
+// Eval sets the current value of the sequence.
+func (s *SetVal) Eval(env *environment.Environment) (types.Value, error) {
+	seq, tx, catalog, err := getSequence(env, s.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := s.Value.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err = document.CastAsInteger(v)
+	if err != nil {
+		return nil, err
+	}
+
+	n := v.V().(int64)
+	err = seq.SetValue(tx, catalog, n)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewIntegerValue(n), nil
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (s *SetVal) IsEqual(other expr.Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	o, ok := other.(*SetVal)
+	if !ok {
+		return false
+	}
+
+	return expr.Equal(s.Name, o.Name) && expr.Equal(s.Value, o.Value)
+}
+
+func (s *SetVal) Params() []expr.Expr { return []expr.Expr{s.Name, s.Value} }
+
+func (s *SetVal) String() string {
+	return fmt.Sprintf("setval(%v, %v)", s.Name, s.Value)
+}
+
+// LastVal represents the lastval() function.
+// It returns the last value returned by any sequence during the current transaction.
+type LastVal struct{}
+
+// Eval returns the last value returned by a sequence.
+func (l *LastVal) Eval(env *environment.Environment) (types.Value, error) {
+	tx := env.GetTx()
+	if tx == nil {
+		return nil, fmt.Errorf("sequence functions cannot be evaluated outside of a transaction")
+	}
+
+	v, ok := tx.LastSequenceValue()
+	if !ok {
+		return nil, fmt.Errorf("lastval is not yet defined in this transaction")
+	}
+
+	return types.NewIntegerValue(v), nil
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (l *LastVal) IsEqual(other expr.Expr) bool {
+	_, ok := other.(*LastVal)
+	return ok
+}
+
+func (*LastVal) Params() []expr.Expr { return nil }
+
+func (l *LastVal) String() string {
+	return "lastval()"
+}
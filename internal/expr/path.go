@@ -17,10 +17,6 @@ func (p Path) Eval(env *environment.Environment) (types.Value, error) {
 		return NullLiteral, nil
 	}
 
-	d, ok := env.GetDocument()
-	if !ok {
-		return NullLiteral, types.ErrFieldNotFound
-	}
 	dp := document.Path(p)
 
 	v, ok := env.Get(dp)
@@ -28,6 +24,11 @@ func (p Path) Eval(env *environment.Environment) (types.Value, error) {
 		return v, nil
 	}
 
+	d, ok := env.GetDocument()
+	if !ok {
+		return NullLiteral, types.ErrFieldNotFound
+	}
+
 	v, err := dp.GetValueFromDocument(d)
 	if errors.Is(err, types.ErrFieldNotFound) {
 		return NullLiteral, nil
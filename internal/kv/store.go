@@ -48,6 +48,35 @@ type Session struct {
 	DB       PebbleStore
 	readOnly bool
 	closed   bool
+
+	// If set, every namespace returned by the session
+	// records the number of keys read.
+	Metrics *Metrics
+
+	// Options used to commit the session.
+	// If nil, the commit is synced to disk.
+	WriteOptions *pebble.WriteOptions
+
+	// number of active savepoints.
+	savepoints int
+	// previous state of the keys written since the
+	// first active savepoint, in the order of the writes.
+	undo []undoEntry
+}
+
+type undoEntry struct {
+	key []byte
+	// previous value of the key, nil if it didn't exist.
+	value []byte
+}
+
+// Metrics collects statistics about the operations
+// performed on the store. It is used to profile queries.
+type Metrics struct {
+	// Number of keys read, either by a lookup or an iterator.
+	Reads int64
+	// Number of bytes written to transient stores.
+	TransientBytesWritten int64
 }
 
 func NewSession(db PebbleStore, readOnly bool) *Session {
@@ -66,7 +95,7 @@ func (s *Session) Commit() error {
 	}
 
 	s.closed = true
-	return s.DB.(*pebble.Batch).Commit(nil)
+	return s.DB.(*pebble.Batch).Commit(s.WriteOptions)
 }
 
 func (s *Session) Close() error {
@@ -81,12 +110,91 @@ func (s *Session) Close() error {
 	return s.DB.(*pebble.Batch).Close()
 }
 
+// Savepoint marks the current state of the session and returns the mark.
+// Until the savepoint is released, every write records the previous
+// state of the key it modifies, so that the session can be rolled back
+// to the mark with RollbackTo.
+func (s *Session) Savepoint() int {
+	s.savepoints++
+	return len(s.undo)
+}
+
+// RollbackTo reverts every write made since the given mark was returned by Savepoint.
+// The savepoint remains active.
+func (s *Session) RollbackTo(mark int) error {
+	if s.savepoints == 0 {
+		return errors.New("no active savepoint")
+	}
+	if mark > len(s.undo) {
+		return errors.New("invalid savepoint mark")
+	}
+
+	for i := len(s.undo) - 1; i >= mark; i-- {
+		e := s.undo[i]
+
+		var err error
+		if e.value == nil {
+			err = s.DB.Delete(e.key, nil)
+		} else {
+			err = s.DB.Set(e.key, e.value, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	s.undo = s.undo[:mark]
+	return nil
+}
+
+// Release the most recent savepoint. The writes made since
+// it was created are kept. Once every savepoint is released,
+// the writes are not recorded anymore.
+func (s *Session) Release() {
+	if s.savepoints == 0 {
+		return
+	}
+
+	s.savepoints--
+	if s.savepoints == 0 {
+		s.undo = nil
+	}
+}
+
+// recordUndo records the current state of the key before it gets modified,
+// if there is an active savepoint.
+func (s *Session) recordUndo(key []byte) error {
+	if s.savepoints == 0 {
+		return nil
+	}
+
+	var e undoEntry
+	e.key = append([]byte{}, key...)
+
+	value, closer, err := s.DB.Get(key)
+	if err != nil && !errors.Is(err, pebble.ErrNotFound) {
+		return err
+	}
+	if err == nil {
+		e.value = append([]byte{}, value...)
+		err = closer.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.undo = append(s.undo, e)
+	return nil
+}
+
 // GetNamespace returns a store by name.
 func (s *Session) GetNamespace(key NamespaceID) *Namespace {
 	return &Namespace{
 		store:    s.DB,
 		ID:       key,
 		readOnly: s.readOnly,
+		metrics:  s.Metrics,
+		session:  s,
 	}
 }
 
@@ -94,6 +202,8 @@ type Namespace struct {
 	ID       NamespaceID
 	store    PebbleStore
 	readOnly bool
+	metrics  *Metrics
+	session  *Session
 }
 
 func BuildKey(nid NamespaceID, k []byte) []byte {
@@ -126,7 +236,10 @@ func (s *Namespace) Put(k, v []byte) error {
 	}
 
 	key := BuildKey(s.ID, k)
-	err := s.store.Set(key, v, nil)
+	err := s.recordUndo(key)
+	if err == nil {
+		err = s.store.Set(key, v, nil)
+	}
 	bufferPool.Put(&key)
 	return err
 }
@@ -139,6 +252,9 @@ func (s *Namespace) Get(k []byte) ([]byte, error) {
 	key := BuildKey(s.ID, k)
 	value, closer, err = s.store.Get(key)
 	bufferPool.Put(&key)
+	if s.metrics != nil {
+		s.metrics.Reads++
+	}
 	if err != nil {
 		if errors.Is(err, pebble.ErrNotFound) {
 			return nil, errors.WithStack(ErrKeyNotFound)
@@ -165,6 +281,9 @@ func (s *Namespace) Exists(k []byte) (bool, error) {
 	key := BuildKey(s.ID, k)
 	_, closer, err = s.store.Get(key)
 	bufferPool.Put(&key)
+	if s.metrics != nil {
+		s.metrics.Reads++
+	}
 	if err != nil {
 		if errors.Is(err, pebble.ErrNotFound) {
 			return false, nil
@@ -200,6 +319,11 @@ func (s *Namespace) Delete(k []byte) error {
 		return err
 	}
 
+	err = s.recordUndo(key)
+	if err != nil {
+		return err
+	}
+
 	return s.store.Delete(key, nil)
 }
 
@@ -213,7 +337,12 @@ func (s *Namespace) Truncate() error {
 	defer it.Close()
 
 	for it.SeekGE(s.ID.Bytes()); it.Valid(); it.Next() {
-		err := s.store.Delete(it.Key(), nil)
+		err := s.recordUndo(it.Key())
+		if err != nil {
+			return err
+		}
+
+		err = s.store.Delete(it.Key(), nil)
 		if err != nil {
 			return err
 		}
@@ -222,6 +351,14 @@ func (s *Namespace) Truncate() error {
 	return nil
 }
 
+func (s *Namespace) recordUndo(key []byte) error {
+	if s.session == nil {
+		return nil
+	}
+
+	return s.session.recordUndo(key)
+}
+
 func (s *Namespace) Iterator(opts *pebble.IterOptions) *Iterator {
 	var iterator Iterator
 	if opts == nil {
@@ -234,6 +371,7 @@ func (s *Namespace) Iterator(opts *pebble.IterOptions) *Iterator {
 	}
 
 	iterator.Iterator = s.store.NewIter(opts)
+	iterator.metrics = s.metrics
 	return &iterator
 }
 
@@ -241,6 +379,39 @@ type Iterator struct {
 	*pebble.Iterator
 
 	lowerBound, upperBound []byte
+	metrics                *Metrics
+}
+
+// First moves the iterator to the first key/value pair.
+// Returns true if the iterator is pointing at a valid entry and false otherwise.
+func (it *Iterator) First() bool {
+	return it.count(it.Iterator.First())
+}
+
+// Last moves the iterator to the last key/value pair.
+// Returns true if the iterator is pointing at a valid entry and false otherwise.
+func (it *Iterator) Last() bool {
+	return it.count(it.Iterator.Last())
+}
+
+// Next moves the iterator to the next key/value pair.
+// Returns true if the iterator is pointing at a valid entry and false otherwise.
+func (it *Iterator) Next() bool {
+	return it.count(it.Iterator.Next())
+}
+
+// Prev moves the iterator to the previous key/value pair.
+// Returns true if the iterator is pointing at a valid entry and false otherwise.
+func (it *Iterator) Prev() bool {
+	return it.count(it.Iterator.Prev())
+}
+
+func (it *Iterator) count(valid bool) bool {
+	if valid && it.metrics != nil {
+		it.metrics.Reads++
+	}
+
+	return valid
 }
 
 func (it *Iterator) Close() error {
@@ -229,6 +229,49 @@ func TestStoreTruncate(t *testing.T) {
 	})
 }
 
+func TestSessionSavepoint(t *testing.T) {
+	pdb := testutil.NewPebble(t)
+	batch := pdb.NewIndexedBatch()
+	defer batch.Close()
+
+	s := kv.NewSession(batch, false)
+	st := s.GetNamespace(10)
+
+	err := st.Put([]byte("foo"), []byte("FOO"))
+	assert.NoError(t, err)
+
+	mark := s.Savepoint()
+
+	err = st.Put([]byte("foo"), []byte("FOO2"))
+	assert.NoError(t, err)
+	err = st.Put([]byte("bar"), []byte("BAR"))
+	assert.NoError(t, err)
+
+	inner := s.Savepoint()
+	err = st.Truncate()
+	assert.NoError(t, err)
+
+	// revert the truncation
+	err = s.RollbackTo(inner)
+	assert.NoError(t, err)
+	s.Release()
+	require.Equal(t, []byte("FOO2"), getValue(t, st, []byte("foo")))
+	require.Equal(t, []byte("BAR"), getValue(t, st, []byte("bar")))
+
+	// revert everything since the first savepoint
+	err = s.RollbackTo(mark)
+	assert.NoError(t, err)
+	require.Equal(t, []byte("FOO"), getValue(t, st, []byte("foo")))
+	_, err = st.Get([]byte("bar"))
+	assert.ErrorIs(t, err, kv.ErrKeyNotFound)
+
+	// once released, the writes are not recorded anymore
+	s.Release()
+	err = st.Delete([]byte("foo"))
+	assert.NoError(t, err)
+	assert.Error(t, s.RollbackTo(0))
+}
+
 // TestQueries test simple queries against the kv.
 func TestQueries(t *testing.T) {
 	t.Run("SELECT", func(t *testing.T) {
@@ -17,6 +17,9 @@ type TransientStore struct {
 	DB    *pebble.DB
 	Path  string
 	batch *pebble.Batch
+
+	// If set, records the number of bytes written to the store.
+	Metrics *Metrics
 }
 
 // NewTransientStore creates a pebble db with fast options.
@@ -72,6 +75,10 @@ func (s *TransientStore) Put(k, v []byte) error {
 		s.batch = s.DB.NewIndexedBatch()
 	}
 
+	if s.Metrics != nil {
+		s.Metrics.TransientBytesWritten += int64(len(k) + len(v))
+	}
+
 	return s.batch.Set(k, v, nil)
 }
 
@@ -106,5 +113,6 @@ func (s *TransientStore) Reset() error {
 	if s.batch != nil {
 		s.batch.Reset()
 	}
+	s.Metrics = nil
 	return nil
 }
@@ -0,0 +1,114 @@
+package planner
+
+import (
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/expr"
+	"github.com/genjidb/genji/internal/stream"
+)
+
+// UseCoveringIndexRule checks if the stream reads documents from an index and
+// if every expression evaluated on these documents only references indexed paths
+// or the primary key, using pk().
+// If so, the index scan is marked as covering: documents are built from the values
+// stored in the index and the table is never read.
+// Example:
+//   CREATE INDEX foo_a ON foo(a)
+//   index.Scan('foo_a', [{"min": [1], "exact": true}]) | docs.Project(a, pk())
+//   -> index.CoveringScan('foo_a', [{"min": [1], "exact": true}]) | docs.Project(a, pk())
+// Only indexes on top-level fields are supported.
+func UseCoveringIndexRule(sctx *StreamContext) error {
+	is, ok := sctx.Stream.First().(*stream.IndexScanOperator)
+	if !ok {
+		return nil
+	}
+
+	info, err := sctx.Catalog.GetIndexInfo(is.IndexName)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range info.Paths {
+		if len(p) != 1 {
+			return nil
+		}
+	}
+
+	// once the documents of the index have been replaced by a projection
+	// or an aggregation, expressions are no longer evaluated on them.
+	replaced := false
+	for op := is.GetNext(); op != nil; op = op.GetNext() {
+		var exprs []expr.Expr
+
+		switch t := op.(type) {
+		case *stream.DocsFilterOperator:
+			exprs = append(exprs, t.Expr)
+		case *stream.DocsTempTreeSortOperator:
+			exprs = append(exprs, t.Expr)
+		case *stream.DocsTakeOperator:
+			exprs = append(exprs, t.E)
+		case *stream.DocsSkipOperator:
+			exprs = append(exprs, t.E)
+		case *stream.DocsProjectOperator:
+			exprs = append(exprs, t.Exprs...)
+		case *stream.DocsGroupAggregateOperator:
+			exprs = append(exprs, t.E)
+			for _, b := range t.Builders {
+				exprs = append(exprs, b)
+			}
+		case *stream.DocsHashAggregateOperator:
+			exprs = append(exprs, t.E)
+			for _, b := range t.Builders {
+				exprs = append(exprs, b)
+			}
+		default:
+			// any other operator may require the whole document
+			return nil
+		}
+
+		if !replaced {
+			for _, e := range exprs {
+				if !isExprCoveredBy(e, info.Paths) {
+					return nil
+				}
+			}
+		}
+
+		switch op.(type) {
+		case *stream.DocsProjectOperator, *stream.DocsGroupAggregateOperator, *stream.DocsHashAggregateOperator:
+			replaced = true
+		}
+	}
+
+	// without projection, the documents of the index would be returned as is
+	if !replaced {
+		return nil
+	}
+
+	is.Covering = true
+	return nil
+}
+
+// isExprCoveredBy returns whether every path of the expression
+// can be read from one of the given top-level paths.
+func isExprCoveredBy(e expr.Expr, paths []document.Path) bool {
+	covered := true
+
+	expr.Walk(e, func(e expr.Expr) bool {
+		switch t := e.(type) {
+		case expr.Wildcard:
+			covered = false
+		case expr.Path:
+			covered = false
+			for _, p := range paths {
+				if len(t) > 0 && t[0].FieldName != "" && t[0].FieldName == p[0].FieldName {
+					covered = true
+					break
+				}
+			}
+		}
+
+		return covered
+	})
+
+	return covered
+}
@@ -2,6 +2,7 @@ package planner
 
 import (
 	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/expr"
 	"github.com/genjidb/genji/internal/sql/scanner"
 	"github.com/genjidb/genji/internal/stream"
@@ -127,49 +128,38 @@ func (i *indexSelector) selectIndex() error {
 		}
 	}
 
-	// select the cheapest plan
-	var selected *candidate
-	var cost int
-
-	// start with the primary key of the table
 	tb, err := i.sctx.Catalog.GetTableInfo(i.tableScan.TableName)
 	if err != nil {
 		return err
 	}
-	pk := tb.GetPrimaryKey()
-	if pk != nil {
-		selected = i.associateIndexWithNodes(tb.TableName, false, false, pk.Paths, nodes)
-		if selected != nil {
-			cost = selected.Cost()
-		}
+
+	candidates, err := i.buildCandidates(tb, nodes)
+	if err != nil {
+		return err
 	}
 
-	// get all the indexes for this table and associate them
-	// with compatible candidates
-	for _, idxName := range i.sctx.Catalog.ListIndexes(i.tableScan.TableName) {
-		idxInfo, err := i.sctx.Catalog.GetIndexInfo(idxName)
-		if err != nil {
+	// if no index can be used to filter the documents directly,
+	// try reading the operands of an OR filter from multiple indexes
+	if !candidates.useFilters() {
+		ok, err := i.selectIndexUnion(tb)
+		if err != nil || ok {
 			return err
 		}
+	}
 
-		candidate := i.associateIndexWithNodes(idxInfo.IndexName, true, idxInfo.Unique, idxInfo.Paths, nodes)
-
-		if candidate == nil {
-			continue
-		}
-
-		if selected == nil {
-			selected = candidate
-			cost = selected.Cost()
-			continue
-		}
-
-		c := candidate.Cost()
+	if len(candidates) == 0 {
+		return nil
+	}
 
-		if len(selected.nodes) < len(candidate.nodes) || (len(selected.nodes) == len(candidate.nodes) && c < cost) {
-			cost = c
-			selected = candidate
-		}
+	// select the cheapest plan.
+	// if the table has been analyzed, rely on statistics,
+	// otherwise use heuristics.
+	var selected *candidate
+	stats := i.getStatistics(tb.TableName, candidates)
+	if stats != nil {
+		selected = i.selectCandidateUsingStatistics(tb.TableName, candidates, stats)
+	} else {
+		selected = i.selectCandidateUsingHeuristics(candidates)
 	}
 
 	if selected == nil {
@@ -204,6 +194,55 @@ func (i *indexSelector) selectIndex() error {
 	return nil
 }
 
+// buildCandidates associates the primary key of the table
+// and each of its indexes with the given nodes.
+func (i *indexSelector) buildCandidates(tb *database.TableInfo, nodes indexableNodes) (candidateList, error) {
+	var candidates candidateList
+
+	// start with the primary key of the table
+	pk := tb.GetPrimaryKey()
+	if pk != nil {
+		c := i.associateIndexWithNodes(tb.TableName, false, false, pk.Paths, nodes)
+		if c != nil {
+			candidates = append(candidates, c)
+		}
+	}
+
+	// get all the indexes for this table and associate them
+	// with compatible candidates
+	for _, idxName := range i.sctx.Catalog.ListIndexes(tb.TableName) {
+		idxInfo, err := i.sctx.Catalog.GetIndexInfo(idxName)
+		if err != nil {
+			return nil, err
+		}
+
+		c := i.associateIndexWithNodes(idxInfo.IndexName, true, idxInfo.Unique, idxInfo.Paths, nodes)
+		if c != nil {
+			candidates = append(candidates, c)
+		}
+	}
+
+	return candidates, nil
+}
+
+// selectCandidateUsingHeuristics returns the candidate that uses the most
+// filter nodes, or the cheapest one if they use the same number of nodes.
+func (i *indexSelector) selectCandidateUsingHeuristics(candidates candidateList) *candidate {
+	selected := candidates[0]
+	cost := selected.Cost()
+
+	for _, candidate := range candidates[1:] {
+		c := candidate.Cost()
+
+		if len(selected.nodes) < len(candidate.nodes) || (len(selected.nodes) == len(candidate.nodes) && c < cost) {
+			cost = c
+			selected = candidate
+		}
+	}
+
+	return selected
+}
+
 func (i *indexSelector) isFilterIndexable(f *stream.DocsFilterOperator) *indexableNode {
 	// only operators can associate this node to an index
 	op, ok := f.Expr.(expr.Operator)
@@ -267,6 +306,9 @@ func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, i
 
 	var hasIn bool
 	var sorter *indexableNode
+	// whether the candidate returns results in the order
+	// expected by the TempTreeSort node
+	var isSorted bool
 	for _, p := range paths {
 		ns := nodes.getByPath(p)
 		if len(ns) == 0 {
@@ -298,6 +340,7 @@ func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, i
 		if filter != nil && sorter != nil {
 			filter.orderBy = sorter
 			sorter = nil
+			isSorted = true
 		}
 
 		if filter.operator == scanner.IN {
@@ -326,8 +369,10 @@ func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, i
 		c := candidate{
 			nodes:      []*indexableNode{sorter},
 			rangesCost: 10_000,
+			treeName:   treeName,
 			isIndex:    isIndex,
 			isUnique:   isUnique,
+			isSorted:   true,
 		}
 
 		if !isIndex {
@@ -359,6 +404,7 @@ func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, i
 	// for deletion
 	if sorter != nil {
 		found[0].orderBy = sorter
+		isSorted = true
 	}
 
 	// in case there is an IN operator in the list, we need to generate multiple ranges.
@@ -374,8 +420,11 @@ func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, i
 	c := candidate{
 		nodes:      found,
 		rangesCost: ranges.Cost(),
+		treeName:   treeName,
+		ranges:     ranges,
 		isIndex:    isIndex,
 		isUnique:   isUnique,
+		isSorted:   isSorted,
 	}
 
 	if !isIndex {
@@ -587,12 +636,35 @@ type candidate struct {
 	// cost of the associated ranges
 	rangesCost int
 
+	// name of the table or index the candidate reads from
+	treeName string
+	// ranges read by the candidate, if any
+	ranges stream.Ranges
+
 	// is this candidate reading from an index.
 	// if false, we are reading from the table
 	// primary key.
 	isIndex bool
 	// if it's an index, does it have a unique constraint
 	isUnique bool
+	// does the candidate return results in the order
+	// expected by the ORDER BY clause
+	isSorted bool
+}
+
+type candidateList []*candidate
+
+// useFilters returns whether any of the candidates replaces a filter node.
+func (c candidateList) useFilters() bool {
+	for _, cd := range c {
+		for _, n := range cd.nodes {
+			if _, ok := n.node.(*stream.DocsFilterOperator); ok {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 func (c *candidate) Cost() int {
@@ -0,0 +1,144 @@
+package planner
+
+import (
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/expr"
+	"github.com/genjidb/genji/internal/sql/scanner"
+	"github.com/genjidb/genji/internal/stream"
+)
+
+// selectIndexUnion looks for a filter node whose condition is a disjunction
+// and for which every operand can be read from the primary key or from an index.
+// If found, the table scan is replaced by a union of these scans, deduplicated
+// by primary key.
+// Example:
+//   CREATE INDEX foo_a ON foo(a)
+//   CREATE INDEX foo_b ON foo(b)
+//   table.Scan('foo') | docs.Filter(a = 1 OR b = 2)
+//   -> union.Keys('foo', index.Scan('foo_a', [{"min": [1], "exact": true}]), index.Scan('foo_b', [{"min": [2], "exact": true}]))
+// Each operand can also be a conjunction, in which case the best index is selected
+// for each one of them. The filter node is only removed if every operand is entirely
+// covered by the selected scans.
+// It returns whether the stream was modified.
+func (i *indexSelector) selectIndexUnion(tb *database.TableInfo) (bool, error) {
+	for _, f := range i.sctx.Filters {
+		operands := splitORExpr(f.Expr)
+		if len(operands) < 2 {
+			continue
+		}
+
+		var selected []*candidate
+		covered := true
+		for _, e := range operands {
+			c, all, err := i.selectOperandCandidate(tb, e)
+			if err != nil {
+				return false, err
+			}
+			if c == nil {
+				selected = nil
+				break
+			}
+
+			covered = covered && all
+			selected = append(selected, c)
+		}
+
+		if len(selected) == 0 {
+			continue
+		}
+
+		// if the table has been analyzed, make sure the union
+		// is cheaper than reading the whole table
+		if stats := i.getStatistics(tb.TableName, selected); stats != nil {
+			rowCount := float64(stats[tb.TableName].RowCount)
+
+			var cost float64
+			for _, c := range selected {
+				cost += c.EstimatedCost(stats[c.treeName], rowCount, false)
+			}
+			if cost > rowCount {
+				return false, nil
+			}
+		}
+
+		streams := make([]*stream.Stream, 0, len(selected))
+		for _, c := range selected {
+			streams = append(streams, stream.New(stream.Pipe(c.replaceRootBy...)))
+		}
+
+		if covered {
+			i.sctx.removeFilterNode(f)
+		}
+
+		// we replace the seq scan node by the union
+		s := i.sctx.Stream
+		s.Remove(s.First())
+		union := stream.UnionKeys(tb.TableName, streams...)
+		if s.Op == nil {
+			s.Op = union
+		} else {
+			stream.InsertBefore(s.First(), union)
+		}
+		i.sctx.Stream = s
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// selectOperandCandidate selects the best candidate to read the documents
+// matching the given operand of a disjunction. It also returns whether the candidate
+// replaces every condition of the operand.
+func (i *indexSelector) selectOperandCandidate(tb *database.TableInfo, e expr.Expr) (*candidate, bool, error) {
+	conds := splitANDExpr(e)
+
+	nodes := make(indexableNodes, 0, len(conds))
+	for _, cond := range conds {
+		node := i.isFilterIndexable(stream.DocsFilter(cond))
+		if node == nil {
+			continue
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nil, false, nil
+	}
+
+	candidates, err := i.buildCandidates(tb, nodes)
+	if err != nil || len(candidates) == 0 {
+		return nil, false, err
+	}
+
+	var c *candidate
+	if stats := i.getStatistics(tb.TableName, candidates); stats != nil {
+		c = i.selectCandidateUsingStatistics(tb.TableName, candidates, stats)
+		if c == nil {
+			return nil, false, nil
+		}
+	} else {
+		c = i.selectCandidateUsingHeuristics(candidates)
+	}
+
+	return c, len(c.nodes) == len(conds), nil
+}
+
+// splitORExpr takes an expression and splits it by OR operator.
+// Parentheses are removed.
+func splitORExpr(cond expr.Expr) (exprs []expr.Expr) {
+	if p, ok := cond.(expr.Parentheses); ok {
+		return splitORExpr(p.E)
+	}
+
+	op, ok := cond.(expr.Operator)
+	if ok && op.Token() == scanner.OR {
+		exprs = append(exprs, splitORExpr(op.LeftHand())...)
+		exprs = append(exprs, splitORExpr(op.RightHand())...)
+		return
+	}
+
+	exprs = append(exprs, cond)
+	return
+}
@@ -17,6 +17,8 @@ var optimizerRules = []func(sctx *StreamContext) error{
 	RemoveUnnecessaryFilterNodesRule,
 	RemoveUnnecessaryTempSortNodesRule,
 	SelectIndex,
+	UseHashAggregateRule,
+	UseCoveringIndexRule,
 }
 
 // Optimize takes a tree, applies a list of optimization rules
@@ -453,3 +455,34 @@ func RemoveUnnecessaryTempSortNodesRule(sctx *StreamContext) error {
 
 	return nil
 }
+
+// UseHashAggregateRule replaces the temporary sort node and the group aggregation node
+// of a GROUP BY clause by a hash aggregation node, which doesn't need to write the whole stream
+// to a transient store.
+//		SELECT a, COUNT(*) FROM foo GROUP BY a
+//		table.Scan('foo') | docs.TempTreeSort(a) | docs.GroupAggregate(a, COUNT(*))
+//		-> table.Scan('foo') | docs.HashAggregate(a, COUNT(*))
+// Groups are returned in the same order by both nodes. If the stream is already sorted,
+// e.g. because the SelectIndex rule replaced the sort node by an index,
+// or if the groups must be returned in descending order, the stream is left untouched.
+func UseHashAggregateRule(sctx *StreamContext) error {
+	for n := sctx.Stream.First(); n != nil; n = n.GetNext() {
+		ga, ok := n.(*stream.DocsGroupAggregateOperator)
+		if !ok || ga.E == nil {
+			continue
+		}
+
+		ts, ok := ga.GetPrev().(*stream.DocsTempTreeSortOperator)
+		if !ok || ts.Desc || !expr.Equal(ts.Expr, ga.E) {
+			return nil
+		}
+
+		sctx.removeTempTreeNodeNode(ts)
+		stream.InsertBefore(ga, stream.DocsHashAggregate(ga.E, ga.Builders...))
+		sctx.Stream.Remove(ga)
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package planner
+
+import (
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/expr"
+	"github.com/genjidb/genji/internal/stream"
+	"github.com/genjidb/genji/types"
+)
+
+// getStatistics returns the statistics of the table and of every tree
+// read by the candidates. If any of them is missing, it returns nil.
+func (i *indexSelector) getStatistics(tableName string, candidates []*candidate) map[string]*database.Statistics {
+	stats := make(map[string]*database.Statistics, len(candidates)+1)
+
+	s, err := i.sctx.Catalog.GetStatistics(tableName)
+	if err != nil {
+		return nil
+	}
+	stats[tableName] = s
+
+	for _, c := range candidates {
+		if _, ok := stats[c.treeName]; ok {
+			continue
+		}
+
+		s, err := i.sctx.Catalog.GetStatistics(c.treeName)
+		if err != nil {
+			return nil
+		}
+		stats[c.treeName] = s
+	}
+
+	return stats
+}
+
+// selectCandidateUsingStatistics estimates the number of rows read by each candidate
+// and returns the cheapest one. If reading the whole table is cheaper than using
+// any of the candidates, it returns nil.
+func (i *indexSelector) selectCandidateUsingStatistics(tableName string, candidates []*candidate, stats map[string]*database.Statistics) *candidate {
+	rowCount := float64(stats[tableName].RowCount)
+	needsSort := len(i.sctx.TempTreeSorts) > 0
+
+	// cost of a table scan
+	tableScanCost := rowCount
+	if needsSort {
+		tableScanCost += rowCount
+	}
+
+	var selected *candidate
+	var cost float64
+	for _, c := range candidates {
+		cc := c.EstimatedCost(stats[c.treeName], rowCount, needsSort)
+		if selected == nil || cc < cost {
+			selected = c
+			cost = cc
+		}
+	}
+
+	if cost > tableScanCost {
+		return nil
+	}
+
+	return selected
+}
+
+// EstimatedCost returns the cost of the candidate based on the number
+// of rows it is expected to read.
+// Reading from an index requires fetching each document from the table,
+// which doubles the cost. If the candidate doesn't return results in
+// the expected order, sorting them adds to the cost as well.
+func (c *candidate) EstimatedCost(s *database.Statistics, rowCount float64, needsSort bool) float64 {
+	rows := rowCount
+	if len(c.ranges) > 0 {
+		rows = 0
+		for i := range c.ranges {
+			rows += estimateRangeRows(s, &c.ranges[i])
+		}
+
+		if rows > rowCount {
+			rows = rowCount
+		}
+	}
+
+	cost := rows
+	if c.isIndex {
+		cost += rows
+	}
+	if needsSort && !c.isSorted {
+		cost += rows
+	}
+
+	return cost
+}
+
+// estimateRangeRows estimates the number of keys of the tree that are within the range.
+// Literal values are compared against the histogram, other expressions
+// rely on the number of distinct values.
+func estimateRangeRows(s *database.Statistics, r *stream.Range) float64 {
+	n := len(r.Min)
+	if n == 0 {
+		n = len(r.Max)
+	}
+	if n == 0 {
+		return float64(s.RowCount)
+	}
+
+	if r.Exact {
+		if n == 1 {
+			if v, ok := literalValue(r.Min[0]); ok {
+				return s.EstimateEqual([]types.Value{v})
+			}
+		}
+
+		return estimatePrefixRows(s, n)
+	}
+
+	// all the values but the last one are compared using the = operator
+	if n > 1 {
+		// without histogram for the last value, assume a third of the rows match
+		return estimatePrefixRows(s, n-1) / 3
+	}
+
+	var min, max types.Value
+	if r.Min != nil {
+		v, ok := literalValue(r.Min[0])
+		if !ok {
+			return float64(s.RowCount) / 3
+		}
+		min = v
+	}
+	if r.Max != nil {
+		v, ok := literalValue(r.Max[0])
+		if !ok {
+			return float64(s.RowCount) / 3
+		}
+		max = v
+	}
+
+	return s.EstimateRange(min, max)
+}
+
+// estimatePrefixRows estimates the number of keys sharing the same n first values.
+func estimatePrefixRows(s *database.Statistics, n int) float64 {
+	if n > len(s.Distinct) {
+		n = len(s.Distinct)
+	}
+	if n == 0 {
+		return float64(s.RowCount)
+	}
+	if s.Distinct[n-1] == 0 {
+		return 0
+	}
+
+	return float64(s.RowCount) / float64(s.Distinct[n-1])
+}
+
+func literalValue(e expr.Expr) (types.Value, bool) {
+	lv, ok := e.(expr.LiteralValue)
+	if !ok {
+		return nil, false
+	}
+
+	return lv.Value, true
+}
@@ -2,11 +2,11 @@ package query
 
 import (
 	"context"
+	"time"
 
 	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/environment"
 	"github.com/genjidb/genji/internal/query/statement"
-	"github.com/genjidb/genji/types"
 )
 
 // A Query can execute statements against the database. It can read or write data
@@ -24,10 +24,11 @@ func New(statements ...statement.Statement) Query {
 }
 
 type Context struct {
-	Ctx    context.Context
-	DB     *database.Database
-	Tx     *database.Transaction
-	Params []environment.Param
+	Ctx     context.Context
+	DB      *database.Database
+	Tx      *database.Transaction
+	Params  []environment.Param
+	Session *statement.Session
 }
 
 func (c *Context) GetTx() *database.Transaction {
@@ -111,7 +112,8 @@ func (q Query) Run(context *Context) (*statement.Result, error) {
 		if qa, ok := stmt.(queryAlterer); ok {
 			err = qa.alterQuery(ctx, context.DB, &q)
 			if err != nil {
-				if tx := context.GetTx(); tx != nil {
+				// failing savepoint statements leave the transaction active
+				if tx := context.GetTx(); tx != nil && !isSavepointStmt(stmt) {
 					tx.Rollback()
 				}
 				return nil, err
@@ -129,13 +131,18 @@ func (q Query) Run(context *Context) (*statement.Result, error) {
 			}
 		}
 
+		stmtCtx, cancel := withStatementTimeout(ctx, context.Session.StatementTimeout(context.DB))
+
 		res, err = stmt.Run(&statement.Context{
+			Ctx:     stmtCtx,
 			DB:      context.DB,
 			Tx:      q.tx,
 			Catalog: context.DB.Catalog,
 			Params:  context.Params,
+			Session: context.Session,
 		})
 		if err != nil {
+			cancel()
 			if q.autoCommit {
 				q.tx.Rollback()
 			}
@@ -143,12 +150,20 @@ func (q Query) Run(context *Context) (*statement.Result, error) {
 			return nil, err
 		}
 
+		// the last result is iterated by the caller, it
+		// releases the context once closed
+		if i+1 == len(q.Statements) {
+			res.Cancel = cancel
+			continue
+		}
+
 		// if there are still statements to be executed,
 		// and the current statement is not read-only,
 		// iterate over the result.
-		if !stmt.IsReadOnly() && i+1 < len(q.Statements) {
-			err = res.Iterate(func(d types.Document) error { return nil })
+		if !stmt.IsReadOnly() {
+			err = res.Skip()
 			if err != nil {
+				cancel()
 				if q.autoCommit {
 					q.tx.Rollback()
 				}
@@ -156,10 +171,11 @@ func (q Query) Run(context *Context) (*statement.Result, error) {
 				return nil, err
 			}
 		}
+		cancel()
 
 		// it there is an opened transaction but there are still statements
 		// to be executed, close the current transaction.
-		if q.tx != nil && q.autoCommit && i+1 < len(q.Statements) {
+		if q.tx != nil && q.autoCommit {
 			if q.tx.Writable {
 				err := q.tx.Commit()
 				if err != nil {
@@ -184,6 +200,17 @@ func (q Query) Run(context *Context) (*statement.Result, error) {
 	return &res, nil
 }
 
+// withStatementTimeout returns a context that is canceled once the statement timeout
+// is elapsed. If the timeout is zero, the context can only be canceled
+// by calling the returned function.
+func withStatementTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
 type queryAlterer interface {
 	alterQuery(ctx context.Context, db *database.Database, q *Query) error
 }
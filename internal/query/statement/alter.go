@@ -1,9 +1,16 @@
 package statement
 
 import (
+	"fmt"
+	"math"
+
 	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/document"
 	errs "github.com/genjidb/genji/errors"
 	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/expr"
+	"github.com/genjidb/genji/internal/stream"
+	"github.com/genjidb/genji/types"
 )
 
 // AlterStmt is a DSL that allows creating a full ALTER TABLE query.
@@ -58,5 +65,319 @@ func (stmt AlterTableAddField) Run(ctx *Context) (Result, error) {
 	}
 
 	err := ctx.Catalog.AddFieldConstraint(ctx.Tx, stmt.Info.TableName, fc, stmt.Info.TableConstraints)
+	if err != nil || fc == nil || !fc.IsGenerated() {
+		return res, err
+	}
+
+	// compute the new generated field for every document of the table.
+	s := stream.New(&stream.TableScanOperator{TableName: stmt.Info.TableName, IncludeExpired: true}).
+		Pipe(stream.TableValidate(stmt.Info.TableName)).
+		Pipe(&stream.TableReplaceOperator{Name: stmt.Info.TableName, SkipTriggers: true})
+
+	ss := PreparedStreamStmt{
+		Stream:   s,
+		ReadOnly: false,
+	}
+
+	return ss.Run(ctx)
+}
+
+// AlterTableDropField is a DSL that allows creating a full ALTER TABLE DROP FIELD statement.
+type AlterTableDropField struct {
+	TableName string
+	Field     string
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt AlterTableDropField) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the ALTER TABLE DROP FIELD statement in the given transaction.
+// The constraints of the field are removed and the field is deleted from every document of the table.
+// It implements the Statement interface.
+func (stmt AlterTableDropField) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	ti, err := ctx.Catalog.GetTableInfo(stmt.TableName)
+	if err != nil {
+		return res, err
+	}
+
+	if tc := checkConstraintUsingField(ti, stmt.Field); tc != nil {
+		return res, fmt.Errorf("cannot drop field %q: it is used by the constraint %s", stmt.Field, tc)
+	}
+
+	if fc := generatedFieldUsingField(ti, stmt.Field); fc != nil {
+		return res, fmt.Errorf("cannot drop field %q: it is used by the generated field %s", stmt.Field, fc.Path)
+	}
+
+	err = ctx.Catalog.DropFieldConstraint(ctx.Tx, stmt.TableName, stmt.Field)
+	if err != nil {
+		return res, err
+	}
+
+	s := stream.New(&stream.TableScanOperator{TableName: stmt.TableName, IncludeExpired: true}).
+		Pipe(stream.PathsUnset(stmt.Field)).
+		Pipe(&stream.TableReplaceOperator{Name: stmt.TableName, SkipTriggers: true})
+
+	ss := PreparedStreamStmt{
+		Stream:   s,
+		ReadOnly: false,
+	}
+
+	return ss.Run(ctx)
+}
+
+// AlterTableRenameField is a DSL that allows creating a full ALTER TABLE RENAME FIELD statement.
+type AlterTableRenameField struct {
+	TableName string
+	Field     string
+	NewName   string
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt AlterTableRenameField) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the ALTER TABLE RENAME FIELD statement in the given transaction.
+// The field is renamed in the constraints of the table, in its indexes and in every document of the table.
+// It implements the Statement interface.
+func (stmt AlterTableRenameField) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	if stmt.Field == stmt.NewName {
+		return res, errs.AlreadyExistsError{Name: stmt.NewName}
+	}
+
+	ti, err := ctx.Catalog.GetTableInfo(stmt.TableName)
+	if err != nil {
+		return res, err
+	}
+
+	if tc := checkConstraintUsingField(ti, stmt.Field); tc != nil {
+		return res, fmt.Errorf("cannot rename field %q: it is used by the constraint %s", stmt.Field, tc)
+	}
+
+	if fc := generatedFieldUsingField(ti, stmt.Field); fc != nil {
+		return res, fmt.Errorf("cannot rename field %q: it is used by the generated field %s", stmt.Field, fc.Path)
+	}
+
+	err = ctx.Catalog.RenameFieldConstraint(ctx.Tx, stmt.TableName, stmt.Field, stmt.NewName)
+	if err != nil {
+		return res, err
+	}
+
+	s := stream.New(&stream.TableScanOperator{TableName: stmt.TableName, IncludeExpired: true}).
+		Pipe(stream.PathsRenameField(stmt.Field, stmt.NewName)).
+		Pipe(&stream.TableReplaceOperator{Name: stmt.TableName, SkipTriggers: true})
+
+	ss := PreparedStreamStmt{
+		Stream:   s,
+		ReadOnly: false,
+	}
+
+	return ss.Run(ctx)
+}
+
+// AlterTableAlterFieldType is a DSL that allows creating a full ALTER TABLE ALTER FIELD ... TYPE statement.
+type AlterTableAlterFieldType struct {
+	TableName string
+	Path      document.Path
+	Type      types.ValueType
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt AlterTableAlterFieldType) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the ALTER TABLE ALTER FIELD ... TYPE statement in the given transaction.
+// Every document of the table is validated and converted to the new type, and the indexes
+// of the table are updated accordingly.
+// It implements the Statement interface.
+func (stmt AlterTableAlterFieldType) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	err := ctx.Catalog.AlterFieldConstraintType(ctx.Tx, stmt.TableName, stmt.Path, stmt.Type)
+	if err != nil {
+		return res, err
+	}
+
+	s := stream.New(&stream.TableScanOperator{TableName: stmt.TableName, IncludeExpired: true})
+
+	// generated fields are computed again during validation
+	ti, err := ctx.Catalog.GetTableInfo(stmt.TableName)
+	if err != nil {
+		return res, err
+	}
+	for _, fc := range ti.FieldConstraints {
+		if fc.IsGenerated() {
+			s = s.Pipe(stream.PathsUnset(fc.Path[0].FieldName))
+		}
+	}
+
+	s = s.Pipe(stream.TableValidate(stmt.TableName))
+
+	indexNames := ctx.Catalog.ListIndexes(stmt.TableName)
+	for _, indexName := range indexNames {
+		s = s.Pipe(stream.IndexDelete(indexName))
+	}
+
+	s = s.Pipe(&stream.TableReplaceOperator{Name: stmt.TableName, SkipTriggers: true})
+
+	for _, indexName := range indexNames {
+		s = s.Pipe(stream.IndexInsert(indexName))
+	}
+
+	ss := PreparedStreamStmt{
+		Stream:   s,
+		ReadOnly: false,
+	}
+
+	return ss.Run(ctx)
+}
+
+// checkConstraintUsingField returns the first CHECK constraint of the table
+// that references the given top-level field, if any.
+func checkConstraintUsingField(ti *database.TableInfo, field string) *database.TableConstraint {
+	for _, tc := range ti.TableConstraints {
+		if tableExprUsesField(tc.Check, field) {
+			return tc
+		}
+	}
+
+	return nil
+}
+
+// generatedFieldUsingField returns the first generated field of the table
+// whose expression references the given top-level field, if any.
+func generatedFieldUsingField(ti *database.TableInfo, field string) *database.FieldConstraint {
+	for _, fc := range ti.FieldConstraints {
+		if tableExprUsesField(fc.GeneratedExpr, field) {
+			return fc
+		}
+	}
+
+	return nil
+}
+
+func tableExprUsesField(te database.TableExpression, field string) bool {
+	ce, ok := te.(*expr.ConstraintExpr)
+	if !ok {
+		return false
+	}
+
+	var found bool
+	expr.Walk(ce.Expr, func(e expr.Expr) bool {
+		if p, ok := e.(expr.Path); ok && len(p) > 0 && p[0].FieldName == field {
+			found = true
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// AlterSequenceStmt is a DSL that allows creating a full ALTER SEQUENCE statement.
+// Options that are not set are left unchanged.
+type AlterSequenceStmt struct {
+	SequenceName string
+	IncrementBy  *int64
+	Min, Max     *int64
+	NoMin, NoMax bool
+	Start        *int64
+	Cache        *uint64
+	Cycle        *bool
+
+	// If Restart is true, the sequence restarts at RestartWith,
+	// or at its start value if RestartWith is nil.
+	Restart     bool
+	RestartWith *int64
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt AlterSequenceStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the ALTER SEQUENCE statement in the given transaction.
+// It implements the Statement interface.
+func (stmt AlterSequenceStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	seq, err := ctx.Catalog.GetSequence(stmt.SequenceName)
+	if err != nil {
+		return res, err
+	}
+
+	info := seq.Info.Clone()
+
+	if stmt.IncrementBy != nil {
+		info.IncrementBy = *stmt.IncrementBy
+	}
+
+	// NO MINVALUE and NO MAXVALUE use the defaults
+	// of the direction of the sequence
+	asc := info.IncrementBy > 0
+
+	switch {
+	case stmt.Min != nil:
+		info.Min = *stmt.Min
+	case stmt.NoMin && asc:
+		info.Min = 1
+	case stmt.NoMin:
+		info.Min = math.MinInt64
+	}
+
+	switch {
+	case stmt.Max != nil:
+		info.Max = *stmt.Max
+	case stmt.NoMax && asc:
+		info.Max = math.MaxInt64
+	case stmt.NoMax:
+		info.Max = -1
+	}
+
+	if stmt.Start != nil {
+		info.Start = *stmt.Start
+	}
+
+	if stmt.Cache != nil {
+		info.Cache = *stmt.Cache
+	}
+
+	if stmt.Cycle != nil {
+		info.Cycle = *stmt.Cycle
+	}
+
+	if info.Min > info.Max {
+		return res, fmt.Errorf("MINVALUE (%d) must be less than MAXVALUE (%d)", info.Min, info.Max)
+	}
+	if info.Start < info.Min {
+		return res, fmt.Errorf("START value (%d) cannot be less than MINVALUE (%d)", info.Start, info.Min)
+	}
+	if info.Start > info.Max {
+		return res, fmt.Errorf("START value (%d) cannot be greater than MAXVALUE (%d)", info.Start, info.Max)
+	}
+
+	var restart *int64
+	if stmt.Restart {
+		restart = &info.Start
+		if stmt.RestartWith != nil {
+			restart = stmt.RestartWith
+		}
+
+		if *restart < info.Min {
+			return res, fmt.Errorf("RESTART value (%d) cannot be less than MINVALUE (%d)", *restart, info.Min)
+		}
+		if *restart > info.Max {
+			return res, fmt.Errorf("RESTART value (%d) cannot be greater than MAXVALUE (%d)", *restart, info.Max)
+		}
+	}
+
+	err = ctx.Catalog.AlterSequence(ctx.Tx, stmt.SequenceName, info, restart)
 	return res, err
 }
@@ -47,3 +47,49 @@ func TestAlterTable(t *testing.T) {
 	err = db.Exec("ALTER TABLE __genji_catalog RENAME TO bar")
 	assert.Error(t, err)
 }
+
+func TestAlterTableField(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := genji.Open(dir)
+	assert.NoError(t, err)
+
+	err = db.Exec(`
+		CREATE TABLE foo(a INT PRIMARY KEY, b INT, c TEXT);
+		CREATE INDEX foo_b_idx ON foo(b);
+		INSERT INTO foo (a, b, c) VALUES (1, 10, 'x'), (2, 20, 'y');
+	`)
+	assert.NoError(t, err)
+
+	// A failed conversion must leave both the schema and the data untouched.
+	err = db.Exec("ALTER TABLE foo ALTER FIELD c TYPE INT")
+	assert.Error(t, err)
+
+	d, err := db.QueryDocument("SELECT sql FROM __genji_catalog WHERE name = 'foo'")
+	assert.NoError(t, err)
+	data, err := document.MarshalJSON(d)
+	assert.NoError(t, err)
+	require.JSONEq(t, `{"sql": "CREATE TABLE foo (a INTEGER, b INTEGER, c TEXT, PRIMARY KEY (a))"}`, string(data))
+
+	err = db.Exec("ALTER TABLE foo RENAME FIELD b TO z")
+	assert.NoError(t, err)
+	err = db.Exec("ALTER TABLE foo DROP FIELD c")
+	assert.NoError(t, err)
+
+	err = db.Close()
+	assert.NoError(t, err)
+
+	// Changes must be reflected in the catalog after reopening the database.
+	db, err = genji.Open(dir)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	d, err = db.QueryDocument("SELECT * FROM foo WHERE z = 20")
+	assert.NoError(t, err)
+	data, err = document.MarshalJSON(d)
+	assert.NoError(t, err)
+	require.JSONEq(t, `{"a": 2, "z": 20}`, string(data))
+
+	err = db.Exec("INSERT INTO foo (a, z, c) VALUES (3, 'foo', 1)")
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package statement
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/internal/database"
+)
+
+// AnalyzeStmt is a DSL that allows creating an ANALYZE statement.
+// It collects statistics about the content of tables and indexes
+// which are then used by the planner to select the best indexes.
+type AnalyzeStmt struct {
+	TableName string
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt AnalyzeStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the Analyze statement in the given transaction.
+// If no table name is provided, every user table is analyzed.
+// It implements the Statement interface.
+func (stmt AnalyzeStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	if stmt.TableName != "" {
+		if strings.HasPrefix(stmt.TableName, database.InternalPrefix) {
+			return res, errors.Errorf("cannot analyze system table %q", stmt.TableName)
+		}
+
+		return res, ctx.Catalog.AnalyzeTable(ctx.Tx, stmt.TableName)
+	}
+
+	for _, tableName := range ctx.Catalog.Cache.ListObjects(database.RelationTableType) {
+		if strings.HasPrefix(tableName, database.InternalPrefix) {
+			continue
+		}
+
+		err := ctx.Catalog.AnalyzeTable(ctx.Tx, tableName)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,214 @@
+package statement
+
+import (
+	"fmt"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/expr"
+	"github.com/genjidb/genji/internal/expr/functions"
+	"github.com/genjidb/genji/internal/sql/scanner"
+	"github.com/genjidb/genji/internal/stream"
+	"github.com/genjidb/genji/types"
+)
+
+// Column describes a field of the documents returned by a statement.
+type Column struct {
+	Name string
+	// Type of the values of the field, or types.AnyType if it cannot
+	// be determined without running the statement.
+	Type types.ValueType
+	// Nullable reports whether the field can be null or missing.
+	// It is always true if the type is unknown.
+	Nullable bool
+}
+
+// Columns returns the fields of the documents returned by the stream.
+// Their types are inferred from the projected expressions and the field constraints
+// of the table the documents come from.
+// If the documents are not projected, they are described by a single "*" column.
+func (s *StreamStmtIterator) Columns() []Column {
+	if s.Stream.Op == nil {
+		return nil
+	}
+
+	var catalog *database.Catalog
+	if s.Context != nil {
+		catalog = s.Context.Catalog
+	}
+
+	var info *database.TableInfo
+
+	for op := s.Stream.First(); op != nil; op = op.GetNext() {
+		switch t := op.(type) {
+		case *stream.DocsProjectOperator:
+			// if there are no projected expression, it's a wildcard
+			if len(t.Exprs) == 0 {
+				return []Column{{Name: "*", Type: types.DocumentValue}}
+			}
+
+			columns := make([]Column, len(t.Exprs))
+			for i, e := range t.Exprs {
+				// fields are named like by the projected documents
+				if ne, ok := e.(*expr.NamedExpr); ok {
+					columns[i].Name = ne.Name()
+				} else {
+					columns[i].Name = fmt.Sprintf("%s", e)
+				}
+				columns[i].Type, columns[i].Nullable = exprType(e, info)
+			}
+
+			return columns
+		case *stream.PathsSetOperator, *stream.PathsUnsetOperator, *stream.PathsRenameOperator, *stream.PathsRenameFieldOperator:
+			// the documents don't match the table anymore
+			info = nil
+		default:
+			if catalog == nil {
+				break
+			}
+			if name := operatorTableName(op, catalog); name != "" {
+				info, _ = catalog.GetTableInfo(name)
+			}
+		}
+	}
+
+	// the stream will output documents in a single field
+	return []Column{{Name: "*", Type: types.DocumentValue}}
+}
+
+// operatorTableName returns the name of the table whose documents are output by op, if any.
+func operatorTableName(op stream.Operator, catalog *database.Catalog) string {
+	switch t := op.(type) {
+	case *stream.TableScanOperator:
+		return t.TableName
+	case *stream.UnionKeysOperator:
+		return t.TableName
+	case *stream.IndexScanOperator:
+		info, err := catalog.GetIndexInfo(t.IndexName)
+		if err != nil {
+			return ""
+		}
+		return info.TableName
+	case *stream.TableInsertOperator:
+		return t.Name
+	case *stream.TableReplaceOperator:
+		return t.Name
+	case *stream.TableDeleteOperator:
+		return t.Name
+	}
+
+	return ""
+}
+
+// exprType returns the type of the values of e and whether they can be null.
+// Paths are typed using the field constraints of the given table, if any.
+func exprType(e expr.Expr, info *database.TableInfo) (types.ValueType, bool) {
+	switch t := e.(type) {
+	case *expr.NamedExpr:
+		return exprType(t.Expr, info)
+	case expr.Parentheses:
+		return exprType(t.E, info)
+	case expr.LiteralValue:
+		if t.Value.Type() == types.NullValue {
+			return types.AnyType, true
+		}
+		return t.Value.Type(), false
+	case expr.LiteralExprList:
+		return types.ArrayValue, false
+	case *expr.KVPairs:
+		return types.DocumentValue, false
+	case expr.Wildcard:
+		return types.DocumentValue, false
+	case expr.Path:
+		return pathType(document.Path(t), info)
+	case expr.Cast:
+		_, nullable := exprType(t.Expr, info)
+		return t.CastAs, nullable
+	case *expr.NotOp:
+		_, nullable := exprType(t.LeftHand(), info)
+		return types.BoolValue, nullable
+	case *expr.IsOperator, *expr.IsNotOperator:
+		return types.BoolValue, false
+	case *expr.ConcatOperator:
+		// the concatenation of values that are not texts is null
+		return types.TextValue, true
+	case *functions.Count:
+		return types.IntegerValue, false
+	case *functions.TypeOf:
+		return types.TextValue, false
+	case *functions.Avg:
+		return types.DoubleValue, true
+	case *functions.Min:
+		tp, _ := exprType(t.Expr, info)
+		return tp, true
+	case *functions.Max:
+		tp, _ := exprType(t.Expr, info)
+		return tp, true
+	case expr.Operator:
+		return operatorType(t, info)
+	}
+
+	return types.AnyType, true
+}
+
+func pathType(p document.Path, info *database.TableInfo) (types.ValueType, bool) {
+	if info == nil {
+		return types.AnyType, true
+	}
+
+	fc := info.GetFieldConstraintForPath(p)
+	if fc == nil || fc.Type.IsAny() {
+		return types.AnyType, true
+	}
+
+	if fc.IsNotNull {
+		return fc.Type, false
+	}
+
+	// primary keys cannot be null
+	if pk := info.GetPrimaryKey(); pk != nil {
+		for _, pp := range pk.Paths {
+			if pp.IsEqual(p) {
+				return fc.Type, false
+			}
+		}
+	}
+
+	return fc.Type, true
+}
+
+func operatorType(op expr.Operator, info *database.TableInfo) (types.ValueType, bool) {
+	ltp, lnull := exprType(op.LeftHand(), info)
+	rtp, rnull := exprType(op.RightHand(), info)
+	nullable := lnull || rnull
+
+	// comparison and logical operators
+	if !expr.IsArithmeticOperator(op) {
+		return types.BoolValue, nullable
+	}
+
+	// operations on values that are not numbers and
+	// divisions by zero are null
+	switch op.Token() {
+	case scanner.DIV, scanner.MOD:
+		nullable = true
+	}
+
+	switch {
+	case ltp == types.IntegerValue && rtp == types.IntegerValue:
+		switch op.Token() {
+		case scanner.ADD, scanner.SUB, scanner.MUL:
+			// integers that overflow are converted to doubles
+			return types.AnyType, true
+		}
+		return types.IntegerValue, nullable
+	case ltp.IsNumber() && rtp.IsNumber():
+		switch op.Token() {
+		case scanner.BITWISEAND, scanner.BITWISEOR, scanner.BITWISEXOR:
+			return types.AnyType, true
+		}
+		return types.DoubleValue, nullable
+	}
+
+	return types.AnyType, true
+}
@@ -1,17 +1,23 @@
 package statement
 
 import (
+	"fmt"
 	"math"
 
+	"github.com/genjidb/genji/document"
 	errs "github.com/genjidb/genji/errors"
 	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/stream"
+	"github.com/genjidb/genji/internal/tree"
+	"github.com/genjidb/genji/types"
 )
 
 // CreateTableStmt represents a parsed CREATE TABLE statement.
+// If SelectStmt is set, the table is filled with the result of the query.
 type CreateTableStmt struct {
 	IfNotExists bool
 	Info        database.TableInfo
+	SelectStmt  Preparer
 }
 
 // IsReadOnly always returns false. It implements the Statement interface.
@@ -49,6 +55,9 @@ func (stmt *CreateTableStmt) Run(ctx *Context) (Result, error) {
 			return res, nil
 		}
 	}
+	if err != nil {
+		return res, err
+	}
 
 	// create a unique index for every unique constraint
 	for _, tc := range stmt.Info.TableConstraints {
@@ -68,9 +77,140 @@ func (stmt *CreateTableStmt) Run(ctx *Context) (Result, error) {
 		}
 	}
 
+	if stmt.SelectStmt != nil {
+		err = stmt.insertSelect(ctx)
+	}
+
 	return res, err
 }
 
+// insertSelect inserts the result of the select statement in the table.
+func (stmt *CreateTableStmt) insertSelect(ctx *Context) error {
+	ins := NewInsertStatement()
+	ins.TableName = stmt.Info.TableName
+	ins.SelectStmt = stmt.SelectStmt
+
+	// if no field was declared, infer them from the selected values
+	// before inserting, as values of untyped fields are converted.
+	// The selected documents are buffered, to only run the query once
+	// and insert the documents the fields were inferred from.
+	if len(stmt.Info.FieldConstraints) == 0 {
+		tr, cleanup, err := database.NewTransientTree(ctx.DB)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		err = stmt.inferFieldConstraints(ctx, tr)
+		if err != nil {
+			return err
+		}
+
+		ins.SelectStmt = &bufferedSelect{tree: tr}
+	}
+
+	st, err := ins.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := st.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	return res.Iterate(func(d types.Document) error { return nil })
+}
+
+// inferFieldConstraints runs the select statement, stores the selected documents in the tree
+// and adds a field constraint to the table for every top-level field whose non-null values
+// all have the same type.
+func (stmt *CreateTableStmt) inferFieldConstraints(ctx *Context, tr *tree.Tree) error {
+	st, err := stmt.SelectStmt.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := st.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	var counter int64
+	var fields []string
+	fieldTypes := make(map[string]types.ValueType)
+	err = res.Iterate(func(d types.Document) error {
+		// projected values are evaluated every time they are read,
+		// copy them to insert the values the fields are inferred from.
+		fb := document.NewFieldBuffer()
+		err := fb.Copy(d)
+		if err != nil {
+			return err
+		}
+
+		k, err := tree.NewKey(types.NewIntegerValue(counter))
+		if err != nil {
+			return err
+		}
+		counter++
+
+		err = tr.Put(k, types.NewDocumentValue(fb))
+		if err != nil {
+			return err
+		}
+
+		return fb.Iterate(func(field string, v types.Value) error {
+			if v.Type() == types.NullValue {
+				return nil
+			}
+
+			tp, ok := fieldTypes[field]
+			if !ok {
+				fields = append(fields, field)
+				fieldTypes[field] = v.Type()
+			} else if tp != v.Type() {
+				fieldTypes[field] = types.AnyType
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		if fieldTypes[field] == types.AnyType {
+			continue
+		}
+
+		fc := database.FieldConstraint{
+			Path: document.NewPath(field),
+			Type: fieldTypes[field],
+		}
+		err = ctx.Catalog.AddFieldConstraint(ctx.Tx, stmt.Info.TableName, &fc, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bufferedSelect emits the documents selected by CREATE TABLE ... AS SELECT,
+// once they are stored in a temporary tree.
+type bufferedSelect struct {
+	tree *tree.Tree
+}
+
+// Prepare implements the Preparer interface.
+func (b *bufferedSelect) Prepare(*Context) (Statement, error) {
+	return &PreparedStreamStmt{
+		Stream:   stream.New(stream.DocsEmitTree(b.tree)),
+		ReadOnly: true,
+	}, nil
+}
+
 // CreateIndexStmt represents a parsed CREATE INDEX statement.
 type CreateIndexStmt struct {
 	IfNotExists bool
@@ -97,7 +237,8 @@ func (stmt *CreateIndexStmt) Run(ctx *Context) (Result, error) {
 		return res, err
 	}
 
-	s := stream.New(stream.TableScan(stmt.Info.TableName)).Pipe(stream.IndexInsert(stmt.Info.IndexName))
+	s := stream.New(&stream.TableScanOperator{TableName: stmt.Info.TableName, IncludeExpired: true}).
+		Pipe(stream.IndexInsert(stmt.Info.IndexName))
 
 	ss := PreparedStreamStmt{
 		Stream:   s,
@@ -131,3 +272,91 @@ func (stmt *CreateSequenceStmt) Run(ctx *Context) (Result, error) {
 	}
 	return res, err
 }
+
+// CreateViewStmt represents a parsed CREATE VIEW or CREATE MATERIALIZED VIEW statement.
+type CreateViewStmt struct {
+	IfNotExists bool
+	Info        database.ViewInfo
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt *CreateViewStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run the statement in the given transaction.
+// If the view is materialized, the result of its query is stored.
+// It implements the Statement interface.
+func (stmt *CreateViewStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	sel := stmt.Info.Query.(*SelectStmt)
+	if selectReadsFrom(ctx.Catalog, sel, stmt.Info.ViewName) {
+		return res, fmt.Errorf("view %s cannot read from itself", stmt.Info.ViewName)
+	}
+
+	// planning the query ensures it is valid before it is stored
+	_, err := sel.Prepare(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	err = ctx.Catalog.CreateView(ctx.Tx, &stmt.Info)
+	if stmt.IfNotExists {
+		if errs.IsAlreadyExistsError(err) {
+			return res, nil
+		}
+	}
+	if err != nil {
+		return res, err
+	}
+
+	if stmt.Info.Materialized {
+		err = refreshView(ctx, &stmt.Info)
+	}
+
+	return res, err
+}
+
+// selectReadsFrom returns whether the statement reads from the given relation,
+// either directly or through other views.
+func selectReadsFrom(catalog *database.Catalog, stmt *SelectStmt, name string) bool {
+	for _, tableName := range stmt.ReadsFrom() {
+		if tableName == name {
+			return true
+		}
+
+		v, err := catalog.GetViewInfo(tableName)
+		if err == nil && selectReadsFrom(catalog, v.Query.(*SelectStmt), name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateTriggerStmt represents a parsed CREATE TRIGGER statement.
+type CreateTriggerStmt struct {
+	IfNotExists bool
+	Info        database.TriggerInfo
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt *CreateTriggerStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run the statement in the given transaction.
+// It implements the Statement interface.
+func (stmt *CreateTriggerStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	err := ctx.Catalog.CreateTrigger(ctx.Tx, &stmt.Info)
+	if stmt.IfNotExists {
+		if errs.IsAlreadyExistsError(err) {
+			return res, nil
+		}
+	}
+
+	return res, err
+}
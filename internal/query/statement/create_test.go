@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/genjidb/genji"
 	"github.com/genjidb/genji/document"
 	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/expr"
@@ -430,3 +431,134 @@ func TestCreateSequence(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateView(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := genji.Open(dir)
+	assert.NoError(t, err)
+
+	err = db.Exec(`
+		CREATE TABLE test(a INT PRIMARY KEY, b TEXT);
+		INSERT INTO test (a, b) VALUES (1, 'foo'), (2, 'bar');
+		CREATE VIEW v AS SELECT b AS c FROM test WHERE a > 1;
+		CREATE MATERIALIZED VIEW mv AS SELECT a FROM test ORDER BY a DESC;
+	`)
+	assert.NoError(t, err)
+
+	err = db.Close()
+	assert.NoError(t, err)
+
+	// views must be loaded from the catalog when reopening the database.
+	db, err = genji.Open(dir)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	d, err := db.QueryDocument("SELECT * FROM v")
+	assert.NoError(t, err)
+	data, err := document.MarshalJSON(d)
+	assert.NoError(t, err)
+	require.JSONEq(t, `{"c": "bar"}`, string(data))
+
+	d, err = db.QueryDocument("SELECT * FROM mv LIMIT 1")
+	assert.NoError(t, err)
+	data, err = document.MarshalJSON(d)
+	assert.NoError(t, err)
+	require.JSONEq(t, `{"a": 2}`, string(data))
+
+	err = db.Exec("INSERT INTO test (a, b) VALUES (3, 'baz'); REFRESH MATERIALIZED VIEW mv")
+	assert.NoError(t, err)
+
+	d, err = db.QueryDocument("SELECT COUNT(*) AS n FROM mv")
+	assert.NoError(t, err)
+	data, err = document.MarshalJSON(d)
+	assert.NoError(t, err)
+	require.JSONEq(t, `{"n": 3}`, string(data))
+}
+
+func TestCreateTrigger(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := genji.Open(dir)
+	assert.NoError(t, err)
+
+	err = db.Exec(`
+		CREATE TABLE test(a INT PRIMARY KEY, b INT);
+		CREATE TABLE total(id INT PRIMARY KEY, n INT);
+		INSERT INTO total (id, n) VALUES (1, 0);
+		CREATE TRIGGER test_insert AFTER INSERT ON test BEGIN
+			UPDATE total SET n = n + NEW.b WHERE id = 1;
+		END;
+		CREATE TRIGGER test_update AFTER UPDATE ON test WHEN NEW.b != OLD.b BEGIN
+			UPDATE total SET n = n - OLD.b + NEW.b WHERE id = 1;
+		END;
+		CREATE TRIGGER test_delete BEFORE DELETE ON test BEGIN
+			UPDATE total SET n = n - OLD.b WHERE id = 1;
+		END;
+	`)
+	assert.NoError(t, err)
+
+	err = db.Close()
+	assert.NoError(t, err)
+
+	// triggers must be loaded from the catalog when reopening the database.
+	db, err = genji.Open(dir)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec(`
+		INSERT INTO test (a, b) VALUES (1, 10), (2, 20), (3, 30);
+		UPDATE test SET b = 25 WHERE a = 2;
+		DELETE FROM test WHERE a = 1;
+	`)
+	assert.NoError(t, err)
+
+	d, err := db.QueryDocument("SELECT n FROM total")
+	assert.NoError(t, err)
+	data, err := document.MarshalJSON(d)
+	assert.NoError(t, err)
+	require.JSONEq(t, `{"n": 55}`, string(data))
+
+	// a failing trigger must cancel the statement that fired it.
+	err = db.Exec("DROP TABLE total")
+	assert.NoError(t, err)
+
+	err = db.Exec("INSERT INTO test (a, b) VALUES (4, 40)")
+	assert.Error(t, err)
+
+	d, err = db.QueryDocument("SELECT COUNT(*) AS n FROM test")
+	assert.NoError(t, err)
+	data, err = document.MarshalJSON(d)
+	assert.NoError(t, err)
+	require.JSONEq(t, `{"n": 2}`, string(data))
+}
+
+func TestAfterTriggers(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE test(a INT PRIMARY KEY, b INT UNIQUE);
+		CREATE TABLE log(a INT PRIMARY KEY);
+		CREATE TRIGGER test_update AFTER UPDATE ON test BEGIN
+			INSERT INTO log (a) VALUES (NEW.a);
+		END;
+		INSERT INTO test (a, b) VALUES (1, 1), (2, 2);
+	`)
+	assert.NoError(t, err)
+
+	tx, err := db.Begin(true)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	// AFTER triggers must not fire for documents rejected by a unique index
+	err = tx.Exec("UPDATE test SET b = 2 WHERE a = 1")
+	assert.Error(t, err)
+
+	d, err := tx.QueryDocument("SELECT COUNT(*) AS n FROM log")
+	assert.NoError(t, err)
+	data, err := document.MarshalJSON(d)
+	assert.NoError(t, err)
+	require.JSONEq(t, `{"n": 0}`, string(data))
+}
@@ -1,9 +1,14 @@
 package statement
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/expr"
 	"github.com/genjidb/genji/internal/sql/scanner"
 	"github.com/genjidb/genji/internal/stream"
+	"github.com/genjidb/genji/internal/stringutil"
 )
 
 // DeleteConfig holds DELETE configuration.
@@ -30,6 +35,10 @@ func NewDeleteStatement() *DeleteStmt {
 }
 
 func (stmt *DeleteStmt) Prepare(c *Context) (Statement, error) {
+	if err := checkWritable(c, stmt.TableName); err != nil {
+		return nil, err
+	}
+
 	s := stream.New(stream.TableScan(stmt.TableName))
 
 	if stmt.WhereExpr != nil {
@@ -59,6 +68,10 @@ func (stmt *DeleteStmt) Prepare(c *Context) (Statement, error) {
 
 	s = s.Pipe(stream.TableDelete(stmt.TableName))
 
+	if hasAfterTriggers(c, stmt.TableName, database.TriggerDelete) {
+		s = s.Pipe(stream.TableAfterTriggers(stmt.TableName, database.TriggerDelete))
+	}
+
 	st := StreamStmt{
 		Stream:   s,
 		ReadOnly: false,
@@ -66,3 +79,31 @@ func (stmt *DeleteStmt) Prepare(c *Context) (Statement, error) {
 
 	return st.Prepare(c)
 }
+
+// String returns a SQL representation.
+func (stmt *DeleteStmt) String() string {
+	var s strings.Builder
+
+	fmt.Fprintf(&s, "DELETE FROM %s", stringutil.NormalizeIdentifier(stmt.TableName, '`'))
+
+	if stmt.WhereExpr != nil {
+		fmt.Fprintf(&s, " WHERE %s", stmt.WhereExpr)
+	}
+
+	if stmt.OrderBy != nil {
+		fmt.Fprintf(&s, " ORDER BY %s", stmt.OrderBy)
+		if stmt.OrderByDirection == scanner.DESC {
+			s.WriteString(" DESC")
+		}
+	}
+
+	if stmt.LimitExpr != nil {
+		fmt.Fprintf(&s, " LIMIT %s", stmt.LimitExpr)
+	}
+
+	if stmt.OffsetExpr != nil {
+		fmt.Fprintf(&s, " OFFSET %s", stmt.OffsetExpr)
+	}
+
+	return s.String()
+}
@@ -119,3 +119,59 @@ func (stmt DropSequenceStmt) Run(ctx *Context) (Result, error) {
 
 	return res, err
 }
+
+// DropViewStmt is a DSL that allows creating a DROP VIEW query.
+type DropViewStmt struct {
+	ViewName string
+	IfExists bool
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt DropViewStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the DropView statement in the given transaction.
+// It implements the Statement interface.
+func (stmt DropViewStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	if stmt.ViewName == "" {
+		return res, errors.New("missing view name")
+	}
+
+	err := ctx.Catalog.DropView(ctx.Tx, stmt.ViewName)
+	if errs.IsNotFoundError(err) && stmt.IfExists {
+		err = nil
+	}
+
+	return res, err
+}
+
+// DropTriggerStmt is a DSL that allows creating a DROP TRIGGER query.
+type DropTriggerStmt struct {
+	TriggerName string
+	IfExists    bool
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt DropTriggerStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the DropTrigger statement in the given transaction.
+// It implements the Statement interface.
+func (stmt DropTriggerStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	if stmt.TriggerName == "" {
+		return res, errors.New("missing trigger name")
+	}
+
+	err := ctx.Catalog.DropTrigger(ctx.Tx, stmt.TriggerName)
+	if errs.IsNotFoundError(err) && stmt.IfExists {
+		err = nil
+	}
+
+	return res, err
+}
@@ -1,8 +1,12 @@
 package statement
 
 import (
+	"time"
+
 	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/document"
 	"github.com/genjidb/genji/internal/expr"
+	"github.com/genjidb/genji/internal/kv"
 	"github.com/genjidb/genji/internal/stream"
 	"github.com/genjidb/genji/types"
 )
@@ -10,8 +14,11 @@ import (
 // ExplainStmt is a Statement that
 // displays information about how a statement
 // is going to be executed, without executing it.
+// If Analyze is true, the statement is executed
+// and the metrics of each operator are displayed as well.
 type ExplainStmt struct {
 	Statement Preparer
+	Analyze   bool
 }
 
 // Run analyses the inner statement and displays its execution plan.
@@ -36,21 +43,106 @@ func (stmt *ExplainStmt) Run(ctx *Context) (Result, error) {
 		plan = "<no exec>"
 	}
 
+	exprs := []expr.Expr{
+		&expr.NamedExpr{
+			ExprName: "plan",
+			Expr:     expr.LiteralValue{Value: types.NewTextValue(plan)},
+		},
+	}
+
+	if stmt.Analyze {
+		analysis, err := stmt.analyze(ctx, s)
+		if err != nil {
+			return Result{}, err
+		}
+
+		exprs = append(exprs, analysis...)
+	}
+
 	newStatement := PreparedStreamStmt{
 		Stream: &stream.Stream{
-			Op: stream.DocsProject(
-				&expr.NamedExpr{
-					ExprName: "plan",
-					Expr:     expr.LiteralValue{Value: types.NewTextValue(plan)},
-				}),
+			Op: stream.DocsProject(exprs...),
 		},
 		ReadOnly: true,
 	}
 	return newStatement.Run(ctx)
 }
 
+// analyze runs the statement, discards its results and returns
+// the metrics collected for each operator.
+func (stmt *ExplainStmt) analyze(ctx *Context, s *PreparedStreamStmt) ([]expr.Expr, error) {
+	var m kv.Metrics
+
+	prev := ctx.Tx.Session.Metrics
+	ctx.Tx.Session.Metrics = &m
+	defer func() {
+		ctx.Tx.Session.Metrics = prev
+	}()
+
+	p := stream.Profile(s.Stream, &m)
+
+	start := time.Now()
+	res, err := s.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = res.Iterate(func(d types.Document) error {
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	elapsed := time.Since(start)
+
+	return []expr.Expr{
+		&expr.NamedExpr{
+			ExprName: "time",
+			Expr:     expr.LiteralValue{Value: types.NewTextValue(elapsed.String())},
+		},
+		&expr.NamedExpr{
+			ExprName: "operators",
+			Expr:     expr.LiteralValue{Value: types.NewArrayValue(operatorStatsToArray(p.Stats))},
+		},
+	}, nil
+}
+
+func operatorStatsToArray(stats []*stream.OperatorStats) types.Array {
+	vb := document.NewValueBuffer()
+
+	for _, st := range stats {
+		fb := document.NewFieldBuffer().
+			Add("operator", types.NewTextValue(st.Operator)).
+			Add("rows_in", types.NewIntegerValue(st.RowsIn)).
+			Add("rows_out", types.NewIntegerValue(st.RowsOut)).
+			Add("time", types.NewTextValue(st.Duration.String())).
+			Add("reads", types.NewIntegerValue(st.Reads)).
+			Add("transient_bytes_written", types.NewIntegerValue(st.TransientBytesWritten))
+
+		if len(st.Streams) > 0 {
+			streams := document.NewValueBuffer()
+			for _, s := range st.Streams {
+				streams.Append(types.NewArrayValue(operatorStatsToArray(s)))
+			}
+			fb.Add("streams", types.NewArrayValue(streams))
+		}
+
+		vb.Append(types.NewDocumentValue(fb))
+	}
+
+	return vb
+}
+
 // IsReadOnly indicates that this statement doesn't write anything into
-// the database.
+// the database, unless it is analyzing a statement that does.
 func (s *ExplainStmt) IsReadOnly() bool {
-	return true
+	if !s.Analyze {
+		return true
+	}
+
+	if st, ok := s.Statement.(Statement); ok {
+		return st.IsReadOnly()
+	}
+
+	return false
 }
@@ -24,17 +24,17 @@ func TestExplainStmt(t *testing.T) {
 		{"EXPLAIN SELECT a + 1 FROM test WHERE c > 10 AND d > 20", false, `"table.Scan(\"test\") | docs.Filter(c > 10) | docs.Filter(d > 20) | docs.Project(a + 1)"`},
 		{"EXPLAIN SELECT a + 1 FROM test WHERE c > 10 OR d > 20", false, `"table.Scan(\"test\") | docs.Filter(c > 10 OR d > 20) | docs.Project(a + 1)"`},
 		{"EXPLAIN SELECT a + 1 FROM test WHERE c IN [1 + 1, 2 + 2]", false, `"table.Scan(\"test\") | docs.Filter(c IN [2, 4]) | docs.Project(a + 1)"`},
-		{"EXPLAIN SELECT a + 1 FROM test WHERE a > 10", false, `"index.Scan(\"idx_a\", [{\"min\": [10], \"exclusive\": true}]) | docs.Project(a + 1)"`},
+		{"EXPLAIN SELECT a + 1 FROM test WHERE a > 10", false, `"index.CoveringScan(\"idx_a\", [{\"min\": [10], \"exclusive\": true}]) | docs.Project(a + 1)"`},
 		{"EXPLAIN SELECT a + 1 FROM test WHERE x = 10 AND y > 5", false, `"index.Scan(\"idx_x_y\", [{\"min\": [10, 5], \"exclusive\": true}]) | docs.Project(a + 1)"`},
 		{"EXPLAIN SELECT a + 1 FROM test WHERE a > 10 AND b > 20 AND c > 30", false, `"index.Scan(\"idx_b\", [{\"min\": [20], \"exclusive\": true}]) | docs.Filter(a > 10) | docs.Filter(c > 30) | docs.Project(a + 1)"`},
 		{"EXPLAIN SELECT a + 1 FROM test WHERE c > 30 ORDER BY d LIMIT 10 OFFSET 20", false, `"table.Scan(\"test\") | docs.Filter(c > 30) | docs.Project(a + 1) | docs.TempTreeSort(d) | docs.Skip(20) | docs.Take(10)"`},
 		{"EXPLAIN SELECT a + 1 FROM test WHERE c > 30 ORDER BY d DESC LIMIT 10 OFFSET 20", false, `"table.Scan(\"test\") | docs.Filter(c > 30) | docs.Project(a + 1) | docs.TempTreeSortReverse(d) | docs.Skip(20) | docs.Take(10)"`},
 		{"EXPLAIN SELECT a + 1 FROM test WHERE c > 30 ORDER BY a DESC LIMIT 10 OFFSET 20", false, `"index.ScanReverse(\"idx_a\") | docs.Filter(c > 30) | docs.Project(a + 1) | docs.Skip(20) | docs.Take(10)"`},
 		{"EXPLAIN SELECT a FROM test WHERE c > 30 GROUP BY a ORDER BY a DESC LIMIT 10 OFFSET 20", false, `"index.ScanReverse(\"idx_a\") | docs.Filter(c > 30) | docs.GroupAggregate(a) | docs.Project(a) | docs.Skip(20) | docs.Take(10)"`},
-		{"EXPLAIN SELECT a + 1 FROM test WHERE c > 30 GROUP BY a + 1 ORDER BY a DESC LIMIT 10 OFFSET 20", false, `"table.Scan(\"test\") | docs.Filter(c > 30) | docs.TempTreeSort(a + 1) | docs.GroupAggregate(a + 1) | docs.Project(a + 1) | docs.TempTreeSortReverse(a) | docs.Skip(20) | docs.Take(10)"`},
-		{"EXPLAIN UPDATE test SET a = 10", false, `"table.Scan(\"test\") | paths.Set(a, 10) | table.Validate(\"test\") | index.Delete(\"idx_a\") | index.Delete(\"idx_b\") | index.Delete(\"idx_x_y\") | table.Replace(\"test\") | index.Insert(\"idx_a\") | index.Insert(\"idx_b\") | index.Insert(\"idx_x_y\")"`},
-		{"EXPLAIN UPDATE test SET a = 10 WHERE c > 10", false, `"table.Scan(\"test\") | docs.Filter(c > 10) | paths.Set(a, 10) | table.Validate(\"test\") | index.Delete(\"idx_a\") | index.Delete(\"idx_b\") | index.Delete(\"idx_x_y\") | table.Replace(\"test\") | index.Insert(\"idx_a\") | index.Insert(\"idx_b\") | index.Insert(\"idx_x_y\")"`},
-		{"EXPLAIN UPDATE test SET a = 10 WHERE a > 10", false, `"index.Scan(\"idx_a\", [{\"min\": [10], \"exclusive\": true}]) | paths.Set(a, 10) | table.Validate(\"test\") | index.Delete(\"idx_a\") | index.Delete(\"idx_b\") | index.Delete(\"idx_x_y\") | table.Replace(\"test\") | index.Insert(\"idx_a\") | index.Insert(\"idx_b\") | index.Insert(\"idx_x_y\")"`},
+		{"EXPLAIN SELECT a + 1 FROM test WHERE c > 30 GROUP BY a + 1 ORDER BY a DESC LIMIT 10 OFFSET 20", false, `"table.Scan(\"test\") | docs.Filter(c > 30) | docs.HashAggregate(a + 1) | docs.Project(a + 1) | docs.TempTreeSortReverse(a) | docs.Skip(20) | docs.Take(10)"`},
+		{"EXPLAIN UPDATE test SET a = 10", false, `"table.Scan(\"test\") | paths.Set(a, 10) | table.Validate(\"test\") | index.Delete(\"idx_a\") | index.Delete(\"idx_b\") | index.Delete(\"idx_x_y\") | index.Validate(\"idx_b\") | table.Replace(\"test\") | index.Insert(\"idx_a\") | index.Insert(\"idx_b\") | index.Insert(\"idx_x_y\")"`},
+		{"EXPLAIN UPDATE test SET a = 10 WHERE c > 10", false, `"table.Scan(\"test\") | docs.Filter(c > 10) | paths.Set(a, 10) | table.Validate(\"test\") | index.Delete(\"idx_a\") | index.Delete(\"idx_b\") | index.Delete(\"idx_x_y\") | index.Validate(\"idx_b\") | table.Replace(\"test\") | index.Insert(\"idx_a\") | index.Insert(\"idx_b\") | index.Insert(\"idx_x_y\")"`},
+		{"EXPLAIN UPDATE test SET a = 10 WHERE a > 10", false, `"index.Scan(\"idx_a\", [{\"min\": [10], \"exclusive\": true}]) | paths.Set(a, 10) | table.Validate(\"test\") | index.Delete(\"idx_a\") | index.Delete(\"idx_b\") | index.Delete(\"idx_x_y\") | index.Validate(\"idx_b\") | table.Replace(\"test\") | index.Insert(\"idx_a\") | index.Insert(\"idx_b\") | index.Insert(\"idx_x_y\")"`},
 		{"EXPLAIN DELETE FROM test", false, `"table.Scan(\"test\") | index.Delete(\"idx_a\") | index.Delete(\"idx_b\") | index.Delete(\"idx_x_y\") | table.Delete('test')"`},
 		{"EXPLAIN DELETE FROM test WHERE c > 10", false, `"table.Scan(\"test\") | docs.Filter(c > 10) | index.Delete(\"idx_a\") | index.Delete(\"idx_b\") | index.Delete(\"idx_x_y\") | table.Delete('test')"`},
 		{"EXPLAIN DELETE FROM test WHERE a > 10", false, `"index.Scan(\"idx_a\", [{\"min\": [10], \"exclusive\": true}]) | index.Delete(\"idx_a\") | index.Delete(\"idx_b\") | index.Delete(\"idx_x_y\") | table.Delete('test')"`},
@@ -72,3 +72,81 @@ func TestExplainStmt(t *testing.T) {
 		})
 	}
 }
+
+func TestExplainAnalyzeStmt(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"EXPLAIN ANALYZE SELECT a FROM test WHERE b > 3", `[
+			{"operator": "table.Scan(\"test\")", "rows_in": 0, "rows_out": 5, "reads": 5, "transient_bytes_written": 0},
+			{"operator": "docs.Filter(b > 3)", "rows_in": 5, "rows_out": 2, "reads": 0, "transient_bytes_written": 0},
+			{"operator": "docs.Project(a)", "rows_in": 2, "rows_out": 2, "reads": 0, "transient_bytes_written": 0}
+		]`},
+		{"EXPLAIN ANALYZE SELECT * FROM test WHERE a = 2", `[
+			{"operator": "index.Scan(\"idx_a\", [{\"min\": [2], \"exact\": true}])", "rows_in": 0, "rows_out": 1, "reads": 1, "transient_bytes_written": 0}
+		]`},
+		{"EXPLAIN ANALYZE SELECT * FROM test ORDER BY b LIMIT 1", `[
+			{"operator": "table.Scan(\"test\")", "rows_in": 0, "rows_out": 5, "reads": 5, "transient_bytes_written": 0},
+			{"operator": "docs.TempTreeSort(b)", "rows_in": 5, "rows_out": 2, "reads": 0, "transient_bytes_written": 420},
+			{"operator": "docs.Take(1)", "rows_in": 2, "rows_out": 1, "reads": 0, "transient_bytes_written": 0}
+		]`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			db, err := genji.Open(":memory:")
+			assert.NoError(t, err)
+			defer db.Close()
+
+			err = db.Exec(`
+				CREATE TABLE test (k INTEGER PRIMARY KEY, a INTEGER, b INTEGER);
+				CREATE INDEX idx_a ON test (a);
+				INSERT INTO test (k, a, b) VALUES (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4), (5, 5, 5);
+			`)
+			assert.NoError(t, err)
+
+			d, err := db.QueryDocument(test.query)
+			assert.NoError(t, err)
+
+			v, err := d.GetByField("operators")
+			assert.NoError(t, err)
+
+			data, err := json.Marshal(v)
+			assert.NoError(t, err)
+
+			// durations are not deterministic
+			var operators []map[string]interface{}
+			err = json.Unmarshal(data, &operators)
+			assert.NoError(t, err)
+			for _, op := range operators {
+				require.Contains(t, op, "time")
+				delete(op, "time")
+			}
+
+			data, err = json.Marshal(operators)
+			assert.NoError(t, err)
+			require.JSONEq(t, test.expected, string(data))
+		})
+	}
+
+	t.Run("Writes", func(t *testing.T) {
+		db, err := genji.Open(":memory:")
+		assert.NoError(t, err)
+		defer db.Close()
+
+		err = db.Exec(`
+			CREATE TABLE test (a INTEGER);
+			INSERT INTO test (a) VALUES (1), (2);
+			EXPLAIN ANALYZE UPDATE test SET a = a + 10;
+		`)
+		assert.NoError(t, err)
+
+		d, err := db.QueryDocument("SELECT SUM(a) AS s FROM test")
+		assert.NoError(t, err)
+
+		v, err := d.GetByField("s")
+		assert.NoError(t, err)
+		require.EqualValues(t, 23, v.V())
+	})
+}
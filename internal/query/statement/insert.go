@@ -1,10 +1,14 @@
 package statement
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/cockroachdb/errors"
 	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/expr"
 	"github.com/genjidb/genji/internal/stream"
+	"github.com/genjidb/genji/internal/stringutil"
 )
 
 // InsertStmt holds INSERT configuration.
@@ -31,6 +35,10 @@ func NewInsertStatement() *InsertStmt {
 }
 
 func (stmt *InsertStmt) Prepare(c *Context) (Statement, error) {
+	if err := checkWritable(c, stmt.TableName); err != nil {
+		return nil, err
+	}
+
 	var s *stream.Stream
 
 	if stmt.Values != nil {
@@ -62,7 +70,11 @@ func (stmt *InsertStmt) Prepare(c *Context) (Statement, error) {
 		case database.OnConflictDoNothing:
 			s = s.Pipe(stream.OnConflict(nil))
 		case database.OnConflictDoReplace:
-			s = s.Pipe(stream.OnConflict(stream.New(stream.TableReplace(stmt.TableName))))
+			onConflict := stream.New(stream.TableReplace(stmt.TableName))
+			if hasAfterTriggers(c, stmt.TableName, database.TriggerUpdate) {
+				onConflict = onConflict.Pipe(stream.TableAfterTriggers(stmt.TableName, database.TriggerUpdate))
+			}
+			s = s.Pipe(stream.OnConflict(onConflict))
 		default:
 			panic("unreachable")
 		}
@@ -87,6 +99,10 @@ func (stmt *InsertStmt) Prepare(c *Context) (Statement, error) {
 		s = s.Pipe(stream.IndexInsert(indexName))
 	}
 
+	if hasAfterTriggers(c, stmt.TableName, database.TriggerInsert) {
+		s = s.Pipe(stream.TableAfterTriggers(stmt.TableName, database.TriggerInsert))
+	}
+
 	if len(stmt.Returning) > 0 {
 		s = s.Pipe(stream.DocsProject(stmt.Returning...))
 	}
@@ -98,3 +114,69 @@ func (stmt *InsertStmt) Prepare(c *Context) (Statement, error) {
 
 	return st.Prepare(c)
 }
+
+// String returns a SQL representation.
+func (stmt *InsertStmt) String() string {
+	var s strings.Builder
+
+	fmt.Fprintf(&s, "INSERT INTO %s", stringutil.NormalizeIdentifier(stmt.TableName, '`'))
+
+	if len(stmt.Fields) > 0 {
+		s.WriteString(" (")
+		for i, f := range stmt.Fields {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+			s.WriteString(stringutil.NormalizeIdentifier(f, '`'))
+		}
+		s.WriteString(")")
+	}
+
+	if stmt.Values != nil {
+		s.WriteString(" VALUES ")
+		for i, v := range stmt.Values {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+
+			// when fields are specified, values are stored as documents
+			// but were written as lists of expressions.
+			kvp, ok := v.(*expr.KVPairs)
+			if !ok || len(stmt.Fields) == 0 {
+				s.WriteString(v.String())
+				continue
+			}
+
+			s.WriteString("(")
+			for j, pair := range kvp.Pairs {
+				if j > 0 {
+					s.WriteString(", ")
+				}
+				s.WriteString(pair.V.String())
+			}
+			s.WriteString(")")
+		}
+	} else {
+		fmt.Fprintf(&s, " %s", stmt.SelectStmt)
+	}
+
+	if stmt.OnConflict != 0 {
+		fmt.Fprintf(&s, " ON CONFLICT %s", stmt.OnConflict)
+	}
+
+	if len(stmt.Returning) > 0 {
+		s.WriteString(" RETURNING ")
+		for i, e := range stmt.Returning {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+
+			s.WriteString(e.String())
+			if ne, ok := e.(*expr.NamedExpr); ok && ne.ExprName != ne.Expr.String() {
+				fmt.Fprintf(&s, " AS %s", stringutil.NormalizeIdentifier(ne.ExprName, '`'))
+			}
+		}
+	}
+
+	return s.String()
+}
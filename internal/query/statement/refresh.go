@@ -0,0 +1,72 @@
+package statement
+
+import (
+	"fmt"
+
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/tree"
+	"github.com/genjidb/genji/types"
+)
+
+// RefreshViewStmt is a DSL that allows creating a full REFRESH MATERIALIZED VIEW statement.
+type RefreshViewStmt struct {
+	ViewName string
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt RefreshViewStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the REFRESH MATERIALIZED VIEW statement in the given transaction.
+// It implements the Statement interface.
+func (stmt RefreshViewStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	info, err := ctx.Catalog.GetViewInfo(stmt.ViewName)
+	if err != nil {
+		return res, err
+	}
+
+	if !info.Materialized {
+		return res, fmt.Errorf("%s is not a materialized view", stmt.ViewName)
+	}
+
+	return res, refreshView(ctx, info)
+}
+
+// refreshView replaces the stored results of a materialized view
+// with the result of its query.
+func refreshView(ctx *Context, info *database.ViewInfo) error {
+	tb, err := ctx.Catalog.GetTable(ctx.Tx, info.ViewName)
+	if err != nil {
+		return err
+	}
+
+	err = tb.Truncate()
+	if err != nil {
+		return err
+	}
+
+	st, err := info.Query.(*SelectStmt).Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := st.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	var i int64
+	return res.Iterate(func(d types.Document) error {
+		i++
+
+		key, err := tree.NewKey(types.NewIntegerValue(i))
+		if err != nil {
+			return err
+		}
+
+		return tb.Tree.Put(key, types.NewDocumentValue(d))
+	})
+}
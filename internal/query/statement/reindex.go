@@ -55,7 +55,8 @@ func (stmt ReIndexStmt) Prepare(ctx *Context) (Statement, error) {
 			return nil, err
 		}
 
-		s := stream.New(stream.TableScan(info.TableName)).Pipe(stream.IndexInsert(info.IndexName))
+		s := stream.New(&stream.TableScanOperator{TableName: info.TableName, IncludeExpired: true}).
+			Pipe(stream.IndexInsert(info.IndexName))
 		streams = append(streams, s)
 	}
 
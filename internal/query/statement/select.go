@@ -2,12 +2,17 @@ package statement
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/genjidb/genji/document"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/expr"
+	"github.com/genjidb/genji/internal/expr/functions"
 	"github.com/genjidb/genji/internal/sql/scanner"
 	"github.com/genjidb/genji/internal/stream"
+	"github.com/genjidb/genji/internal/stringutil"
 )
 
 type SelectCoreStmt struct {
@@ -18,25 +23,48 @@ type SelectCoreStmt struct {
 	ProjectionExprs []expr.Expr
 }
 
-func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
+func (stmt *SelectCoreStmt) Prepare(ctx *Context) (*StreamStmt, error) {
 	isReadOnly := true
 
 	var s *stream.Stream
 
 	if stmt.TableName != "" {
-		s = s.Pipe(stream.TableScan(stmt.TableName))
+		// views are replaced by the stream of their query,
+		// materialized views are read like tables.
+		v, err := ctx.Catalog.GetViewInfo(stmt.TableName)
+		switch {
+		case database.IsSystemTable(stmt.TableName):
+			s = s.Pipe(stream.SystemTableScan(stmt.TableName))
+		case err == nil && !v.Materialized:
+			vs, err := v.Query.(*SelectStmt).toStream(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			s = stream.New(stream.Concat(vs.Stream))
+			isReadOnly = vs.ReadOnly
+		case err == nil || errs.IsNotFoundError(err):
+			s = s.Pipe(stream.TableScan(stmt.TableName))
+		default:
+			return nil, err
+		}
 	}
 
 	if stmt.WhereExpr != nil {
 		s = s.Pipe(stream.DocsFilter(stmt.WhereExpr))
 	}
 
+	// the projected expressions may be replaced below, copy them
+	// to keep the statement reusable.
+	projectionExprs := make([]expr.Expr, len(stmt.ProjectionExprs))
+	copy(projectionExprs, stmt.ProjectionExprs)
+
 	// when using GROUP BY, only aggregation functions or GroupByExpr can be selected
 	if stmt.GroupByExpr != nil {
 		var invalidProjectedField expr.Expr
 		var aggregators []expr.AggregatorBuilder
 
-		for i, pe := range stmt.ProjectionExprs {
+		for i, pe := range projectionExprs {
 			ne, ok := pe.(*expr.NamedExpr)
 			if !ok {
 				invalidProjectedField = pe
@@ -53,7 +81,7 @@ func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
 			// check if this is the same expression as the one used in the GROUP BY clause
 			if expr.Equal(e, stmt.GroupByExpr) {
 				// if so, replace the expression with a path expression
-				stmt.ProjectionExprs[i] = &expr.NamedExpr{
+				projectionExprs[i] = &expr.NamedExpr{
 					ExprName: ne.ExprName,
 					Expr:     expr.Path(document.NewPath(e.String())),
 				}
@@ -76,7 +104,7 @@ func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
 		// and if so add an aggregation node
 		var aggregators []expr.AggregatorBuilder
 
-		for _, pe := range stmt.ProjectionExprs {
+		for _, pe := range projectionExprs {
 			ne, ok := pe.(*expr.NamedExpr)
 			if !ok {
 				continue
@@ -99,7 +127,7 @@ func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
 	if stmt.TableName == "" {
 		var err error
 
-		for _, e := range stmt.ProjectionExprs {
+		for _, e := range projectionExprs {
 			expr.Walk(e, func(e expr.Expr) bool {
 				switch e.(type) {
 				case expr.Path, expr.Wildcard:
@@ -114,20 +142,12 @@ func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
 			}
 		}
 	}
-	s = s.Pipe(stream.DocsProject(stmt.ProjectionExprs...))
+	s = s.Pipe(stream.DocsProject(projectionExprs...))
 
 	// SELECT is read-only most of the time, unless it's using some expressions
-	// that require write access and that are allowed to be run, such as NEXT VALUE FOR
-	for _, e := range stmt.ProjectionExprs {
-		expr.Walk(e, func(e expr.Expr) bool {
-			switch e.(type) {
-			case expr.NextValueFor:
-				isReadOnly = false
-				return false
-			default:
-				return true
-			}
-		})
+	// that require write access and that are allowed to be run, such as NEXT VALUE FOR or setval()
+	if requiresWriteAccess(projectionExprs) {
+		isReadOnly = false
 	}
 
 	if stmt.Distinct {
@@ -140,6 +160,41 @@ func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
 	}, nil
 }
 
+// String returns a SQL representation.
+func (stmt *SelectCoreStmt) String() string {
+	var s strings.Builder
+
+	s.WriteString("SELECT ")
+	if stmt.Distinct {
+		s.WriteString("DISTINCT ")
+	}
+
+	for i, e := range stmt.ProjectionExprs {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+
+		s.WriteString(e.String())
+		if ne, ok := e.(*expr.NamedExpr); ok && ne.ExprName != ne.Expr.String() {
+			fmt.Fprintf(&s, " AS %s", stringutil.NormalizeIdentifier(ne.ExprName, '`'))
+		}
+	}
+
+	if stmt.TableName != "" {
+		fmt.Fprintf(&s, " FROM %s", stringutil.NormalizeIdentifier(stmt.TableName, '`'))
+	}
+
+	if stmt.WhereExpr != nil {
+		fmt.Fprintf(&s, " WHERE %s", stmt.WhereExpr)
+	}
+
+	if stmt.GroupByExpr != nil {
+		fmt.Fprintf(&s, " GROUP BY %s", stmt.GroupByExpr)
+	}
+
+	return s.String()
+}
+
 // SelectStmt holds SELECT configuration.
 type SelectStmt struct {
 	basePreparedStatement
@@ -152,6 +207,18 @@ type SelectStmt struct {
 	LimitExpr         expr.Expr
 }
 
+// IsReadOnly reports whether the statement can be run in a read-only transaction.
+// It is used when the statement is run without being prepared first.
+func (stmt *SelectStmt) IsReadOnly() bool {
+	for _, core := range stmt.CompoundSelect {
+		if requiresWriteAccess(core.ProjectionExprs) {
+			return false
+		}
+	}
+
+	return stmt.basePreparedStatement.IsReadOnly()
+}
+
 func NewSelectStatement() *SelectStmt {
 	var p SelectStmt
 
@@ -165,6 +232,16 @@ func NewSelectStatement() *SelectStmt {
 
 // Prepare implements the Preparer interface.
 func (stmt *SelectStmt) Prepare(ctx *Context) (Statement, error) {
+	st, err := stmt.toStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return st.Prepare(ctx)
+}
+
+// toStream returns the stream of the statement, before optimization.
+func (stmt *SelectStmt) toStream(ctx *Context) (*StreamStmt, error) {
 	var s *stream.Stream
 
 	var prev scanner.Token
@@ -225,10 +302,76 @@ func (stmt *SelectStmt) Prepare(ctx *Context) (Statement, error) {
 		s = s.Pipe(stream.DocsTake(stmt.LimitExpr))
 	}
 
-	st := StreamStmt{
+	return &StreamStmt{
 		Stream:   s,
 		ReadOnly: readOnly,
+	}, nil
+}
+
+// ReadsFrom returns the names of the tables and views read by the statement.
+// It implements the database.ViewQuery interface.
+func (stmt *SelectStmt) ReadsFrom() []string {
+	var names []string
+	for _, coreSelect := range stmt.CompoundSelect {
+		if coreSelect.TableName != "" {
+			names = append(names, coreSelect.TableName)
+		}
 	}
 
-	return st.Prepare(ctx)
+	return names
+}
+
+// String returns a SQL representation.
+func (stmt *SelectStmt) String() string {
+	var s strings.Builder
+
+	for i, coreSelect := range stmt.CompoundSelect {
+		if i > 0 {
+			switch stmt.CompoundOperators[i-1] {
+			case scanner.UNION:
+				s.WriteString(" UNION ")
+			case scanner.ALL:
+				s.WriteString(" UNION ALL ")
+			}
+		}
+
+		s.WriteString(coreSelect.String())
+	}
+
+	if stmt.OrderBy != nil {
+		fmt.Fprintf(&s, " ORDER BY %s", stmt.OrderBy)
+		if stmt.OrderByDirection == scanner.DESC {
+			s.WriteString(" DESC")
+		}
+	}
+
+	if stmt.LimitExpr != nil {
+		fmt.Fprintf(&s, " LIMIT %s", stmt.LimitExpr)
+	}
+
+	if stmt.OffsetExpr != nil {
+		fmt.Fprintf(&s, " OFFSET %s", stmt.OffsetExpr)
+	}
+
+	return s.String()
+}
+
+// requiresWriteAccess returns true if any of the expressions
+// modifies the database when evaluated.
+func requiresWriteAccess(exprs []expr.Expr) bool {
+	var found bool
+
+	for _, e := range exprs {
+		expr.Walk(e, func(e expr.Expr) bool {
+			switch e.(type) {
+			case expr.NextValueFor, *functions.SetVal:
+				found = true
+				return false
+			default:
+				return true
+			}
+		})
+	}
+
+	return found
 }
@@ -0,0 +1,101 @@
+package statement
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/types"
+)
+
+// A Session holds the parameters changed by the SET statement.
+// They only apply to the statements run within the session,
+// the other sessions keep using the parameters of the database.
+type Session struct {
+	// statement timeout in nanoseconds, or -1 to use the one of the database.
+	statementTimeout int64
+}
+
+// NewSession returns a session using the parameters of the database.
+func NewSession() *Session {
+	return &Session{statementTimeout: -1}
+}
+
+// StatementTimeout returns the maximum duration of the statements run within the session.
+// If s is nil or if the timeout wasn't set by the session, it returns the one of the database.
+func (s *Session) StatementTimeout(db *database.Database) time.Duration {
+	if s != nil {
+		if d := atomic.LoadInt64(&s.statementTimeout); d >= 0 {
+			return time.Duration(d)
+		}
+	}
+
+	return db.StatementTimeout()
+}
+
+// SetStmt is a DSL that allows creating a SET statement.
+// It changes the value of a parameter of the session.
+type SetStmt struct {
+	Name string
+	// Value of the parameter. A NULL value resets
+	// the parameter to the value of the database.
+	Value types.Value
+}
+
+// IsReadOnly always returns true. It implements the Statement interface.
+func (stmt SetStmt) IsReadOnly() bool {
+	return true
+}
+
+// Run sets the parameter. It implements the Statement interface.
+func (stmt SetStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	if ctx.Session == nil {
+		return res, errors.New("parameters can only be set within a session")
+	}
+
+	switch stmt.Name {
+	case "statement_timeout":
+		if stmt.Value.Type() == types.NullValue {
+			atomic.StoreInt64(&ctx.Session.statementTimeout, -1)
+			return res, nil
+		}
+
+		d, err := parseTimeout(stmt.Value)
+		if err != nil {
+			return res, errors.Wrapf(err, "invalid value for %s", stmt.Name)
+		}
+
+		atomic.StoreInt64(&ctx.Session.statementTimeout, int64(d))
+		return res, nil
+	}
+
+	return res, errors.Errorf("unknown parameter %q", stmt.Name)
+}
+
+// parseTimeout converts a text duration, such as '1m30s', or an integer
+// number of milliseconds into a duration.
+func parseTimeout(v types.Value) (time.Duration, error) {
+	var d time.Duration
+
+	switch v.Type() {
+	case types.IntegerValue:
+		d = time.Duration(v.V().(int64)) * time.Millisecond
+	case types.TextValue:
+		var err error
+		d, err = time.ParseDuration(v.V().(string))
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, errors.Errorf("expected duration or integer, got %s", v.Type())
+	}
+
+	if d < 0 {
+		return 0, errors.New("timeout cannot be negative")
+	}
+
+	return d, nil
+}
@@ -1,10 +1,14 @@
 package statement
 
 import (
+	"context"
+
 	"github.com/cockroachdb/errors"
 	"github.com/genjidb/genji/document"
+	errs "github.com/genjidb/genji/errors"
 	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/environment"
+	"github.com/genjidb/genji/internal/stream"
 	"github.com/genjidb/genji/types"
 )
 
@@ -32,11 +36,42 @@ func (stmt *basePreparedStatement) Run(ctx *Context) (Result, error) {
 	return s.Run(ctx)
 }
 
+// hasAfterTriggers returns whether the table has AFTER triggers for the given event.
+func hasAfterTriggers(ctx *Context, tableName string, event database.TriggerEvent) bool {
+	for _, t := range ctx.Catalog.GetTableTriggers(tableName) {
+		if t.Time == database.TriggerAfter && t.Event == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkWritable returns an error if the given relation is a view.
+// Views, materialized or not, cannot be written to.
+func checkWritable(ctx *Context, tableName string) error {
+	_, err := ctx.Catalog.GetViewInfo(tableName)
+	if err == nil {
+		return errors.Errorf("cannot write to view %s", tableName)
+	}
+	if errs.IsNotFoundError(err) {
+		return nil
+	}
+
+	return err
+}
+
 type Context struct {
+	// Ctx interrupts the statement once it is done.
+	// If nil, the statement cannot be interrupted.
+	Ctx     context.Context
 	DB      *database.Database
 	Tx      *database.Transaction
 	Catalog *database.Catalog
 	Params  []environment.Param
+	// Session whose parameters are changed by the SET statement.
+	// If nil, the parameters of the database are used and cannot be changed.
+	Session *Session
 }
 
 type Preparer interface {
@@ -47,8 +82,15 @@ type Preparer interface {
 type Result struct {
 	Iterator document.Iterator
 	Tx       *database.Transaction
-	closed   bool
-	err      error
+	// Stats counts the documents written by the statement.
+	// They are only known once the result has been iterated over.
+	// It is nil if the statement is not run as a stream.
+	Stats *environment.WriteStats
+	// Cancel releases the context of the statement, if any.
+	// It is called by Close.
+	Cancel context.CancelFunc
+	closed bool
+	err    error
 }
 
 func (r *Result) Iterate(fn func(d types.Document) error) error {
@@ -60,6 +102,19 @@ func (r *Result) Iterate(fn func(d types.Document) error) error {
 	return r.err
 }
 
+// Skip iterates over the result and discards the documents.
+// Projected documents are evaluated lazily, they are evaluated nonetheless
+// to run the expressions that modify the database, such as NEXT VALUE FOR.
+func (r *Result) Skip() error {
+	return r.Iterate(func(d types.Document) error {
+		if _, ok := d.(*stream.MaskDocument); !ok {
+			return nil
+		}
+
+		return d.Iterate(func(string, types.Value) error { return nil })
+	})
+}
+
 // Close the result stream.
 // After closing the result, Stream is not supposed to be used.
 // If the result stream was already closed, it returns an error.
@@ -74,6 +129,10 @@ func (r *Result) Close() (err error) {
 
 	r.closed = true
 
+	if r.Cancel != nil {
+		defer r.Cancel()
+	}
+
 	if r.Tx != nil {
 		if r.Tx.Writable && r.err == nil {
 			err = r.Tx.Commit()
@@ -37,11 +37,15 @@ type PreparedStreamStmt struct {
 // Run returns a result containing the stream. The stream will be executed by calling the Iterate method of
 // the result.
 func (s *PreparedStreamStmt) Run(ctx *Context) (Result, error) {
+	var stats environment.WriteStats
+
 	return Result{
 		Iterator: &StreamStmtIterator{
 			Stream:  s.Stream,
 			Context: ctx,
+			Stats:   &stats,
 		},
+		Stats: &stats,
 	}, nil
 }
 
@@ -58,6 +62,8 @@ func (s *PreparedStreamStmt) String() string {
 type StreamStmtIterator struct {
 	Stream  *stream.Stream
 	Context *Context
+	// Stats counts the documents written during the iteration, if not nil.
+	Stats *environment.WriteStats
 }
 
 func (s *StreamStmtIterator) Iterate(fn func(d types.Document) error) error {
@@ -65,6 +71,11 @@ func (s *StreamStmtIterator) Iterate(fn func(d types.Document) error) error {
 	env.DB = s.Context.DB
 	env.Tx = s.Context.Tx
 	env.Catalog = s.Context.Catalog
+	env.Stats = s.Stats
+	env.Ctx = s.Context.Ctx
+	if s.Context.Session != nil {
+		env.Session = s.Context.Session
+	}
 	env.SetParams(s.Context.Params)
 
 	err := s.Stream.Iterate(&env, func(env *environment.Environment) error {
@@ -0,0 +1,108 @@
+package statement
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/internal/environment"
+	"github.com/genjidb/genji/internal/expr"
+	"github.com/genjidb/genji/internal/stream"
+	"github.com/genjidb/genji/types"
+)
+
+// TriggerAction holds the condition and the statements run by a trigger.
+// It implements the stream.TriggerRunner interface.
+type TriggerAction struct {
+	When       expr.Expr
+	Statements []Preparer
+}
+
+// Prepare prepares every statement of the trigger in the transaction of the environment.
+// It implements the stream.TriggerRunner interface.
+func (a *TriggerAction) Prepare(env *environment.Environment) (stream.PreparedTrigger, error) {
+	ctx := Context{
+		Ctx:     env.GetContext(),
+		DB:      env.GetDB(),
+		Tx:      env.GetTx(),
+		Catalog: env.GetCatalog(),
+	}
+	if session, ok := env.GetSession().(*Session); ok {
+		ctx.Session = session
+	}
+
+	pa := preparedTriggerAction{
+		when:    a.When,
+		streams: make([]*stream.Stream, 0, len(a.Statements)),
+	}
+
+	for _, stmt := range a.Statements {
+		st, err := stmt.Prepare(&ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ps, ok := st.(*PreparedStreamStmt)
+		if !ok {
+			return nil, errors.Errorf("unsupported trigger statement %s", stmt)
+		}
+
+		pa.streams = append(pa.streams, ps.Stream)
+	}
+
+	return &pa, nil
+}
+
+// preparedTriggerAction holds the prepared statements of a trigger.
+type preparedTriggerAction struct {
+	when    expr.Expr
+	streams []*stream.Stream
+}
+
+// Run evaluates the WHEN condition, if any, and runs every statement
+// of the trigger in the transaction of the environment.
+// The NEW and OLD documents are available to the statements through the environment.
+func (a *preparedTriggerAction) Run(env *environment.Environment) error {
+	if a.when != nil {
+		v, err := a.when.Eval(env)
+		if err != nil {
+			return err
+		}
+
+		ok, err := types.IsTruthy(v)
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	for _, s := range a.streams {
+		var newEnv environment.Environment
+		newEnv.SetOuter(env)
+
+		err := s.Iterate(&newEnv, func(out *environment.Environment) error {
+			return nil
+		})
+		if err != nil && !errors.Is(err, stream.ErrStreamClosed) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// String returns a SQL representation.
+func (a *TriggerAction) String() string {
+	var s strings.Builder
+
+	if a.When != nil {
+		fmt.Fprintf(&s, "WHEN %s ", a.When)
+	}
+
+	s.WriteString("BEGIN ")
+	for _, stmt := range a.Statements {
+		fmt.Fprintf(&s, "%s; ", stmt)
+	}
+	s.WriteString("END")
+
+	return s.String()
+}
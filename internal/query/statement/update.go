@@ -1,10 +1,15 @@
 package statement
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/cockroachdb/errors"
 	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/expr"
 	"github.com/genjidb/genji/internal/stream"
+	"github.com/genjidb/genji/internal/stringutil"
 )
 
 // UpdateConfig holds UPDATE configuration.
@@ -43,6 +48,10 @@ type UpdateSetPair struct {
 
 // Prepare implements the Preparer interface.
 func (stmt *UpdateStmt) Prepare(c *Context) (Statement, error) {
+	if err := checkWritable(c, stmt.TableName); err != nil {
+		return nil, err
+	}
+
 	ti, err := c.Catalog.GetTableInfo(stmt.TableName)
 	if err != nil {
 		return nil, err
@@ -56,6 +65,38 @@ func (stmt *UpdateStmt) Prepare(c *Context) (Statement, error) {
 	}
 
 	var pkModified bool
+
+	// generated fields are computed when the document is validated
+	// and cannot be modified directly.
+	var generated []string
+	for _, fc := range ti.FieldConstraints {
+		if !fc.IsGenerated() {
+			continue
+		}
+
+		for _, pair := range stmt.SetPairs {
+			if pair.Path[0].FieldName == fc.Path[0].FieldName {
+				return nil, fmt.Errorf("cannot update generated field %q", fc.Path)
+			}
+		}
+		for _, name := range stmt.UnsetFields {
+			if name == fc.Path[0].FieldName {
+				return nil, fmt.Errorf("cannot update generated field %q", fc.Path)
+			}
+		}
+
+		// if the primary key is generated, any update may modify it
+		if pk != nil {
+			for _, p := range pk.Paths {
+				if p.IsEqual(fc.Path) {
+					pkModified = true
+				}
+			}
+		}
+
+		generated = append(generated, fc.Path[0].FieldName)
+	}
+
 	if stmt.SetPairs != nil {
 		for _, pair := range stmt.SetPairs {
 			// if we modify the primary key,
@@ -85,6 +126,11 @@ func (stmt *UpdateStmt) Prepare(c *Context) (Statement, error) {
 		}
 	}
 
+	// remove the stored values of generated fields before computing them again
+	for _, name := range generated {
+		s = s.Pipe(stream.PathsUnset(name))
+	}
+
 	// validate document
 	s = s.Pipe(stream.TableValidate(stmt.TableName))
 
@@ -96,6 +142,20 @@ func (stmt *UpdateStmt) Prepare(c *Context) (Statement, error) {
 		s = s.Pipe(stream.IndexDelete(indexName))
 	}
 
+	// check unique constraints, once the old values of the document are removed from the indexes
+	for _, indexName := range indexNames {
+		info, err := c.Catalog.GetIndexInfo(indexName)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.Unique {
+			s = s.Pipe(stream.IndexValidate(indexName))
+		}
+	}
+
+	// when the primary key is modified, the document is deleted and inserted again.
+	// This fires the DELETE and INSERT triggers of the table instead of the UPDATE ones.
 	if pkModified {
 		s = s.Pipe(stream.TableDelete(stmt.TableName))
 		s = s.Pipe(stream.TableInsert(stmt.TableName))
@@ -107,6 +167,17 @@ func (stmt *UpdateStmt) Prepare(c *Context) (Statement, error) {
 		s = s.Pipe(stream.IndexInsert(indexName))
 	}
 
+	// AFTER triggers fire once the indexes are up to date
+	events := []database.TriggerEvent{database.TriggerUpdate}
+	if pkModified {
+		events = []database.TriggerEvent{database.TriggerDelete, database.TriggerInsert}
+	}
+	for _, event := range events {
+		if hasAfterTriggers(c, stmt.TableName, event) {
+			s = s.Pipe(stream.TableAfterTriggers(stmt.TableName, event))
+		}
+	}
+
 	st := StreamStmt{
 		Stream:   s,
 		ReadOnly: false,
@@ -114,3 +185,34 @@ func (stmt *UpdateStmt) Prepare(c *Context) (Statement, error) {
 
 	return st.Prepare(c)
 }
+
+// String returns a SQL representation.
+func (stmt *UpdateStmt) String() string {
+	var s strings.Builder
+
+	fmt.Fprintf(&s, "UPDATE %s", stringutil.NormalizeIdentifier(stmt.TableName, '`'))
+
+	if stmt.SetPairs != nil {
+		s.WriteString(" SET ")
+		for i, pair := range stmt.SetPairs {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+			fmt.Fprintf(&s, "%s = %s", pair.Path, pair.E)
+		}
+	} else {
+		s.WriteString(" UNSET ")
+		for i, f := range stmt.UnsetFields {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+			s.WriteString(stringutil.NormalizeIdentifier(f, '`'))
+		}
+	}
+
+	if stmt.WhereExpr != nil {
+		fmt.Fprintf(&s, " WHERE %s", stmt.WhereExpr)
+	}
+
+	return s.String()
+}
@@ -41,7 +41,11 @@ func (stmt BeginStmt) Run(ctx *statement.Context) (statement.Result, error) {
 }
 
 // RollbackStmt is a statement that rollbacks the current active transaction.
-type RollbackStmt struct{}
+// If Savepoint is set, only the modifications made since the savepoint
+// are rolled back and the transaction remains active.
+type RollbackStmt struct {
+	Savepoint string
+}
 
 // Prepare implements the Preparer interface.
 func (stmt RollbackStmt) Prepare(*statement.Context) (statement.Statement, error) {
@@ -53,6 +57,15 @@ func (stmt RollbackStmt) alterQuery(ctx context.Context, db *database.Database,
 		return errors.New("cannot rollback with no active transaction")
 	}
 
+	if stmt.Savepoint != "" {
+		sp, err := q.tx.GetSavepoint(stmt.Savepoint)
+		if err != nil {
+			return err
+		}
+
+		return sp.Rollback()
+	}
+
 	err := q.tx.Rollback()
 	if err != nil {
 		return err
@@ -99,3 +112,74 @@ func (stmt CommitStmt) IsReadOnly() bool {
 func (stmt CommitStmt) Run(ctx *statement.Context) (statement.Result, error) {
 	return statement.Result{}, errors.New("cannot commit with no active transaction")
 }
+
+// SavepointStmt is a statement that creates a savepoint in the current active transaction.
+type SavepointStmt struct {
+	Name string
+}
+
+// Prepare implements the Preparer interface.
+func (stmt SavepointStmt) Prepare(*statement.Context) (statement.Statement, error) {
+	return stmt, nil
+}
+
+func (stmt SavepointStmt) alterQuery(ctx context.Context, db *database.Database, q *Query) error {
+	if q.tx == nil || q.autoCommit {
+		return errors.New("cannot create a savepoint with no active transaction")
+	}
+
+	q.tx.Savepoint(stmt.Name)
+	return nil
+}
+
+func (stmt SavepointStmt) IsReadOnly() bool {
+	return true
+}
+
+func (stmt SavepointStmt) Run(ctx *statement.Context) (statement.Result, error) {
+	return statement.Result{}, errors.New("cannot create a savepoint with no active transaction")
+}
+
+// ReleaseStmt is a statement that releases a savepoint of the current active transaction.
+type ReleaseStmt struct {
+	Name string
+}
+
+// Prepare implements the Preparer interface.
+func (stmt ReleaseStmt) Prepare(*statement.Context) (statement.Statement, error) {
+	return stmt, nil
+}
+
+func (stmt ReleaseStmt) alterQuery(ctx context.Context, db *database.Database, q *Query) error {
+	if q.tx == nil || q.autoCommit {
+		return errors.New("cannot release a savepoint with no active transaction")
+	}
+
+	sp, err := q.tx.GetSavepoint(stmt.Name)
+	if err != nil {
+		return err
+	}
+
+	return sp.Release()
+}
+
+func (stmt ReleaseStmt) IsReadOnly() bool {
+	return true
+}
+
+func (stmt ReleaseStmt) Run(ctx *statement.Context) (statement.Result, error) {
+	return statement.Result{}, errors.New("cannot release a savepoint with no active transaction")
+}
+
+// isSavepointStmt returns whether the statement only manages the savepoints
+// of the current transaction.
+func isSavepointStmt(stmt statement.Statement) bool {
+	switch t := stmt.(type) {
+	case SavepointStmt, ReleaseStmt:
+		return true
+	case RollbackStmt:
+		return t.Savepoint != ""
+	}
+
+	return false
+}
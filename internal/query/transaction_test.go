@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/document"
 	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTransactionRun(t *testing.T) {
@@ -46,3 +48,30 @@ func TestTransactionRun(t *testing.T) {
 		})
 	}
 }
+
+func TestSavepointError(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE test(a INT); BEGIN; INSERT INTO test (a) VALUES (1)")
+	assert.NoError(t, err)
+
+	// a failing savepoint statement doesn't end the transaction
+	err = db.Exec("RELEASE SAVEPOINT unknown")
+	assert.Error(t, err)
+	err = db.Exec("ROLLBACK TO SAVEPOINT unknown")
+	assert.Error(t, err)
+
+	err = db.Exec("INSERT INTO test (a) VALUES (2)")
+	assert.NoError(t, err)
+	err = db.Exec("COMMIT")
+	assert.NoError(t, err)
+
+	d, err := db.QueryDocument("SELECT COUNT(*) AS n FROM test")
+	assert.NoError(t, err)
+	var n int
+	err = document.Scan(d, &n)
+	assert.NoError(t, err)
+	require.Equal(t, 2, n)
+}
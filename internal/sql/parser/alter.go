@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/cockroachdb/errors"
 
 	"github.com/genjidb/genji/internal/database"
@@ -56,15 +58,97 @@ func (p *Parser) parseAlterTableAddFieldStatement(tableName string) (_ statement
 	return stmt, nil
 }
 
+func (p *Parser) parseAlterTableDropFieldStatement(tableName string) (_ statement.AlterTableDropField, err error) {
+	var stmt statement.AlterTableDropField
+	stmt.TableName = tableName
+
+	// Parse "FIELD".
+	if err := p.parseTokens(scanner.FIELD); err != nil {
+		return stmt, err
+	}
+
+	// Parse field name.
+	stmt.Field, err = p.parseIdent()
+	if err != nil {
+		return stmt, err
+	}
+
+	return stmt, nil
+}
+
+func (p *Parser) parseAlterTableRenameFieldStatement(tableName string) (_ statement.AlterTableRenameField, err error) {
+	var stmt statement.AlterTableRenameField
+	stmt.TableName = tableName
+
+	// Parse field name.
+	stmt.Field, err = p.parseIdent()
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse "TO".
+	if err := p.parseTokens(scanner.TO); err != nil {
+		return stmt, err
+	}
+
+	// Parse new field name.
+	stmt.NewName, err = p.parseIdent()
+	if err != nil {
+		return stmt, err
+	}
+
+	return stmt, nil
+}
+
+func (p *Parser) parseAlterTableAlterFieldStatement(tableName string) (_ statement.AlterTableAlterFieldType, err error) {
+	var stmt statement.AlterTableAlterFieldType
+	stmt.TableName = tableName
+
+	// Parse "FIELD".
+	if err := p.parseTokens(scanner.FIELD); err != nil {
+		return stmt, err
+	}
+
+	// Parse field path.
+	stmt.Path, err = p.parsePath()
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse "TYPE".
+	// TYPE is not a keyword, to allow using it as a field name.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.IDENT || !strings.EqualFold(lit, "TYPE") {
+		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{"TYPE"}, pos)
+	}
+
+	// Parse new type.
+	stmt.Type, err = p.parseType()
+	if err != nil {
+		return stmt, err
+	}
+
+	return stmt, nil
+}
+
 // parseAlterStatement parses a Alter query string and returns a Statement AST object.
 func (p *Parser) parseAlterStatement() (statement.Statement, error) {
 	var err error
 
-	// Parse "TABLE".
-	if err := p.parseTokens(scanner.ALTER, scanner.TABLE); err != nil {
+	// Parse "ALTER".
+	if err := p.parseTokens(scanner.ALTER); err != nil {
 		return nil, err
 	}
 
+	// Parse "TABLE" or "SEQUENCE".
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch tok {
+	case scanner.TABLE:
+	case scanner.SEQUENCE:
+		return p.parseAlterSequenceStatement()
+	default:
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "SEQUENCE"}, pos)
+	}
+
 	// Parse table name.
 	tableName, err := p.parseIdent()
 	if err != nil {
@@ -73,13 +157,69 @@ func (p *Parser) parseAlterStatement() (statement.Statement, error) {
 		return nil, pErr
 	}
 
-	tok, pos, lit := p.ScanIgnoreWhitespace()
+	tok, pos, lit = p.ScanIgnoreWhitespace()
 	switch tok {
 	case scanner.RENAME:
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok == scanner.FIELD {
+			return p.parseAlterTableRenameFieldStatement(tableName)
+		}
+		p.Unscan()
 		return p.parseAlterTableRenameStatement(tableName)
 	case scanner.ADD_KEYWORD:
 		return p.parseAlterTableAddFieldStatement(tableName)
+	case scanner.DROP:
+		return p.parseAlterTableDropFieldStatement(tableName)
+	case scanner.ALTER:
+		return p.parseAlterTableAlterFieldStatement(tableName)
 	}
 
-	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"ADD", "RENAME"}, pos)
+	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"ADD", "ALTER", "DROP", "RENAME"}, pos)
+}
+
+// parseAlterSequenceStatement parses an alter sequence string and returns a Statement AST object.
+// This function assumes the ALTER SEQUENCE tokens have already been consumed.
+func (p *Parser) parseAlterSequenceStatement() (_ statement.AlterSequenceStmt, err error) {
+	var stmt statement.AlterSequenceStmt
+
+	// Parse sequence name
+	stmt.SequenceName, err = p.parseIdent()
+	if err != nil {
+		pErr := errors.Unwrap(err).(*ParseError)
+		pErr.Expected = []string{"sequence_name"}
+		return stmt, pErr
+	}
+
+	opts, err := p.parseSequenceOptions(true)
+	if err != nil {
+		return stmt, err
+	}
+
+	if opts.hasAsInt {
+		return stmt, &ParseError{Message: "cannot change the type of a sequence"}
+	}
+
+	stmt.IncrementBy = opts.incrementBy
+	stmt.Min = opts.min
+	stmt.Max = opts.max
+	stmt.NoMin = opts.hasNoMin
+	stmt.NoMax = opts.hasNoMax
+	stmt.Start = opts.start
+	stmt.Restart = opts.restart
+	stmt.RestartWith = opts.restartWith
+
+	if opts.cache != nil {
+		cache := uint64(*opts.cache)
+		stmt.Cache = &cache
+	}
+
+	if opts.cycle || opts.hasNoCycle {
+		stmt.Cycle = &opts.cycle
+	}
+
+	if stmt == (statement.AlterSequenceStmt{SequenceName: stmt.SequenceName}) {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{"RESTART", "INCREMENT", "MINVALUE", "MAXVALUE", "START", "CACHE", "CYCLE"}, pos)
+	}
+
+	return stmt, nil
 }
@@ -102,3 +102,74 @@ func TestParserAlterTableAddField(t *testing.T) {
 		})
 	}
 }
+
+func TestParserAlterTableField(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected statement.Statement
+		errored  bool
+	}{
+		{"Drop", "ALTER TABLE foo DROP FIELD bar", statement.AlterTableDropField{TableName: "foo", Field: "bar"}, false},
+		{"Rename", "ALTER TABLE foo RENAME FIELD bar TO baz", statement.AlterTableRenameField{TableName: "foo", Field: "bar", NewName: "baz"}, false},
+		{"Alter type", "ALTER TABLE foo ALTER FIELD bar TYPE double", statement.AlterTableAlterFieldType{TableName: "foo", Path: document.Path(testutil.ParsePath(t, "bar")), Type: types.DoubleValue}, false},
+		{"Alter type / nested path", "ALTER TABLE foo ALTER FIELD bar.baz type TEXT", statement.AlterTableAlterFieldType{TableName: "foo", Path: document.Path(testutil.ParsePath(t, "bar.baz")), Type: types.TextValue}, false},
+		{"With error / drop missing FIELD keyword", "ALTER TABLE foo DROP bar", nil, true},
+		{"With error / drop nested path", "ALTER TABLE foo DROP FIELD bar.baz", nil, true},
+		{"With error / rename missing TO", "ALTER TABLE foo RENAME FIELD bar baz", nil, true},
+		{"With error / alter missing TYPE", "ALTER TABLE foo ALTER FIELD bar double", nil, true},
+		{"With error / alter unknown type", "ALTER TABLE foo ALTER FIELD bar TYPE foo", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+			require.EqualValues(t, test.expected, q.Statements[0])
+		})
+	}
+}
+
+func TestParserAlterSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected statement.Statement
+		errored  bool
+	}{
+		{"RESTART", "ALTER SEQUENCE seq RESTART", statement.AlterSequenceStmt{SequenceName: "seq", Restart: true}, false},
+		{"RESTART WITH", "ALTER SEQUENCE seq RESTART WITH 10", statement.AlterSequenceStmt{SequenceName: "seq", Restart: true, RestartWith: testutil.Int64Ptr(10)}, false},
+		{"RESTART value", "ALTER SEQUENCE seq RESTART -10", statement.AlterSequenceStmt{SequenceName: "seq", Restart: true, RestartWith: testutil.Int64Ptr(-10)}, false},
+		{"INCREMENT BY", "ALTER SEQUENCE seq INCREMENT BY 10", statement.AlterSequenceStmt{SequenceName: "seq", IncrementBy: testutil.Int64Ptr(10)}, false},
+		{"MINVALUE MAXVALUE", "ALTER SEQUENCE seq MINVALUE 10 MAXVALUE 20", statement.AlterSequenceStmt{SequenceName: "seq", Min: testutil.Int64Ptr(10), Max: testutil.Int64Ptr(20)}, false},
+		{"NO MINVALUE NO MAXVALUE", "ALTER SEQUENCE seq NO MINVALUE NO MAXVALUE", statement.AlterSequenceStmt{SequenceName: "seq", NoMin: true, NoMax: true}, false},
+		{"START", "ALTER SEQUENCE seq START WITH 10", statement.AlterSequenceStmt{SequenceName: "seq", Start: testutil.Int64Ptr(10)}, false},
+		{"CACHE", "ALTER SEQUENCE seq CACHE 10", statement.AlterSequenceStmt{SequenceName: "seq", Cache: func() *uint64 { c := uint64(10); return &c }()}, false},
+		{"CYCLE", "ALTER SEQUENCE seq CYCLE", statement.AlterSequenceStmt{SequenceName: "seq", Cycle: func() *bool { b := true; return &b }()}, false},
+		{"NO CYCLE", "ALTER SEQUENCE seq NO CYCLE", statement.AlterSequenceStmt{SequenceName: "seq", Cycle: func() *bool { b := false; return &b }()}, false},
+		{"Multiple options", "ALTER SEQUENCE seq INCREMENT 2 RESTART WITH 5", statement.AlterSequenceStmt{SequenceName: "seq", IncrementBy: testutil.Int64Ptr(2), Restart: true, RestartWith: testutil.Int64Ptr(5)}, false},
+		{"No options", "ALTER SEQUENCE seq", nil, true},
+		{"AS", "ALTER SEQUENCE seq AS INTEGER", nil, true},
+		{"INCREMENT BY 0", "ALTER SEQUENCE seq INCREMENT BY 0", nil, true},
+		{"RESTART twice", "ALTER SEQUENCE seq RESTART RESTART", nil, true},
+		{"RESTART WITH without value", "ALTER SEQUENCE seq RESTART WITH", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+			require.EqualValues(t, test.expected, q.Statements[0])
+		})
+	}
+}
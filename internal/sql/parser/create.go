@@ -3,6 +3,9 @@ package parser
 import (
 	"fmt"
 	"math"
+	"strings"
+
+	"github.com/cockroachdb/errors"
 
 	"github.com/genjidb/genji/document"
 	"github.com/genjidb/genji/internal/database"
@@ -32,9 +35,19 @@ func (p *Parser) parseCreateStatement() (statement.Statement, error) {
 		return p.parseCreateIndexStatement(false)
 	case scanner.SEQUENCE:
 		return p.parseCreateSequenceStatement()
+	case scanner.VIEW:
+		return p.parseCreateViewStatement(false)
+	case scanner.MATERIALIZED:
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.VIEW {
+			return nil, newParseError(scanner.Tokstr(tok, lit), []string{"VIEW"}, pos)
+		}
+
+		return p.parseCreateViewStatement(true)
+	case scanner.TRIGGER:
+		return p.parseCreateTriggerStatement()
 	}
 
-	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "INDEX", "SEQUENCE"}, pos)
+	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "INDEX", "SEQUENCE", "VIEW", "MATERIALIZED", "TRIGGER"}, pos)
 }
 
 // parseCreateTableStatement parses a create table string and returns a Statement AST object.
@@ -57,7 +70,48 @@ func (p *Parser) parseCreateTableStatement() (*statement.CreateTableStmt, error)
 
 	// parse field constraints
 	err = p.parseConstraints(&stmt)
-	return &stmt, err
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse optional WITH TTL ON path
+	stmt.Info.TTLPath, err = p.parseTTL()
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse optional AS SELECT
+	if ok, err := p.parseOptional(scanner.AS); !ok || err != nil {
+		return &stmt, err
+	}
+
+	stmt.SelectStmt, err = p.parseSelectStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmt, nil
+}
+
+// parseTTL parses the optional TTL of a table:
+// WITH TTL ON path.
+func (p *Parser) parseTTL() (document.Path, error) {
+	if ok, err := p.parseOptional(scanner.WITH); !ok || err != nil {
+		return nil, err
+	}
+
+	// Parse "TTL"
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != scanner.IDENT || !strings.EqualFold(lit, "TTL") {
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TTL"}, pos)
+	}
+
+	// Parse "ON"
+	if err := p.parseTokens(scanner.ON); err != nil {
+		return nil, err
+	}
+
+	return p.parsePath()
 }
 
 func (p *Parser) parseConstraints(stmt *statement.CreateTableStmt) error {
@@ -237,6 +291,23 @@ LOOP:
 
 			info.TableConstraints.AddCheck(info.TableName, expr.Constraint(e))
 			addedTc++
+		case scanner.IDENT:
+			if !strings.EqualFold(lit, "GENERATED") {
+				p.Unscan()
+				break LOOP
+			}
+
+			// if it is already generated we return an error
+			if fc.IsGenerated() {
+				return newParseError(scanner.Tokstr(tok, lit), []string{"CONSTRAINT", ")"}, pos)
+			}
+
+			e, err := p.parseGeneratedExpr()
+			if err != nil {
+				return err
+			}
+
+			fc.GeneratedExpr = expr.Constraint(e)
 		default:
 			p.Unscan()
 			break LOOP
@@ -253,6 +324,40 @@ LOOP:
 	return nil
 }
 
+// parseGeneratedExpr parses the expression of a generated field:
+// ALWAYS AS (expr) [STORED].
+// This function assumes the GENERATED token has already been consumed.
+func (p *Parser) parseGeneratedExpr() (expr.Expr, error) {
+	// Parse "ALWAYS"
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != scanner.IDENT || !strings.EqualFold(lit, "ALWAYS") {
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"ALWAYS"}, pos)
+	}
+
+	// Parse "AS ("
+	if err := p.parseTokens(scanner.AS, scanner.LPAREN); err != nil {
+		return nil, err
+	}
+
+	e, err := p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse ")"
+	if err := p.parseTokens(scanner.RPAREN); err != nil {
+		return nil, err
+	}
+
+	// generated fields are always stored, STORED is optional
+	tok, _, lit = p.ScanIgnoreWhitespace()
+	if tok != scanner.IDENT || !strings.EqualFold(lit, "STORED") {
+		p.Unscan()
+	}
+
+	return e, nil
+}
+
 func (p *Parser) parseTableConstraint(stmt *statement.CreateTableStmt) (bool, error) {
 	var err error
 
@@ -384,8 +489,88 @@ func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt,
 		return nil, err
 	}
 
-	var hasAsInt, hasNoMin, hasNoMax, hasNoCycle bool
-	var min, max, incrementBy, start, cache *int64
+	opts, err := p.parseSequenceOptions(false)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.Info.Cycle = opts.cycle
+
+	// default value for increment is 1
+	if opts.incrementBy != nil {
+		stmt.Info.IncrementBy = *opts.incrementBy
+	} else {
+		stmt.Info.IncrementBy = 1
+	}
+
+	// determine if the sequence is ascending or descending
+	asc := stmt.Info.IncrementBy > 0
+
+	// default value for min is 1 if ascending
+	// or the minimum value of ints if descending
+	if opts.min != nil {
+		stmt.Info.Min = *opts.min
+	} else if asc {
+		stmt.Info.Min = 1
+	} else {
+		stmt.Info.Min = math.MinInt64
+	}
+
+	// default value for max is the maximum value of ints if ascending
+	// or the -1 if descending
+	if opts.max != nil {
+		stmt.Info.Max = *opts.max
+	} else if asc {
+		stmt.Info.Max = math.MaxInt64
+	} else {
+		stmt.Info.Max = -1
+	}
+
+	// check if min > max
+	if stmt.Info.Min > stmt.Info.Max {
+		return nil, &ParseError{Message: fmt.Sprintf("MINVALUE (%d) must be less than MAXVALUE (%d)", stmt.Info.Min, stmt.Info.Max)}
+	}
+
+	// default value for start is min if ascending
+	// or max if descending
+	if opts.start != nil {
+		stmt.Info.Start = *opts.start
+	} else if asc {
+		stmt.Info.Start = stmt.Info.Min
+	} else {
+		stmt.Info.Start = stmt.Info.Max
+	}
+
+	// check if min < start < max
+	if stmt.Info.Start < stmt.Info.Min {
+		return nil, &ParseError{Message: fmt.Sprintf("START value (%d) cannot be less than MINVALUE (%d)", stmt.Info.Start, stmt.Info.Min)}
+	}
+	if stmt.Info.Start > stmt.Info.Max {
+		return nil, &ParseError{Message: fmt.Sprintf("START value (%d) cannot be greater than MAXVALUE (%d)", stmt.Info.Start, stmt.Info.Max)}
+	}
+
+	// default for cache is 1
+	if opts.cache != nil {
+		stmt.Info.Cache = uint64(*opts.cache)
+	} else {
+		stmt.Info.Cache = 1
+	}
+	return &stmt, err
+}
+
+// sequenceOptions holds the options of a CREATE SEQUENCE or ALTER SEQUENCE statement.
+// Options that were not specified are nil or false.
+type sequenceOptions struct {
+	hasAsInt, hasNoMin, hasNoMax, hasNoCycle bool
+	cycle, restart                           bool
+
+	min, max, incrementBy, start, cache, restartWith *int64
+}
+
+// parseSequenceOptions parses the options of a sequence, in any order.
+// The RESTART option is only allowed if alter is true.
+func (p *Parser) parseSequenceOptions(alter bool) (*sequenceOptions, error) {
+	var o sequenceOptions
 
 	for {
 		// Parse AS [any int type]
@@ -399,11 +584,11 @@ func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt,
 				return nil, newParseError(scanner.Tokstr(tok, lit), []string{"INT"}, pos)
 			}
 
-			if hasAsInt {
+			if o.hasAsInt {
 				return nil, &ParseError{Message: "conflicting or redundant options"}
 			}
 
-			hasAsInt = true
+			o.hasAsInt = true
 			continue
 		}
 
@@ -412,7 +597,7 @@ func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt,
 			// parse optional BY token
 			_, _ = p.parseOptional(scanner.BY)
 
-			if incrementBy != nil {
+			if o.incrementBy != nil {
 				return nil, &ParseError{Message: "conflicting or redundant options"}
 			}
 
@@ -423,7 +608,7 @@ func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt,
 			if i == 0 {
 				return nil, &ParseError{Message: "INCREMENT must not be zero"}
 			}
-			incrementBy = &i
+			o.incrementBy = &i
 
 			continue
 		}
@@ -433,26 +618,26 @@ func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt,
 			tok, pos, lit := p.ScanIgnoreWhitespace()
 
 			if tok == scanner.MINVALUE {
-				if hasNoMin {
+				if o.hasNoMin {
 					return nil, &ParseError{Message: "conflicting or redundant options"}
 				}
-				hasNoMin = true
+				o.hasNoMin = true
 				continue
 			}
 
 			if tok == scanner.MAXVALUE {
-				if hasNoMax {
+				if o.hasNoMax {
 					return nil, &ParseError{Message: "conflicting or redundant options"}
 				}
-				hasNoMax = true
+				o.hasNoMax = true
 				continue
 			}
 
 			if tok == scanner.CYCLE {
-				if hasNoCycle {
+				if o.hasNoCycle {
 					return nil, &ParseError{Message: "conflicting or redundant options"}
 				}
-				hasNoCycle = true
+				o.hasNoCycle = true
 				continue
 			}
 
@@ -461,27 +646,27 @@ func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt,
 
 		// Parse MINVALUE integer
 		if ok, _ := p.parseOptional(scanner.MINVALUE); ok {
-			if hasNoMin || min != nil {
+			if o.hasNoMin || o.min != nil {
 				return nil, &ParseError{Message: "conflicting or redundant options"}
 			}
 			i, err := p.parseInteger()
 			if err != nil {
 				return nil, err
 			}
-			min = &i
+			o.min = &i
 			continue
 		}
 
 		// Parse MAXVALUE integer
 		if ok, _ := p.parseOptional(scanner.MAXVALUE); ok {
-			if hasNoMax || max != nil {
+			if o.hasNoMax || o.max != nil {
 				return nil, &ParseError{Message: "conflicting or redundant options"}
 			}
 			i, err := p.parseInteger()
 			if err != nil {
 				return nil, err
 			}
-			max = &i
+			o.max = &i
 			continue
 		}
 
@@ -490,7 +675,7 @@ func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt,
 			// parse optional WITH token
 			_, _ = p.parseOptional(scanner.WITH)
 
-			if start != nil {
+			if o.start != nil {
 				return nil, &ParseError{Message: "conflicting or redundant options"}
 			}
 
@@ -498,13 +683,13 @@ func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt,
 			if err != nil {
 				return nil, err
 			}
-			start = &i
+			o.start = &i
 			continue
 		}
 
 		// Parse CACHE integer
 		if ok, _ := p.parseOptional(scanner.CACHE); ok {
-			if cache != nil {
+			if o.cache != nil {
 				return nil, &ParseError{Message: "conflicting or redundant options"}
 			}
 
@@ -515,82 +700,239 @@ func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt,
 			if v < 0 {
 				return nil, &ParseError{Message: "cache value must be positive"}
 			}
-			cache = &v
+			o.cache = &v
 
 			continue
 		}
 
+		// Parse RESTART [[WITH] integer]
+		if alter {
+			tok, _, lit := p.ScanIgnoreWhitespace()
+			if tok == scanner.IDENT && strings.EqualFold(lit, "RESTART") {
+				if o.restart {
+					return nil, &ParseError{Message: "conflicting or redundant options"}
+				}
+				o.restart = true
+
+				// the value is optional if WITH is omitted
+				if ok, _ := p.parseOptional(scanner.WITH); !ok {
+					tok, _, _ := p.ScanIgnoreWhitespace()
+					p.Unscan()
+					if tok != scanner.INTEGER && tok != scanner.ADD && tok != scanner.SUB {
+						continue
+					}
+				}
+
+				i, err := p.parseInteger()
+				if err != nil {
+					return nil, err
+				}
+				o.restartWith = &i
+				continue
+			}
+			p.Unscan()
+		}
+
 		// Parse CYCLE
 		if ok, _ := p.parseOptional(scanner.CYCLE); ok {
-			if hasNoCycle || stmt.Info.Cycle {
+			if o.hasNoCycle || o.cycle {
 				return nil, &ParseError{Message: "conflicting or redundant options"}
 			}
 
-			stmt.Info.Cycle = true
+			o.cycle = true
 			continue
 		}
 
 		break
 	}
 
-	// default value for increment is 1
-	if incrementBy != nil {
-		stmt.Info.IncrementBy = *incrementBy
-	} else {
-		stmt.Info.IncrementBy = 1
+	return &o, nil
+}
+
+// parseCreateViewStatement parses a create view string and returns a Statement AST object.
+// This function assumes the CREATE VIEW or CREATE MATERIALIZED VIEW tokens have already been consumed.
+func (p *Parser) parseCreateViewStatement(materialized bool) (*statement.CreateViewStmt, error) {
+	var stmt statement.CreateViewStmt
+	var err error
+
+	stmt.Info.Materialized = materialized
+
+	// Parse IF NOT EXISTS
+	stmt.IfNotExists, err = p.parseOptional(scanner.IF, scanner.NOT, scanner.EXISTS)
+	if err != nil {
+		return nil, err
 	}
 
-	// determine if the sequence is ascending or descending
-	asc := stmt.Info.IncrementBy > 0
+	// Parse view name
+	stmt.Info.ViewName, err = p.parseIdent()
+	if err != nil {
+		pErr := errors.Unwrap(err).(*ParseError)
+		pErr.Expected = []string{"view_name"}
+		return nil, pErr
+	}
 
-	// default value for min is 1 if ascending
-	// or the minimum value of ints if descending
-	if min != nil {
-		stmt.Info.Min = *min
-	} else if asc {
-		stmt.Info.Min = 1
-	} else {
-		stmt.Info.Min = math.MinInt64
+	// Parse "AS"
+	if err := p.parseTokens(scanner.AS); err != nil {
+		return nil, err
 	}
 
-	// default value for max is the maximum value of ints if ascending
-	// or the -1 if descending
-	if max != nil {
-		stmt.Info.Max = *max
-	} else if asc {
-		stmt.Info.Max = math.MaxInt64
-	} else {
-		stmt.Info.Max = -1
+	orderedParams, namedParams := p.orderedParams, p.namedParams
+
+	// Parse the query of the view
+	query, err := p.parseSelectStatement()
+	if err != nil {
+		return nil, err
 	}
 
-	// check if min > max
-	if stmt.Info.Min > stmt.Info.Max {
-		return nil, &ParseError{Message: fmt.Sprintf("MINVALUE (%d) must be less than MAXVALUE (%d)", stmt.Info.Min, stmt.Info.Max)}
+	// the query is stored and run later, parameters cannot be bound to it.
+	if p.orderedParams != orderedParams || p.namedParams != namedParams {
+		return nil, errors.WithStack(&ParseError{Message: "views cannot use parameters"})
 	}
 
-	// default value for start is min if ascending
-	// or max if descending
-	if start != nil {
-		stmt.Info.Start = *start
-	} else if asc {
-		stmt.Info.Start = stmt.Info.Min
-	} else {
-		stmt.Info.Start = stmt.Info.Max
+	stmt.Info.Query = query
+
+	return &stmt, nil
+}
+
+// parseCreateTriggerStatement parses a create trigger string and returns a Statement AST object.
+// This function assumes the CREATE TRIGGER tokens have already been consumed.
+func (p *Parser) parseCreateTriggerStatement() (*statement.CreateTriggerStmt, error) {
+	var stmt statement.CreateTriggerStmt
+	var err error
+
+	// Parse IF NOT EXISTS
+	stmt.IfNotExists, err = p.parseOptional(scanner.IF, scanner.NOT, scanner.EXISTS)
+	if err != nil {
+		return nil, err
 	}
 
-	// check if min < start < max
-	if stmt.Info.Start < stmt.Info.Min {
-		return nil, &ParseError{Message: fmt.Sprintf("START value (%d) cannot be less than MINVALUE (%d)", stmt.Info.Start, stmt.Info.Min)}
+	// Parse trigger name
+	stmt.Info.TriggerName, err = p.parseIdent()
+	if err != nil {
+		pErr := errors.Unwrap(err).(*ParseError)
+		pErr.Expected = []string{"trigger_name"}
+		return nil, pErr
 	}
-	if stmt.Info.Start > stmt.Info.Max {
-		return nil, &ParseError{Message: fmt.Sprintf("START value (%d) cannot be greater than MAXVALUE (%d)", stmt.Info.Start, stmt.Info.Max)}
+
+	// Parse BEFORE or AFTER
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch {
+	case tok == scanner.IDENT && strings.EqualFold(lit, "BEFORE"):
+		stmt.Info.Time = database.TriggerBefore
+	case tok == scanner.IDENT && strings.EqualFold(lit, "AFTER"):
+		stmt.Info.Time = database.TriggerAfter
+	default:
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"BEFORE", "AFTER"}, pos)
 	}
 
-	// default for cache is 1
-	if cache != nil {
-		stmt.Info.Cache = uint64(*cache)
-	} else {
-		stmt.Info.Cache = 1
+	// Parse INSERT, UPDATE or DELETE
+	tok, pos, lit = p.ScanIgnoreWhitespace()
+	switch tok {
+	case scanner.INSERT:
+		stmt.Info.Event = database.TriggerInsert
+	case scanner.UPDATE:
+		stmt.Info.Event = database.TriggerUpdate
+	case scanner.DELETE:
+		stmt.Info.Event = database.TriggerDelete
+	default:
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"INSERT", "UPDATE", "DELETE"}, pos)
 	}
-	return &stmt, err
+
+	// Parse "ON"
+	if err := p.parseTokens(scanner.ON); err != nil {
+		return nil, err
+	}
+
+	// Parse table name
+	stmt.Info.TableName, err = p.parseIdent()
+	if err != nil {
+		pErr := errors.Unwrap(err).(*ParseError)
+		pErr.Expected = []string{"table_name"}
+		return nil, pErr
+	}
+
+	orderedParams, namedParams := p.orderedParams, p.namedParams
+
+	stmt.Info.Action, err = p.parseTriggerAction()
+	if err != nil {
+		return nil, err
+	}
+
+	// the action is stored and run later, parameters cannot be bound to it.
+	if p.orderedParams != orderedParams || p.namedParams != namedParams {
+		return nil, errors.WithStack(&ParseError{Message: "triggers cannot use parameters"})
+	}
+
+	return &stmt, nil
+}
+
+// parseTriggerAction parses the action of a trigger:
+// [FOR EACH ROW] [WHEN expr] BEGIN stmt; [stmt; ...] END
+func (p *Parser) parseTriggerAction() (*statement.TriggerAction, error) {
+	var action statement.TriggerAction
+
+	// Parse optional FOR EACH ROW, which is the only supported mode
+	if ok, err := p.parseOptional(scanner.FOR); err != nil {
+		return nil, err
+	} else if ok {
+		for _, word := range []string{"EACH", "ROW"} {
+			tok, pos, lit := p.ScanIgnoreWhitespace()
+			if tok != scanner.IDENT || !strings.EqualFold(lit, word) {
+				return nil, newParseError(scanner.Tokstr(tok, lit), []string{word}, pos)
+			}
+		}
+	}
+
+	// Parse optional WHEN condition
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok == scanner.IDENT && strings.EqualFold(lit, "WHEN") {
+		e, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		action.When = e
+
+		tok, pos, lit = p.ScanIgnoreWhitespace()
+	}
+
+	if tok != scanner.BEGIN {
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"WHEN", "BEGIN"}, pos)
+	}
+
+	// Parse the statements, each one of them followed by a semicolon
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok == scanner.IDENT && strings.EqualFold(lit, "END") && len(action.Statements) > 0 {
+			break
+		}
+		p.Unscan()
+
+		var stmt statement.Preparer
+		var err error
+		switch tok {
+		case scanner.INSERT:
+			stmt, err = p.parseInsertStatement()
+		case scanner.UPDATE:
+			stmt, err = p.parseUpdateStatement()
+		case scanner.DELETE:
+			stmt, err = p.parseDeleteStatement()
+		default:
+			expected := []string{"INSERT", "UPDATE", "DELETE"}
+			if len(action.Statements) > 0 {
+				expected = append(expected, "END")
+			}
+			return nil, newParseError(scanner.Tokstr(tok, lit), expected, pos)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.parseTokens(scanner.SEMICOLON); err != nil {
+			return nil, err
+		}
+
+		action.Statements = append(action.Statements, stmt)
+	}
+
+	return &action, nil
 }
@@ -1,6 +1,7 @@
 package parser_test
 
 import (
+	"fmt"
 	"math"
 	"testing"
 
@@ -78,6 +79,39 @@ func TestParserCreateTable(t *testing.T) {
 		{"With default and no parentheses", "CREATE TABLE test(foo DEFAULT (10)", nil, true},
 		{"With forbidden tokens", "CREATE TABLE test(foo DEFAULT a)", nil, true},
 		{"With forbidden tokens", "CREATE TABLE test(foo DEFAULT 1 AND 2)", nil, true},
+		{"With generated", "CREATE TABLE test(a TEXT, b TEXT GENERATED ALWAYS AS (a || 'b') STORED NOT NULL)",
+			&statement.CreateTableStmt{
+				Info: database.TableInfo{
+					TableName: "test",
+					FieldConstraints: []*database.FieldConstraint{
+						{Path: document.Path(testutil.ParseDocumentPath(t, "a")), Type: types.TextValue},
+						{Path: document.Path(testutil.ParseDocumentPath(t, "b")), Type: types.TextValue, IsNotNull: true, GeneratedExpr: expr.Constraint(testutil.ParseExpr(t, "a || 'b'"))},
+					},
+				},
+			}, false},
+		{"With TTL", "CREATE TABLE test(a INT, b TEXT) WITH TTL ON b",
+			&statement.CreateTableStmt{
+				Info: database.TableInfo{
+					TableName: "test",
+					FieldConstraints: []*database.FieldConstraint{
+						{Path: document.Path(testutil.ParseDocumentPath(t, "a")), Type: types.IntegerValue},
+						{Path: document.Path(testutil.ParseDocumentPath(t, "b")), Type: types.TextValue},
+					},
+					TTLPath: testutil.ParseDocumentPath(t, "b"),
+				},
+			}, false},
+		{"With nested TTL", "CREATE TABLE test WITH TTL ON a.b[0]",
+			&statement.CreateTableStmt{
+				Info: database.TableInfo{
+					TableName: "test",
+					TTLPath:   testutil.ParseDocumentPath(t, "a.b[0]"),
+				},
+			}, false},
+		{"With TTL and no path", "CREATE TABLE test(a INT) WITH TTL ON", nil, true},
+		{"With TTL and no ON", "CREATE TABLE test(a INT) WITH TTL a", nil, true},
+		{"With generated twice", "CREATE TABLE test(a, b GENERATED ALWAYS AS (a) GENERATED ALWAYS AS (a))", nil, true},
+		{"With generated and no parentheses", "CREATE TABLE test(a, b GENERATED ALWAYS AS a)", nil, true},
+		{"With generated and no always", "CREATE TABLE test(a, b GENERATED AS (a))", nil, true},
 		{"With unique", "CREATE TABLE test(foo.bar[0].baz UNIQUE)",
 			&statement.CreateTableStmt{
 				Info: database.TableInfo{
@@ -511,3 +545,143 @@ func TestParserCreateSequence(t *testing.T) {
 		})
 	}
 }
+
+func TestParserCreateView(t *testing.T) {
+	tests := []struct {
+		name         string
+		s            string
+		viewName     string
+		query        string
+		ifNotExists  bool
+		materialized bool
+		errored      bool
+	}{
+		{"Basic", "CREATE VIEW v AS SELECT * FROM test", "v", "SELECT * FROM test", false, false, false},
+		{"If not exists", "CREATE VIEW IF NOT EXISTS v AS SELECT a FROM test", "v", "SELECT a FROM test", true, false, false},
+		{"Materialized", "CREATE MATERIALIZED VIEW v AS SELECT a FROM test", "v", "SELECT a FROM test", false, true, false},
+		{"Complex query",
+			"CREATE VIEW `my view` AS SELECT DISTINCT a.b AS c, COUNT(*) FROM `my table` WHERE a > 1 GROUP BY a.b UNION ALL SELECT 1 UNION SELECT 2 ORDER BY c DESC LIMIT 10 OFFSET 20",
+			"my view",
+			"SELECT DISTINCT a.b AS c, COUNT(*) FROM `my table` WHERE a > 1 GROUP BY a.b UNION ALL SELECT 1 UNION SELECT 2 ORDER BY c DESC LIMIT 10 OFFSET 20",
+			false, false, false},
+		{"With error / missing AS", "CREATE VIEW v SELECT * FROM test", "", "", false, false, true},
+		{"With error / missing query", "CREATE VIEW v AS", "", "", false, false, true},
+		{"With error / not a select", "CREATE VIEW v AS DELETE FROM test", "", "", false, false, true},
+		{"With error / positional param", "CREATE VIEW v AS SELECT * FROM test WHERE a = ?", "", "", false, false, true},
+		{"With error / named param", "CREATE VIEW v AS SELECT * FROM test WHERE a = $a", "", "", false, false, true},
+		{"With error / missing VIEW", "CREATE MATERIALIZED v AS SELECT * FROM test", "", "", false, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+
+			stmt := q.Statements[0].(*statement.CreateViewStmt)
+			require.Equal(t, test.viewName, stmt.Info.ViewName)
+			require.Equal(t, test.ifNotExists, stmt.IfNotExists)
+			require.Equal(t, test.materialized, stmt.Info.Materialized)
+			require.Equal(t, test.query, stmt.Info.Query.String())
+		})
+	}
+}
+
+func TestParserCreateTableAsSelect(t *testing.T) {
+	tests := []struct {
+		name        string
+		s           string
+		tableName   string
+		fields      int
+		query       string
+		ifNotExists bool
+		errored     bool
+	}{
+		{"Basic", "CREATE TABLE test AS SELECT * FROM foo", "test", 0, "SELECT * FROM foo", false, false},
+		{"If not exists", "CREATE TABLE IF NOT EXISTS test AS SELECT a FROM foo", "test", 0, "SELECT a FROM foo", true, false},
+		{"With constraints", "CREATE TABLE test (a INT PRIMARY KEY, b TEXT) AS SELECT a, b FROM foo WHERE a > ?", "test", 2, "SELECT a, b FROM foo WHERE a > ?", false, false},
+		{"With error / missing query", "CREATE TABLE test AS", "", 0, "", false, true},
+		{"With error / not a select", "CREATE TABLE test AS DELETE FROM foo", "", 0, "", false, true},
+		{"With error / constraints after query", "CREATE TABLE test AS SELECT * FROM foo (a INT)", "", 0, "", false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+
+			stmt := q.Statements[0].(*statement.CreateTableStmt)
+			require.Equal(t, test.tableName, stmt.Info.TableName)
+			require.Equal(t, test.ifNotExists, stmt.IfNotExists)
+			require.Len(t, stmt.Info.FieldConstraints, test.fields)
+			require.Equal(t, test.query, fmt.Sprint(stmt.SelectStmt))
+		})
+	}
+}
+
+func TestParserCreateTrigger(t *testing.T) {
+	tests := []struct {
+		name        string
+		s           string
+		triggerName string
+		tableName   string
+		time        database.TriggerTime
+		event       database.TriggerEvent
+		action      string
+		ifNotExists bool
+		errored     bool
+	}{
+		{"Basic", "CREATE TRIGGER t AFTER INSERT ON test BEGIN INSERT INTO log VALUES {a: NEW.a}; END",
+			"t", "test", database.TriggerAfter, database.TriggerInsert, "BEGIN INSERT INTO log VALUES {a: NEW.a}; END", false, false},
+		{"If not exists", "CREATE TRIGGER IF NOT EXISTS t BEFORE UPDATE ON test BEGIN DELETE FROM log WHERE a = OLD.a; END",
+			"t", "test", database.TriggerBefore, database.TriggerUpdate, "BEGIN DELETE FROM log WHERE a = OLD.a; END", true, false},
+		{"Lowercase", "create trigger t before delete on test for each row when old.a > 1 begin delete from log; end",
+			"t", "test", database.TriggerBefore, database.TriggerDelete, "WHEN old.a > 1 BEGIN DELETE FROM log; END", false, false},
+		{"Multiple statements",
+			"CREATE TRIGGER `my trigger` AFTER UPDATE ON `my table` FOR EACH ROW WHEN NEW.a != OLD.a BEGIN INSERT INTO log (a, b) VALUES (NEW.a, 1) ON CONFLICT DO REPLACE; UPDATE log SET b = b + 1, c.d = 2 WHERE a = NEW.a; UPDATE log UNSET c; DELETE FROM log WHERE a > 10 ORDER BY a DESC LIMIT 1 OFFSET 2; END",
+			"my trigger", "my table", database.TriggerAfter, database.TriggerUpdate,
+			"WHEN NEW.a != OLD.a BEGIN INSERT INTO log (a, b) VALUES (NEW.a, 1) ON CONFLICT DO REPLACE; UPDATE log SET b = b + 1, c.d = 2 WHERE a = NEW.a; UPDATE log UNSET c; DELETE FROM log WHERE a > 10 ORDER BY a DESC LIMIT 1 OFFSET 2; END",
+			false, false},
+		{"Insert select", "CREATE TRIGGER t AFTER INSERT ON test BEGIN INSERT INTO log (a) SELECT b FROM foo; END",
+			"t", "test", database.TriggerAfter, database.TriggerInsert, "BEGIN INSERT INTO log (a) SELECT b FROM foo; END", false, false},
+		{"With error / missing time", "CREATE TRIGGER t INSERT ON test BEGIN DELETE FROM log; END", "", "", 0, 0, "", false, true},
+		{"With error / missing event", "CREATE TRIGGER t AFTER ON test BEGIN DELETE FROM log; END", "", "", 0, 0, "", false, true},
+		{"With error / missing ON", "CREATE TRIGGER t AFTER INSERT test BEGIN DELETE FROM log; END", "", "", 0, 0, "", false, true},
+		{"With error / missing EACH", "CREATE TRIGGER t AFTER INSERT ON test FOR ROW BEGIN DELETE FROM log; END", "", "", 0, 0, "", false, true},
+		{"With error / missing BEGIN", "CREATE TRIGGER t AFTER INSERT ON test DELETE FROM log; END", "", "", 0, 0, "", false, true},
+		{"With error / missing END", "CREATE TRIGGER t AFTER INSERT ON test BEGIN DELETE FROM log;", "", "", 0, 0, "", false, true},
+		{"With error / missing semicolon", "CREATE TRIGGER t AFTER INSERT ON test BEGIN DELETE FROM log END", "", "", 0, 0, "", false, true},
+		{"With error / no statements", "CREATE TRIGGER t AFTER INSERT ON test BEGIN END", "", "", 0, 0, "", false, true},
+		{"With error / select", "CREATE TRIGGER t AFTER INSERT ON test BEGIN SELECT 1; END", "", "", 0, 0, "", false, true},
+		{"With error / param", "CREATE TRIGGER t AFTER INSERT ON test BEGIN DELETE FROM log WHERE a = ?; END", "", "", 0, 0, "", false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+
+			stmt := q.Statements[0].(*statement.CreateTriggerStmt)
+			require.Equal(t, test.triggerName, stmt.Info.TriggerName)
+			require.Equal(t, test.tableName, stmt.Info.TableName)
+			require.Equal(t, test.time, stmt.Info.Time)
+			require.Equal(t, test.event, stmt.Info.Event)
+			require.Equal(t, test.ifNotExists, stmt.IfNotExists)
+			require.Equal(t, test.action, stmt.Info.Action.String())
+		})
+	}
+}
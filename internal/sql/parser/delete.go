@@ -8,7 +8,7 @@ import (
 )
 
 // parseDeleteStatement parses a delete string and returns a Statement AST object.
-func (p *Parser) parseDeleteStatement() (statement.Statement, error) {
+func (p *Parser) parseDeleteStatement() (*statement.DeleteStmt, error) {
 	stmt := statement.NewDeleteStatement()
 	var err error
 
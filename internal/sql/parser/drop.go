@@ -22,9 +22,13 @@ func (p *Parser) parseDropStatement() (statement.Statement, error) {
 		return p.parseDropIndexStatement()
 	case scanner.SEQUENCE:
 		return p.parseDropSequenceStatement()
+	case scanner.VIEW:
+		return p.parseDropViewStatement()
+	case scanner.TRIGGER:
+		return p.parseDropTriggerStatement()
 	}
 
-	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "INDEX", "SEQUENCE"}, pos)
+	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "INDEX", "SEQUENCE", "VIEW", "TRIGGER"}, pos)
 }
 
 // parseDropTableStatement parses a drop table string and returns a Statement AST object.
@@ -92,3 +96,47 @@ func (p *Parser) parseDropSequenceStatement() (statement.DropSequenceStmt, error
 
 	return stmt, nil
 }
+
+// parseDropViewStatement parses a drop view string and returns a Statement AST object.
+// This function assumes the DROP VIEW tokens have already been consumed.
+func (p *Parser) parseDropViewStatement() (statement.DropViewStmt, error) {
+	var stmt statement.DropViewStmt
+	var err error
+
+	stmt.IfExists, err = p.parseOptional(scanner.IF, scanner.EXISTS)
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse view name
+	stmt.ViewName, err = p.parseIdent()
+	if err != nil {
+		pErr := errors.Unwrap(err).(*ParseError)
+		pErr.Expected = []string{"view_name"}
+		return stmt, pErr
+	}
+
+	return stmt, nil
+}
+
+// parseDropTriggerStatement parses a drop trigger string and returns a Statement AST object.
+// This function assumes the DROP TRIGGER tokens have already been consumed.
+func (p *Parser) parseDropTriggerStatement() (statement.DropTriggerStmt, error) {
+	var stmt statement.DropTriggerStmt
+	var err error
+
+	stmt.IfExists, err = p.parseOptional(scanner.IF, scanner.EXISTS)
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse trigger name
+	stmt.TriggerName, err = p.parseIdent()
+	if err != nil {
+		pErr := errors.Unwrap(err).(*ParseError)
+		pErr.Expected = []string{"trigger_name"}
+		return stmt, pErr
+	}
+
+	return stmt, nil
+}
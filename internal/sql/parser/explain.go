@@ -13,6 +13,14 @@ func (p *Parser) parseExplainStatement() (statement.Statement, error) {
 		return nil, err
 	}
 
+	// Parse optional "ANALYZE".
+	var analyze bool
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == scanner.ANALYZE {
+		analyze = true
+	} else {
+		p.Unscan()
+	}
+
 	// ensure we don't have multiple EXPLAIN keywords
 	tok, pos, lit := p.ScanIgnoreWhitespace()
 	if tok != scanner.SELECT && tok != scanner.UPDATE && tok != scanner.DELETE && tok != scanner.INSERT {
@@ -25,5 +33,5 @@ func (p *Parser) parseExplainStatement() (statement.Statement, error) {
 		return nil, err
 	}
 
-	return &statement.ExplainStmt{Statement: innerStmt.(statement.Preparer)}, nil
+	return &statement.ExplainStmt{Statement: innerStmt.(statement.Preparer), Analyze: analyze}, nil
 }
@@ -94,6 +94,8 @@ func (p *Parser) ParseStatement() (statement.Statement, error) {
 	switch tok {
 	case scanner.ALTER:
 		return p.parseAlterStatement()
+	case scanner.ANALYZE:
+		return p.parseAnalyzeStatement()
 	case scanner.BEGIN:
 		return p.parseBeginStatement()
 	case scanner.COMMIT:
@@ -112,14 +114,22 @@ func (p *Parser) ParseStatement() (statement.Statement, error) {
 		return p.parseDropStatement()
 	case scanner.EXPLAIN:
 		return p.parseExplainStatement()
+	case scanner.REFRESH:
+		return p.parseRefreshStatement()
 	case scanner.REINDEX:
 		return p.parseReIndexStatement()
+	case scanner.RELEASE:
+		return p.parseReleaseStatement()
 	case scanner.ROLLBACK:
 		return p.parseRollbackStatement()
+	case scanner.SAVEPOINT:
+		return p.parseSavepointStatement()
+	case scanner.SET:
+		return p.parseSetStatement()
 	}
 
 	return nil, newParseError(scanner.Tokstr(tok, lit), []string{
-		"ALTER", "BEGIN", "COMMIT", "SELECT", "DELETE", "UPDATE", "INSERT", "CREATE", "DROP", "EXPLAIN", "REINDEX", "ROLLBACK",
+		"ALTER", "ANALYZE", "BEGIN", "COMMIT", "SELECT", "DELETE", "UPDATE", "INSERT", "CREATE", "DROP", "EXPLAIN", "REFRESH", "REINDEX", "RELEASE", "ROLLBACK", "SAVEPOINT", "SET",
 	}, pos)
 }
 
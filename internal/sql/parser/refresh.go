@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/internal/query/statement"
+	"github.com/genjidb/genji/internal/sql/scanner"
+)
+
+// parseRefreshStatement parses a REFRESH MATERIALIZED VIEW statement.
+func (p *Parser) parseRefreshStatement() (statement.Statement, error) {
+	var stmt statement.RefreshViewStmt
+	var err error
+
+	// Parse "REFRESH MATERIALIZED VIEW".
+	if err := p.parseTokens(scanner.REFRESH, scanner.MATERIALIZED, scanner.VIEW); err != nil {
+		return nil, err
+	}
+
+	// Parse view name
+	stmt.ViewName, err = p.parseIdent()
+	if err != nil {
+		pErr := errors.Unwrap(err).(*ParseError)
+		pErr.Expected = []string{"view_name"}
+		return nil, pErr
+	}
+
+	return stmt, nil
+}
@@ -0,0 +1,36 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/genjidb/genji/internal/query/statement"
+	"github.com/genjidb/genji/internal/sql/parser"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserRefresh(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected statement.Statement
+		errored  bool
+	}{
+		{"Refresh", "REFRESH MATERIALIZED VIEW test", statement.RefreshViewStmt{ViewName: "test"}, false},
+		{"With error / missing MATERIALIZED", "REFRESH VIEW test", nil, true},
+		{"With error / missing view name", "REFRESH MATERIALIZED VIEW", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+			require.EqualValues(t, test.expected, q.Statements[0])
+		})
+	}
+}
@@ -79,8 +79,7 @@ func TestParserSelect(t *testing.T) {
 		{"WithGroupBy", "SELECT a.b.c FROM test WHERE age = 10 GROUP BY a.b.c",
 			stream.New(stream.TableScan("test")).
 				Pipe(stream.DocsFilter(parser.MustParseExpr("age = 10"))).
-				Pipe(stream.DocsTempTreeSort(parser.MustParseExpr("a.b.c"))).
-				Pipe(stream.DocsGroupAggregate(parser.MustParseExpr("a.b.c"))).
+				Pipe(stream.DocsHashAggregate(parser.MustParseExpr("a.b.c"))).
 				Pipe(stream.DocsProject(&expr.NamedExpr{ExprName: "a.b.c", Expr: expr.Path(document.NewPath("a.b.c"))})),
 			true, false,
 		},
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/genjidb/genji/internal/query/statement"
+	"github.com/genjidb/genji/internal/sql/scanner"
+	"github.com/genjidb/genji/types"
+)
+
+// parseSetStatement parses a SET statement.
+func (p *Parser) parseSetStatement() (statement.Statement, error) {
+	var stmt statement.SetStmt
+	var err error
+
+	// Parse "SET".
+	if err := p.parseTokens(scanner.SET); err != nil {
+		return nil, err
+	}
+
+	// Parse parameter name
+	stmt.Name, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse "=" or "TO".
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.EQ && tok != scanner.TO {
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"=", "TO"}, pos)
+	}
+
+	// Parse value
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch tok {
+	case scanner.DEFAULT:
+		stmt.Value = types.NewNullValue()
+	case scanner.STRING:
+		stmt.Value = types.NewTextValue(lit)
+	case scanner.INTEGER:
+		i, err := strconv.ParseInt(lit, 10, 64)
+		if err != nil {
+			return nil, &ParseError{Message: "unable to parse integer", Pos: pos}
+		}
+		stmt.Value = types.NewIntegerValue(i)
+	default:
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"string", "integer", "DEFAULT"}, pos)
+	}
+
+	return stmt, nil
+}
@@ -0,0 +1,40 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/genjidb/genji/internal/query/statement"
+	"github.com/genjidb/genji/internal/sql/parser"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/genjidb/genji/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected statement.Statement
+		errored  bool
+	}{
+		{"Text", "SET statement_timeout = '5s'", statement.SetStmt{Name: "statement_timeout", Value: types.NewTextValue("5s")}, false},
+		{"Integer", "SET statement_timeout TO 100", statement.SetStmt{Name: "statement_timeout", Value: types.NewIntegerValue(100)}, false},
+		{"Default", "SET statement_timeout = DEFAULT", statement.SetStmt{Name: "statement_timeout", Value: types.NewNullValue()}, false},
+		{"No value", "SET statement_timeout", nil, true},
+		{"No operator", "SET statement_timeout 10", nil, true},
+		{"Expression", "SET statement_timeout = 1 + 1", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+			require.EqualValues(t, test.expected, q.Statements[0])
+		})
+	}
+}
@@ -120,6 +120,7 @@ func TestScanner_Scan(t *testing.T) {
 		// Keywords
 		{s: `ADD`, tok: ADD_KEYWORD},
 		{s: `ALTER`, tok: ALTER},
+		{s: `ANALYZE`, tok: ANALYZE},
 		{s: `AS`, tok: AS},
 		{s: `ASC`, tok: ASC},
 		{s: `ALL`, tok: ALL},
@@ -162,10 +163,12 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `PRIMARY`, tok: PRIMARY},
 		{s: `READ`, tok: READ},
 		{s: `REINDEX`, tok: REINDEX},
+		{s: `RELEASE`, tok: RELEASE},
 		{s: `RENAME`, tok: RENAME},
 		{s: `REPLACE`, tok: REPLACE},
 		{s: `RETURNING`, tok: RETURNING},
 		{s: `ROLLBACK`, tok: ROLLBACK},
+		{s: `SAVEPOINT`, tok: SAVEPOINT},
 		{s: `SELECT`, tok: SELECT},
 		{s: `SEQUENCE`, tok: SEQUENCE},
 		{s: `SET`, tok: SET},
@@ -79,6 +79,7 @@ const (
 	ADD_KEYWORD
 	ALL
 	ALTER
+	ANALYZE
 	AS
 	ASC
 	BEGIN
@@ -110,6 +111,7 @@ const (
 	INTO
 	KEY
 	LIMIT
+	MATERIALIZED
 	MAXVALUE
 	MINVALUE
 	NEXT
@@ -123,11 +125,14 @@ const (
 	PRECISION
 	PRIMARY
 	READ
+	REFRESH
 	REINDEX
+	RELEASE
 	RENAME
 	REPLACE
 	RETURNING
 	ROLLBACK
+	SAVEPOINT
 	SELECT
 	SEQUENCE
 	SET
@@ -135,12 +140,14 @@ const (
 	TABLE
 	TO
 	TRANSACTION
+	TRIGGER
 	UNION
 	UNIQUE
 	UNSET
 	UPDATE
 	VALUE
 	VALUES
+	VIEW
 	WITH
 	WHERE
 	WRITE
@@ -192,6 +199,7 @@ var tokens = [...]string{
 	BITWISEAND: "&",
 	BITWISEOR:  "|",
 	BITWISEXOR: "^",
+	CONCAT:     "||",
 	BETWEEN:    "BETWEEN",
 
 	AND: "AND",
@@ -221,74 +229,81 @@ var tokens = [...]string{
 	SEMICOLON:   ";",
 	DOT:         ".",
 
-	ADD_KEYWORD: "ADD",
-	ALL:         "ALL",
-	ALTER:       "ALTER",
-	AS:          "AS",
-	ASC:         "ASC",
-	BEGIN:       "BEGIN",
-	BY:          "BY",
-	CACHE:       "CACHE",
-	CAST:        "CAST",
-	CHECK:       "CHECK",
-	COMMIT:      "COMMIT",
-	CONFLICT:    "CONFLICT",
-	CREATE:      "CREATE",
-	CYCLE:       "CYCLE",
-	DO:          "DO",
-	DEFAULT:     "DEFAULT",
-	DELETE:      "DELETE",
-	DESC:        "DESC",
-	DISTINCT:    "DISTINCT",
-	DROP:        "DROP",
-	EXISTS:      "EXISTS",
-	EXPLAIN:     "EXPLAIN",
-	GROUP:       "GROUP",
-	KEY:         "KEY",
-	FIELD:       "FIELD",
-	FOR:         "FOR",
-	FROM:        "FROM",
-	IF:          "IF",
-	IGNORE:      "IGNORE",
-	INCREMENT:   "INCREMENT",
-	INDEX:       "INDEX",
-	INSERT:      "INSERT",
-	INTO:        "INTO",
-	LIMIT:       "LIMIT",
-	MAXVALUE:    "MAXVALUE",
-	MINVALUE:    "MINVALUE",
-	NEXT:        "NEXT",
-	NO:          "NO",
-	NOT:         "NOT",
-	NOTHING:     "NOTHING",
-	OFFSET:      "OFFSET",
-	ON:          "ON",
-	ONLY:        "ONLY",
-	ORDER:       "ORDER",
-	PRECISION:   "PRECISION",
-	PRIMARY:     "PRIMARY",
-	READ:        "READ",
-	REINDEX:     "REINDEX",
-	RENAME:      "RENAME",
-	RETURNING:   "RETURNING",
-	REPLACE:     "REPLACE",
-	ROLLBACK:    "ROLLBACK",
-	START:       "START",
-	SELECT:      "SELECT",
-	SET:         "SET",
-	SEQUENCE:    "SEQUENCE",
-	TABLE:       "TABLE",
-	TO:          "TO",
-	TRANSACTION: "TRANSACTION",
-	UNION:       "UNION",
-	UNIQUE:      "UNIQUE",
-	UNSET:       "UNSET",
-	UPDATE:      "UPDATE",
-	VALUE:       "VALUE",
-	VALUES:      "VALUES",
-	WITH:        "WITH",
-	WHERE:       "WHERE",
-	WRITE:       "WRITE",
+	ADD_KEYWORD:  "ADD",
+	ALL:          "ALL",
+	ALTER:        "ALTER",
+	ANALYZE:      "ANALYZE",
+	AS:           "AS",
+	ASC:          "ASC",
+	BEGIN:        "BEGIN",
+	BY:           "BY",
+	CACHE:        "CACHE",
+	CAST:         "CAST",
+	CHECK:        "CHECK",
+	COMMIT:       "COMMIT",
+	CONFLICT:     "CONFLICT",
+	CREATE:       "CREATE",
+	CYCLE:        "CYCLE",
+	DO:           "DO",
+	DEFAULT:      "DEFAULT",
+	DELETE:       "DELETE",
+	DESC:         "DESC",
+	DISTINCT:     "DISTINCT",
+	DROP:         "DROP",
+	EXISTS:       "EXISTS",
+	EXPLAIN:      "EXPLAIN",
+	GROUP:        "GROUP",
+	KEY:          "KEY",
+	FIELD:        "FIELD",
+	FOR:          "FOR",
+	FROM:         "FROM",
+	IF:           "IF",
+	IGNORE:       "IGNORE",
+	INCREMENT:    "INCREMENT",
+	INDEX:        "INDEX",
+	INSERT:       "INSERT",
+	INTO:         "INTO",
+	LIMIT:        "LIMIT",
+	MATERIALIZED: "MATERIALIZED",
+	MAXVALUE:     "MAXVALUE",
+	MINVALUE:     "MINVALUE",
+	NEXT:         "NEXT",
+	NO:           "NO",
+	NOT:          "NOT",
+	NOTHING:      "NOTHING",
+	OFFSET:       "OFFSET",
+	ON:           "ON",
+	ONLY:         "ONLY",
+	ORDER:        "ORDER",
+	PRECISION:    "PRECISION",
+	PRIMARY:      "PRIMARY",
+	READ:         "READ",
+	REFRESH:      "REFRESH",
+	REINDEX:      "REINDEX",
+	RELEASE:      "RELEASE",
+	RENAME:       "RENAME",
+	RETURNING:    "RETURNING",
+	REPLACE:      "REPLACE",
+	ROLLBACK:     "ROLLBACK",
+	SAVEPOINT:    "SAVEPOINT",
+	START:        "START",
+	SELECT:       "SELECT",
+	SET:          "SET",
+	SEQUENCE:     "SEQUENCE",
+	TABLE:        "TABLE",
+	TO:           "TO",
+	TRANSACTION:  "TRANSACTION",
+	TRIGGER:      "TRIGGER",
+	UNION:        "UNION",
+	UNIQUE:       "UNIQUE",
+	UNSET:        "UNSET",
+	UPDATE:       "UPDATE",
+	VALUE:        "VALUE",
+	VALUES:       "VALUES",
+	VIEW:         "VIEW",
+	WITH:         "WITH",
+	WHERE:        "WHERE",
+	WRITE:        "WRITE",
 
 	TYPEARRAY:     "ARRAY",
 	TYPEBIGINT:    "BIGINT",
@@ -362,6 +377,12 @@ func lookup(ident string) Token {
 	return IDENT
 }
 
+// IsKeyword returns whether ident is a keyword, which must be quoted
+// to be used as an identifier.
+func IsKeyword(ident string) bool {
+	return lookup(ident) != IDENT
+}
+
 // Pos specifies the line and character position of a token.
 // The Char and Line are both zero-based indexes.
 type Pos struct {
@@ -1,7 +1,9 @@
 package stream
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/errors"
@@ -62,6 +64,38 @@ func (op *DocsEmitOperator) String() string {
 	return sb.String()
 }
 
+// A DocsEmitTreeOperator iterates over the documents stored in a tree.
+type DocsEmitTreeOperator struct {
+	baseOperator
+	Tree *tree.Tree
+}
+
+// DocsEmitTree creates an operator that iterates over the documents stored in the tree,
+// in the order of their keys.
+func DocsEmitTree(tr *tree.Tree) *DocsEmitTreeOperator {
+	return &DocsEmitTreeOperator{Tree: tr}
+}
+
+func (op *DocsEmitTreeOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	var newEnv environment.Environment
+	newEnv.SetOuter(in)
+
+	ctx := in.GetContext()
+
+	return op.Tree.IterateOnRange(nil, false, func(k tree.Key, v types.Value) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		newEnv.SetDocument(v.V().(types.Document))
+		return fn(&newEnv)
+	})
+}
+
+func (op *DocsEmitTreeOperator) String() string {
+	return "docs.EmitTree()"
+}
+
 // A DocsProjectOperator applies an expression on each value of the stream and returns a new value.
 type DocsProjectOperator struct {
 	baseOperator
@@ -328,7 +362,13 @@ func (op *DocsGroupAggregateOperator) Iterate(in *environment.Environment, f fun
 		groupExpr = fmt.Sprintf("%s", op.E)
 	}
 
+	ctx := in.GetContext()
+
 	err := op.Prev.Iterate(in, func(out *environment.Environment) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		if op.E == nil {
 			if ga == nil {
 				ga = newGroupAggregator(nil, groupExpr, op.Builders)
@@ -417,6 +457,257 @@ func (op *DocsGroupAggregateOperator) String() string {
 	return sb.String()
 }
 
+// estimated amount of memory used by each group of a hash aggregation
+// and by each of its aggregators, excluding the group key.
+const hashAggregateGroupOverhead = 64
+
+// A DocsHashAggregateOperator consumes the incoming stream and outputs one value per group,
+// ordered by group.
+// Unlike DocsGroupAggregateOperator, it doesn't require the stream to be sorted: groups are
+// kept in memory until the memory limit of the database is reached. Past that limit,
+// documents belonging to new groups are written to a transient tree, sorted by group,
+// and aggregated once the whole stream has been consumed.
+type DocsHashAggregateOperator struct {
+	baseOperator
+	Builders []expr.AggregatorBuilder
+	E        expr.Expr
+}
+
+// DocsHashAggregate consumes the incoming stream and outputs one value per group.
+func DocsHashAggregate(groupBy expr.Expr, builders ...expr.AggregatorBuilder) *DocsHashAggregateOperator {
+	return &DocsHashAggregateOperator{E: groupBy, Builders: builders}
+}
+
+func (op *DocsHashAggregateOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
+	limit := int64(database.DefaultHashAggregateMemoryLimit)
+	if db := in.GetDB(); db != nil {
+		limit = db.HashAggregateMemoryLimit
+	}
+
+	var groupExpr string
+	if op.E != nil {
+		groupExpr = op.E.String()
+	}
+
+	groups := make(map[string]*groupAggregator)
+	var used int64
+
+	var spill *tree.Tree
+	var cleanup func() error
+	var counter int64
+	ctx := in.GetContext()
+
+	err := op.Prev.Iterate(in, func(out *environment.Environment) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		group := types.NewNullValue()
+		if op.E != nil {
+			var err error
+			group, err = op.E.Eval(out)
+			if err != nil {
+				return err
+			}
+		}
+
+		k, err := tree.NewKey(group)
+		if err != nil {
+			return err
+		}
+
+		if ga, ok := groups[string(k)]; ok {
+			return ga.Aggregate(out)
+		}
+
+		if used < limit {
+			g, err := document.CloneValue(group)
+			if err != nil {
+				return err
+			}
+
+			ga := newGroupAggregator(g, groupExpr, op.Builders)
+			groups[string(k)] = ga
+			used += 2*int64(len(k)) + hashAggregateGroupOverhead*int64(1+len(op.Builders))
+
+			return ga.Aggregate(out)
+		}
+
+		// the group doesn't fit in memory, the document is written
+		// to the transient tree
+		if spill == nil {
+			spill, cleanup, err = newTransientTree(in)
+			if err != nil {
+				return err
+			}
+		}
+
+		doc, ok := out.GetDocument()
+		if !ok {
+			panic("missing document")
+		}
+
+		tableName, _ := out.Get(environment.TableKey)
+
+		key, _ := out.Get(environment.DocPKKey)
+
+		tk, err := tree.NewKey(group, tableName, key, types.NewIntegerValue(counter))
+		if err != nil {
+			return err
+		}
+
+		counter++
+
+		return spill.Put(tk, types.NewDocumentValue(doc))
+	})
+	if cleanup != nil {
+		defer cleanup()
+	}
+	if err != nil {
+		return err
+	}
+
+	// if s is empty, we create a default group so that aggregators will
+	// return their default initial value.
+	if len(groups) == 0 && spill == nil {
+		ga := newGroupAggregator(nil, "", op.Builders)
+
+		e, err := ga.Flush(in)
+		if err != nil {
+			return err
+		}
+		return f(e)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// flushInMemory outputs the groups kept in memory
+	// whose key is lower than k, or all of them if k is nil.
+	var i int
+	flushInMemory := func(k tree.Key) error {
+		for ; i < len(keys); i++ {
+			if k != nil && keys[i] >= string(k) {
+				return nil
+			}
+
+			e, err := groups[keys[i]].Flush(in)
+			if err != nil {
+				return err
+			}
+
+			err = f(e)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if spill != nil {
+		var newEnv environment.Environment
+		newEnv.SetOuter(in)
+
+		var ga *groupAggregator
+		var lastKey tree.Key
+
+		err = spill.IterateOnRange(nil, false, func(k tree.Key, v types.Value) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			kv, err := k.Decode()
+			if err != nil {
+				return err
+			}
+
+			gk, err := tree.NewKey(kv[0])
+			if err != nil {
+				return err
+			}
+
+			// if the document is from a different group, we flush the previous group
+			// and the groups kept in memory that come before the new one
+			if ga == nil || !bytes.Equal(gk, lastKey) {
+				if ga != nil {
+					e, err := ga.Flush(&newEnv)
+					if err != nil {
+						return err
+					}
+					err = f(e)
+					if err != nil {
+						return err
+					}
+				}
+
+				err = flushInMemory(gk)
+				if err != nil {
+					return err
+				}
+
+				g, err := document.CloneValue(kv[0])
+				if err != nil {
+					return err
+				}
+
+				ga = newGroupAggregator(g, groupExpr, op.Builders)
+				lastKey = gk
+			}
+
+			tableName := kv[1]
+			if tableName.Type() != types.NullValue {
+				newEnv.Set(environment.TableKey, tableName)
+			}
+
+			docKey := kv[2]
+			if docKey.Type() != types.NullValue {
+				newEnv.Set(environment.DocPKKey, docKey)
+			}
+
+			newEnv.SetDocument(v.V().(types.Document))
+
+			return ga.Aggregate(&newEnv)
+		})
+		if err != nil {
+			return err
+		}
+
+		e, err := ga.Flush(&newEnv)
+		if err != nil {
+			return err
+		}
+		err = f(e)
+		if err != nil {
+			return err
+		}
+	}
+
+	return flushInMemory(nil)
+}
+
+func (op *DocsHashAggregateOperator) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("docs.HashAggregate(")
+	if op.E != nil {
+		sb.WriteString(op.E.String())
+	} else {
+		sb.WriteString("NULL")
+	}
+
+	for _, agg := range op.Builders {
+		sb.WriteString(", ")
+		sb.WriteString(agg.(fmt.Stringer).String())
+	}
+
+	sb.WriteString(")")
+	return sb.String()
+}
+
 // a groupAggregator is an aggregator for a whole group of documents.
 // It applies all the aggregators for each documents and returns a new document with the
 // result of the aggregation.
@@ -492,17 +783,20 @@ func DocsTempTreeSortReverse(e expr.Expr) *DocsTempTreeSortOperator {
 }
 
 func (op *DocsTempTreeSortOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
-	db := in.GetDB()
-
-	tr, cleanup, err := database.NewTransientTree(db)
+	tr, cleanup, err := newTransientTree(in)
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
 	var counter int64
+	ctx := in.GetContext()
 
 	err = op.Prev.Iterate(in, func(out *environment.Environment) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		// evaluate the sort expression
 		v, err := op.Expr.Eval(out)
 		if err != nil {
@@ -535,6 +829,10 @@ func (op *DocsTempTreeSortOperator) Iterate(in *environment.Environment, fn func
 	newEnv.SetOuter(in)
 
 	return tr.IterateOnRange(nil, op.Desc, func(k tree.Key, v types.Value) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		kv, err := k.Decode()
 		if err != nil {
 			return err
@@ -2,9 +2,11 @@ package stream_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/environment"
 	"github.com/genjidb/genji/internal/expr"
 	"github.com/genjidb/genji/internal/expr/functions"
@@ -237,6 +239,87 @@ func TestAggregate(t *testing.T) {
 	})
 }
 
+func TestHashAggregate(t *testing.T) {
+	tests := []struct {
+		name     string
+		groupBy  expr.Expr
+		builders []expr.AggregatorBuilder
+		in       []types.Document
+		want     []types.Document
+	}{
+		{
+			"count/groupBy",
+			parser.MustParseExpr("(a + 1) % 3"),
+			[]expr.AggregatorBuilder{&functions.Count{Expr: parser.MustParseExpr("a")}, &functions.Max{Expr: parser.MustParseExpr("a")}},
+			generateSeqDocs(t, 10),
+			testutil.MakeDocuments(t,
+				`{"(a + 1) % 3": 0, "COUNT(a)": 3, "MAX(a)": 8}`,
+				`{"(a + 1) % 3": 1, "COUNT(a)": 4, "MAX(a)": 9}`,
+				`{"(a + 1) % 3": 2, "COUNT(a)": 3, "MAX(a)": 7}`,
+			),
+		},
+		{
+			"count/noInput",
+			parser.MustParseExpr("a % 2"),
+			[]expr.AggregatorBuilder{&functions.Count{Expr: parser.MustParseExpr("a")}},
+			nil,
+			testutil.MakeDocuments(t, `{"COUNT(a)": 0}`),
+		},
+		{
+			"no aggregator",
+			parser.MustParseExpr("a % 2"),
+			nil,
+			generateSeqDocs(t, 4),
+			testutil.MakeDocuments(t, `{"a % 2": 0}`, `{"a % 2": 1}`),
+		},
+	}
+
+	// the memory limit determines how many groups are kept in memory,
+	// the others are written to a transient tree
+	limits := []int64{database.DefaultHashAggregateMemoryLimit, 1, 0}
+
+	for _, test := range tests {
+		for _, limit := range limits {
+			t.Run(fmt.Sprintf("%s/limit=%d", test.name, limit), func(t *testing.T) {
+				db, tx, cleanup := testutil.NewTestTx(t)
+				defer cleanup()
+
+				db.HashAggregateMemoryLimit = limit
+
+				testutil.MustExec(t, db, tx, "CREATE TABLE test(a int)")
+
+				for _, doc := range test.in {
+					testutil.MustExec(t, db, tx, "INSERT INTO test VALUES ?", environment.Param{Value: doc})
+				}
+
+				var env environment.Environment
+				env.DB = db
+				env.Tx = tx
+				env.Catalog = db.Catalog
+
+				s := stream.New(stream.TableScan("test")).Pipe(stream.DocsHashAggregate(test.groupBy, test.builders...))
+
+				var got testutil.Docs
+				err := s.Iterate(&env, func(env *environment.Environment) error {
+					d, ok := env.GetDocument()
+					require.True(t, ok)
+					var fb document.FieldBuffer
+					fb.Copy(d)
+					got = append(got, &fb)
+					return nil
+				})
+				assert.NoError(t, err)
+				testutil.Docs(test.want).RequireEqual(t, got)
+			})
+		}
+	}
+
+	t.Run("String", func(t *testing.T) {
+		require.Equal(t, `docs.HashAggregate(a % 2, a(), b())`, stream.DocsHashAggregate(parser.MustParseExpr("a % 2"), makeAggregatorBuilders("a()", "b()")...).String())
+		require.Equal(t, `docs.HashAggregate(a % 2)`, stream.DocsHashAggregate(parser.MustParseExpr("a % 2")).String())
+	})
+}
+
 type fakeAggregator struct {
 	count int64
 	name  string
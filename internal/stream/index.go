@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/document"
 	errs "github.com/genjidb/genji/errors"
 	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/environment"
@@ -24,6 +26,9 @@ type IndexScanOperator struct {
 	Ranges Ranges
 	// Reverse indicates the direction used to traverse the index.
 	Reverse bool
+	// Covering indicates that the index contains every value needed by the rest of the stream.
+	// Documents are then built from the values stored in the index and the table is never read.
+	Covering bool
 }
 
 // IndexScan creates an iterator that iterates over each document of the given table.
@@ -39,7 +44,11 @@ func IndexScanReverse(name string, ranges ...Range) *IndexScanOperator {
 func (it *IndexScanOperator) String() string {
 	var s strings.Builder
 
-	s.WriteString("index.Scan")
+	s.WriteString("index.")
+	if it.Covering {
+		s.WriteString("Covering")
+	}
+	s.WriteString("Scan")
 	if it.Reverse {
 		s.WriteString("Reverse")
 	}
@@ -83,19 +92,85 @@ func (it *IndexScanOperator) Iterate(in *environment.Environment, fn func(out *e
 	newEnv.SetOuter(in)
 	newEnv.Set(environment.TableKey, types.NewTextValue(table.Info.Name()))
 
-	ptr := DocumentPointer{
-		Table: table,
+	// the expired documents must be read to be skipped
+	hide := hideExpired(in, table.Info)
+	now := time.Now()
+	ctx := in.GetContext()
+	isExpired := func(key tree.Key) (types.Document, bool, error) {
+		// stop scanning if the statement was canceled
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+
+		if !hide {
+			return nil, false, nil
+		}
+
+		d, err := table.GetDocument(key)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return d, table.Info.IsExpired(d, now), nil
 	}
-	newEnv.SetDocument(&ptr)
 
-	if len(it.Ranges) == 0 {
-		return index.Iterate(it.Reverse, func(key tree.Key) error {
-			ptr.key = key
-			ptr.Doc = nil
-			newEnv.Set(environment.DocPKKey, types.NewBlobValue(key))
+	var iterate func(rng *tree.Range) error
+	if it.Covering {
+		var fb document.FieldBuffer
+		newEnv.SetDocument(&fb)
+
+		iterate = func(rng *tree.Range) error {
+			return index.IterateValuesOnRange(rng, it.Reverse, func(vs []types.Value, key tree.Key) error {
+				_, expired, err := isExpired(key)
+				if err != nil || expired {
+					return err
+				}
+
+				fb.Reset()
+				for i, v := range vs {
+					// documents without the field are indexed with a NULL value,
+					// omitting it makes both cases evaluate the same way
+					if v.Type() == types.NullValue {
+						continue
+					}
 
-			return fn(&newEnv)
-		})
+					fb.Add(info.Paths[i][0].FieldName, v)
+				}
+				newEnv.Set(environment.DocPKKey, types.NewBlobValue(key))
+
+				return fn(&newEnv)
+			})
+		}
+	} else {
+		ptr := DocumentPointer{
+			Table: table,
+		}
+		newEnv.SetDocument(&ptr)
+
+		iterate = func(rng *tree.Range) error {
+			f := func(key tree.Key) error {
+				d, expired, err := isExpired(key)
+				if err != nil || expired {
+					return err
+				}
+
+				ptr.key = key
+				ptr.Doc = d
+				newEnv.Set(environment.DocPKKey, types.NewBlobValue(key))
+
+				return fn(&newEnv)
+			}
+
+			if rng == nil {
+				return index.Iterate(it.Reverse, f)
+			}
+
+			return index.IterateOnRange(rng, it.Reverse, f)
+		}
+	}
+
+	if len(it.Ranges) == 0 {
+		return iterate(nil)
 	}
 
 	ranges, err := it.Ranges.Eval(in)
@@ -109,13 +184,7 @@ func (it *IndexScanOperator) Iterate(in *environment.Environment, fn func(out *e
 			return err
 		}
 
-		err = index.IterateOnRange(r, it.Reverse, func(key tree.Key) error {
-			ptr.key = key
-			ptr.Doc = nil
-			newEnv.Set(environment.DocPKKey, types.NewBlobValue(key))
-
-			return fn(&newEnv)
-		})
+		err = iterate(r)
 		if errors.Is(err, ErrStreamClosed) {
 			err = nil
 		}
@@ -55,6 +55,51 @@ func TestIndexScan(t *testing.T) {
 	})
 }
 
+func TestIndexCoveringScan(t *testing.T) {
+	db, tx, cleanup := testutil.NewTestTx(t)
+	defer cleanup()
+
+	testutil.MustExec(t, db, tx, `
+		CREATE TABLE test(a int, b int, c int);
+		CREATE INDEX idx_test_a_b ON test(a, b);
+		INSERT INTO test (a, b, c) VALUES (1, 1, 1), (1, 2, 2), (2, 1, 3);
+		INSERT INTO test (a, c) VALUES (1, 4);
+	`)
+
+	op := stream.IndexScan("idx_test_a_b", stream.Range{Min: testutil.ExprList(t, `[1]`), Exact: true})
+	op.Covering = true
+
+	var env environment.Environment
+	env.Tx = tx
+	env.Catalog = db.Catalog
+	env.DB = db
+
+	var got testutil.Docs
+	err := op.Iterate(&env, func(env *environment.Environment) error {
+		d, ok := env.GetDocument()
+		require.True(t, ok)
+
+		var fb document.FieldBuffer
+		err := fb.Copy(d)
+		assert.NoError(t, err)
+
+		_, ok = env.Get(environment.DocPKKey)
+		require.True(t, ok)
+
+		got = append(got, &fb)
+		return nil
+	})
+	assert.NoError(t, err)
+	testutil.MakeDocuments(t, `{"a": 1}`, `{"a": 1, "b": 1}`, `{"a": 1, "b": 2}`).RequireEqual(t, got)
+
+	t.Run("String", func(t *testing.T) {
+		require.Equal(t, `index.CoveringScan("idx_test_a_b", [{"min": [1], "exact": true}])`, op.String())
+
+		op.Reverse = true
+		require.Equal(t, `index.CoveringScanReverse("idx_test_a_b", [{"min": [1], "exact": true}])`, op.String())
+	})
+}
+
 // func TestTransientIndexScan(t *testing.T) {
 // 	testIndexScan(t, func(db *database.Database, tx *database.Transaction, name string, indexOn string, reverse bool, ranges ...stream.IndexRange) stream.Operator {
 // 		var paths []document.Path
@@ -8,6 +8,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/environment"
 	"github.com/genjidb/genji/internal/expr"
 	"github.com/genjidb/genji/internal/sql/parser"
@@ -219,11 +220,67 @@ func TestTableInsert(t *testing.T) {
 		})
 	}
 
+	t.Run("Triggers", func(t *testing.T) {
+		db, tx, cleanup := testutil.NewTestTx(t)
+		defer cleanup()
+
+		testutil.MustExec(t, db, tx, "CREATE TABLE test (a INTEGER)")
+
+		var runner countingTriggerRunner
+		err := db.Catalog.CreateTrigger(tx, &database.TriggerInfo{
+			TriggerName: "test_trigger",
+			TableName:   "test",
+			Time:        database.TriggerAfter,
+			Event:       database.TriggerInsert,
+			Action:      &runner,
+		})
+		assert.NoError(t, err)
+
+		in := &environment.Environment{}
+		in.Tx = tx
+		in.Catalog = db.Catalog
+
+		s := stream.New(stream.DocsEmit(testutil.ParseExpr(t, `{"a": 10}`), testutil.ParseExpr(t, `{"a": 11}`))).
+			Pipe(stream.TableInsert("test")).
+			Pipe(stream.TableAfterTriggers("test", database.TriggerInsert))
+
+		err = s.Iterate(in, func(out *environment.Environment) error { return nil })
+		assert.NoError(t, err)
+
+		// the trigger is prepared once per execution and run for every document
+		require.Equal(t, 1, runner.prepared)
+		require.Equal(t, 2, runner.run)
+	})
+
 	t.Run("String", func(t *testing.T) {
 		require.Equal(t, "table.Insert(\"test\")", stream.TableInsert("test").String())
 	})
 }
 
+// countingTriggerRunner counts how many times a trigger is prepared and run.
+type countingTriggerRunner struct {
+	prepared, run int
+}
+
+func (r *countingTriggerRunner) Prepare(env *environment.Environment) (stream.PreparedTrigger, error) {
+	r.prepared++
+	return r, nil
+}
+
+func (r *countingTriggerRunner) Run(env *environment.Environment) error {
+	_, ok := env.Get(environment.NewDocKey)
+	if !ok {
+		return errors.New("missing NEW document")
+	}
+
+	r.run++
+	return nil
+}
+
+func (r *countingTriggerRunner) String() string {
+	return "BEGIN END"
+}
+
 func TestTableReplace(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -189,3 +189,73 @@ func (op *PathsRenameOperator) Iterate(in *environment.Environment, f func(out *
 func (op *PathsRenameOperator) String() string {
 	return fmt.Sprintf("paths.Rename(%s)", strings.Join(op.FieldNames, ", "))
 }
+
+// A PathsRenameFieldOperator renames a top-level field of the incoming documents.
+type PathsRenameFieldOperator struct {
+	baseOperator
+	Field   string
+	NewName string
+}
+
+// PathsRenameField renames a top-level field of the incoming documents, preserving the order of the fields.
+// Documents without that field are left untouched. If a document already contains a field
+// with the new name, it returns an error.
+func PathsRenameField(field, newName string) *PathsRenameFieldOperator {
+	return &PathsRenameFieldOperator{
+		Field:   field,
+		NewName: newName,
+	}
+}
+
+// Iterate implements the Operator interface.
+func (op *PathsRenameFieldOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
+	var fb document.FieldBuffer
+	var newEnv environment.Environment
+
+	return op.Prev.Iterate(in, func(out *environment.Environment) error {
+		fb.Reset()
+
+		d, ok := out.GetDocument()
+		if !ok {
+			return errors.New("missing document")
+		}
+
+		_, err := d.GetByField(op.Field)
+		if err != nil {
+			if !errors.Is(err, types.ErrFieldNotFound) {
+				return err
+			}
+
+			return f(out)
+		}
+
+		_, err = d.GetByField(op.NewName)
+		if err == nil {
+			return fmt.Errorf("cannot rename field %q: field %q already exists", op.Field, op.NewName)
+		}
+		if !errors.Is(err, types.ErrFieldNotFound) {
+			return err
+		}
+
+		err = d.Iterate(func(field string, value types.Value) error {
+			if field == op.Field {
+				field = op.NewName
+			}
+
+			fb.Add(field, value)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		newEnv.SetOuter(out)
+		newEnv.SetDocument(&fb)
+
+		return f(&newEnv)
+	})
+}
+
+func (op *PathsRenameFieldOperator) String() string {
+	return fmt.Sprintf("paths.RenameField(%s, %s)", op.Field, op.NewName)
+}
@@ -0,0 +1,166 @@
+package stream
+
+import (
+	"time"
+
+	"github.com/genjidb/genji/internal/environment"
+	"github.com/genjidb/genji/internal/kv"
+)
+
+// OperatorStats holds the metrics of an operator collected during profiling.
+type OperatorStats struct {
+	Operator string
+	// Number of values received from the previous operator.
+	RowsIn int64
+	// Number of values passed to the next operator.
+	RowsOut int64
+	// Time spent in the operator itself, excluding
+	// the time spent in the other operators.
+	Duration time.Duration
+	// Number of keys read from the store.
+	Reads int64
+	// Number of bytes written to transient stores.
+	TransientBytesWritten int64
+	// Metrics of the streams nested in the operator, if any.
+	Streams [][]*OperatorStats
+}
+
+// A Profiler measures the execution of every operator of a stream.
+// At any time, only one operator is considered running. Time and store metrics
+// are attributed to that operator until another one takes over, which happens
+// when an operator starts iterating or passes a value to the next one.
+type Profiler struct {
+	// Metrics of each operator of the stream, in order.
+	Stats []*OperatorStats
+
+	metrics *kv.Metrics
+	current *OperatorStats
+	last    time.Time
+	reads   int64
+	written int64
+}
+
+// Profile instruments every operator of the stream, including nested streams,
+// and returns the profiler collecting their metrics.
+// Metrics of the store are read from m, which can be nil.
+// The stream is modified in place and must not be used once profiling is done.
+func Profile(s *Stream, m *kv.Metrics) *Profiler {
+	p := Profiler{
+		metrics: m,
+	}
+
+	p.Stats = p.instrument(s)
+	return &p
+}
+
+func (p *Profiler) instrument(s *Stream) []*OperatorStats {
+	if s == nil || s.Op == nil {
+		return nil
+	}
+
+	var ops []*profiledOperator
+	for op := s.First(); op != nil; op = op.GetNext() {
+		stats := OperatorStats{
+			Operator: op.String(),
+		}
+
+		switch t := op.(type) {
+		case *ConcatOperator:
+			for _, st := range t.Streams {
+				stats.Streams = append(stats.Streams, p.instrument(st))
+			}
+		case *UnionOperator:
+			for _, st := range t.Streams {
+				stats.Streams = append(stats.Streams, p.instrument(st))
+			}
+		case *UnionKeysOperator:
+			for _, st := range t.Streams {
+				stats.Streams = append(stats.Streams, p.instrument(st))
+			}
+		case *OnConflictOperator:
+			if t.OnConflict != nil {
+				stats.Streams = append(stats.Streams, p.instrument(t.OnConflict))
+			}
+		}
+
+		ops = append(ops, &profiledOperator{
+			Operator: op,
+			profiler: p,
+			stats:    &stats,
+		})
+	}
+
+	// link the operators to the instrumented ones
+	all := make([]*OperatorStats, len(ops))
+	for i, op := range ops {
+		all[i] = op.stats
+
+		if i > 0 {
+			op.Operator.SetPrev(ops[i-1])
+		}
+		if i < len(ops)-1 {
+			op.Operator.SetNext(ops[i+1])
+			op.next = ops[i+1].stats
+		}
+	}
+
+	s.Op = ops[len(ops)-1]
+	return all
+}
+
+// switchTo attributes the metrics collected since the last switch to the current operator
+// and makes op the current one. It returns the operator that was running.
+func (p *Profiler) switchTo(op *OperatorStats) *OperatorStats {
+	now := time.Now()
+
+	var reads, written int64
+	if p.metrics != nil {
+		reads, written = p.metrics.Reads, p.metrics.TransientBytesWritten
+	}
+
+	prev := p.current
+	if prev != nil {
+		prev.Duration += now.Sub(p.last)
+		prev.Reads += reads - p.reads
+		prev.TransientBytesWritten += written - p.written
+	}
+
+	p.current = op
+	p.last = now
+	p.reads = reads
+	p.written = written
+
+	return prev
+}
+
+// profiledOperator wraps an operator to measure its execution.
+type profiledOperator struct {
+	Operator
+
+	profiler *Profiler
+	stats    *OperatorStats
+	next     *OperatorStats
+}
+
+func (op *profiledOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	p := op.profiler
+
+	// the caller is the operator that consumes the values of this one
+	caller := p.switchTo(op.stats)
+
+	err := op.Operator.Iterate(in, func(out *environment.Environment) error {
+		op.stats.RowsOut++
+		if op.next != nil {
+			op.next.RowsIn++
+		}
+
+		p.switchTo(caller)
+		err := fn(out)
+		p.switchTo(op.stats)
+
+		return err
+	})
+
+	p.switchTo(caller)
+	return err
+}
@@ -2,6 +2,7 @@ package stream
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/cockroachdb/errors"
@@ -87,6 +88,21 @@ func (s *Stream) String() string {
 	return sb.String()
 }
 
+// newTransientTree creates a temporary tree. If the current transaction
+// is being profiled, the bytes written to the tree are recorded.
+func newTransientTree(in *environment.Environment) (*tree.Tree, func() error, error) {
+	tr, cleanup, err := database.NewTransientTree(in.GetDB())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if tx := in.GetTx(); tx != nil {
+		tr.TransientStore.Metrics = tx.Session.Metrics
+	}
+
+	return tr, cleanup, nil
+}
+
 func InsertBefore(op, newOp Operator) Operator {
 	if op != nil {
 		prev := op.GetPrev()
@@ -154,9 +170,7 @@ func (it *UnionOperator) Iterate(in *environment.Environment, fn func(out *envir
 
 			if temp == nil {
 				// create a temporary database
-				db := in.GetDB()
-
-				tr, f, err := database.NewTransientTree(db)
+				tr, f, err := newTransientTree(in)
 				if err != nil {
 					return err
 				}
@@ -216,6 +230,104 @@ func (it *UnionOperator) String() string {
 	return s.String()
 }
 
+// UnionKeysOperator is an operator that merges the documents of multiple streams
+// reading from the same table, using their primary key to deduplicate them.
+type UnionKeysOperator struct {
+	baseOperator
+	TableName string
+	Streams   []*Stream
+}
+
+// UnionKeys returns a new UnionKeysOperator.
+func UnionKeys(tableName string, s ...*Stream) *UnionKeysOperator {
+	return &UnionKeysOperator{TableName: tableName, Streams: s}
+}
+
+// Iterate iterates over all the streams, collects the primary key of each document
+// then reads every distinct document from the table, in primary key order.
+func (it *UnionKeysOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) (err error) {
+	var temp *tree.Tree
+	var cleanup func() error
+
+	defer func() {
+		if cleanup != nil {
+			e := cleanup()
+			if err == nil {
+				err = e
+			}
+		}
+	}()
+
+	// insert the primary key of each document in a temporary tree
+	// to deduplicate them
+	for _, s := range it.Streams {
+		err := s.Iterate(in, func(out *environment.Environment) error {
+			k, ok := out.Get(environment.DocPKKey)
+			if !ok {
+				return errors.New("missing primary key")
+			}
+
+			if temp == nil {
+				tr, f, err := newTransientTree(in)
+				if err != nil {
+					return err
+				}
+				temp = tr
+				cleanup = f
+			}
+
+			return temp.Put(k.V().([]byte), nil)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if temp == nil {
+		// the union is empty
+		return nil
+	}
+
+	table, err := in.GetCatalog().GetTable(in.GetTx(), it.TableName)
+	if err != nil {
+		return err
+	}
+
+	var newEnv environment.Environment
+	newEnv.SetOuter(in)
+	newEnv.Set(environment.TableKey, types.NewTextValue(it.TableName))
+
+	err = temp.IterateOnRange(nil, false, func(key tree.Key, _ types.Value) error {
+		d, err := table.GetDocument(key)
+		if err != nil {
+			return err
+		}
+
+		newEnv.Set(environment.DocPKKey, types.NewBlobValue(key))
+		newEnv.SetDocument(d)
+
+		return fn(&newEnv)
+	})
+	if errors.Is(err, ErrStreamClosed) {
+		err = nil
+	}
+	return err
+}
+
+func (it *UnionKeysOperator) String() string {
+	var s strings.Builder
+
+	s.WriteString("union.Keys(")
+	s.WriteString(strconv.Quote(it.TableName))
+	for _, st := range it.Streams {
+		s.WriteString(", ")
+		s.WriteString(st.String())
+	}
+	s.WriteRune(')')
+
+	return s.String()
+}
+
 // A ConcatOperator concatenates two streams.
 type ConcatOperator struct {
 	baseOperator
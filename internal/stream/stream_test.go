@@ -137,6 +137,48 @@ func TestUnion(t *testing.T) {
 	})
 }
 
+func TestUnionKeys(t *testing.T) {
+	db, tx, cleanup := testutil.NewTestTx(t)
+	defer cleanup()
+
+	testutil.MustExec(t, db, tx, `
+		CREATE TABLE test(a int, b int);
+		CREATE INDEX test_a ON test(a);
+		CREATE INDEX test_b ON test(b);
+		INSERT INTO test (a, b) VALUES (1, 1), (2, 2), (3, 1), (4, 4);
+	`)
+
+	st := stream.New(stream.UnionKeys("test",
+		stream.New(stream.IndexScan("test_a", stream.Range{Min: testutil.ExprList(t, `[1]`), Max: testutil.ExprList(t, `[3]`)})),
+		stream.New(stream.IndexScan("test_b", stream.Range{Min: testutil.ExprList(t, `[1]`), Exact: true})),
+	))
+
+	var env environment.Environment
+	env.Tx = tx
+	env.DB = db
+	env.Catalog = db.Catalog
+
+	var got testutil.Docs
+	err := st.Iterate(&env, func(env *environment.Environment) error {
+		d, ok := env.GetDocument()
+		require.True(t, ok)
+
+		clone, err := document.CloneValue(types.NewDocumentValue(d))
+		if err != nil {
+			return err
+		}
+
+		got = append(got, clone.V().(types.Document))
+		return nil
+	})
+	assert.NoError(t, err)
+	testutil.MakeDocuments(t, `{"a": 1, "b": 1}`, `{"a": 2, "b": 2}`, `{"a": 3, "b": 1}`).RequireEqual(t, got)
+
+	t.Run("String", func(t *testing.T) {
+		require.Equal(t, `union.Keys("test", index.Scan("test_a", [{"min": [1], "max": [3]}]), index.Scan("test_b", [{"min": [1], "exact": true}]))`, st.String())
+	})
+}
+
 func TestConcatOperator(t *testing.T) {
 	in1 := testutil.ParseExprs(t, `{"a": 10}`, `{"a": 11}`)
 	in2 := testutil.ParseExprs(t, `{"a": 12}`, `{"a": 13}`)
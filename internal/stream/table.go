@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji/document"
 	"github.com/genjidb/genji/internal/database"
 	"github.com/genjidb/genji/internal/environment"
 	"github.com/genjidb/genji/internal/tree"
@@ -18,6 +20,9 @@ type TableScanOperator struct {
 	TableName string
 	Ranges    Ranges
 	Reverse   bool
+	// IncludeExpired returns the expired documents even if the database hides them.
+	// It must be set by operations that rewrite every document of the table.
+	IncludeExpired bool
 }
 
 // TableScan creates an iterator that iterates over each document of the given table that match the given ranges.
@@ -81,8 +86,21 @@ func (it *TableScanOperator) Iterate(in *environment.Environment, fn func(out *e
 		}
 	}
 
+	hide := !it.IncludeExpired && hideExpired(in, table.Info)
+	now := time.Now()
+	ctx := in.GetContext()
+
 	for _, rng := range ranges {
 		err = table.IterateOnRange(rng, it.Reverse, func(key tree.Key, d types.Document) error {
+			// stop scanning if the statement was canceled
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if hide && table.Info.IsExpired(d, now) {
+				return nil
+			}
+
 			newEnv.Set(environment.DocPKKey, types.NewBlobValue(key))
 			newEnv.SetDocument(d)
 
@@ -99,6 +117,44 @@ func (it *TableScanOperator) Iterate(in *environment.Environment, fn func(out *e
 	return nil
 }
 
+// A SystemTableScanOperator iterates over the documents of a system table.
+type SystemTableScanOperator struct {
+	baseOperator
+	TableName string
+}
+
+// SystemTableScan creates an iterator that iterates over each document of the given system table.
+func SystemTableScan(tableName string) *SystemTableScanOperator {
+	return &SystemTableScanOperator{TableName: tableName}
+}
+
+// Iterate over the documents of the system table, generated from the catalog.
+func (it *SystemTableScanOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	var newEnv environment.Environment
+	newEnv.SetOuter(in)
+	newEnv.Set(environment.TableKey, types.NewTextValue(it.TableName))
+
+	err := in.GetCatalog().IterateSystemTable(it.TableName, func(d types.Document) error {
+		newEnv.SetDocument(d)
+		return fn(&newEnv)
+	})
+	if errors.Is(err, ErrStreamClosed) {
+		err = nil
+	}
+	return err
+}
+
+func (it *SystemTableScanOperator) String() string {
+	return fmt.Sprintf("table.SystemScan(%s)", strconv.Quote(it.TableName))
+}
+
+// hideExpired returns whether scans must skip the expired documents of the table
+// that were not deleted yet.
+func hideExpired(env *environment.Environment, info *database.TableInfo) bool {
+	db := env.GetDB()
+	return db != nil && db.HideExpiredDocuments && info.TTLPath != nil
+}
+
 // TableValidateOperator validates and converts incoming documents against table and field constraints.
 type TableValidateOperator struct {
 	baseOperator
@@ -163,6 +219,7 @@ func (op *TableInsertOperator) Iterate(in *environment.Environment, f func(out *
 	newEnv.Set(environment.TableKey, types.NewTextValue(op.Name))
 
 	var table *database.Table
+	var triggers tableTriggers
 	return op.Prev.Iterate(in, func(out *environment.Environment) error {
 		newEnv.SetOuter(out)
 
@@ -177,6 +234,12 @@ func (op *TableInsertOperator) Iterate(in *environment.Environment, f func(out *
 			if err != nil {
 				return err
 			}
+			triggers.infos = out.GetCatalog().GetTableTriggers(op.Name)
+		}
+
+		err = triggers.fire(out, database.TriggerBefore, database.TriggerInsert, d, nil)
+		if err != nil {
+			return err
 		}
 
 		key, d, err := table.Insert(d)
@@ -184,6 +247,11 @@ func (op *TableInsertOperator) Iterate(in *environment.Environment, f func(out *
 			return err
 		}
 
+		if stats := out.GetStats(); stats != nil {
+			stats.RowsAffected++
+			stats.LastInsertKey = key
+		}
+
 		newEnv.Set(environment.DocPKKey, types.NewBlobValue(key))
 		newEnv.SetDocument(d)
 
@@ -199,6 +267,10 @@ func (op *TableInsertOperator) String() string {
 type TableReplaceOperator struct {
 	baseOperator
 	Name string
+
+	// If set to true, the UPDATE triggers of the table are not fired.
+	// This is used when rewriting documents after a schema change.
+	SkipTriggers bool
 }
 
 // TableReplace replaces documents in the table. Incoming documents must implement the document.Keyer interface.
@@ -209,6 +281,7 @@ func TableReplace(tableName string) *TableReplaceOperator {
 // Iterate implements the Operator interface.
 func (op *TableReplaceOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
 	var table *database.Table
+	var triggers tableTriggers
 
 	it := func(out *environment.Environment) error {
 		d, ok := out.GetDocument()
@@ -222,6 +295,9 @@ func (op *TableReplaceOperator) Iterate(in *environment.Environment, f func(out
 			if err != nil {
 				return err
 			}
+			if !op.SkipTriggers {
+				triggers.infos = out.GetCatalog().GetTableTriggers(op.Name)
+			}
 		}
 
 		key, ok := out.Get(environment.DocPKKey)
@@ -229,12 +305,30 @@ func (op *TableReplaceOperator) Iterate(in *environment.Environment, f func(out
 			return errors.New("missing key")
 		}
 
-		_, err := table.Replace(key.V().([]byte), d)
+		var old types.Document
+		if triggers.has(database.TriggerUpdate) {
+			var err error
+			old, err = getDocumentCopy(table, key.V().([]byte))
+			if err != nil {
+				return err
+			}
+		}
+
+		err := triggers.fire(out, database.TriggerBefore, database.TriggerUpdate, d, old)
 		if err != nil {
 			return err
 		}
 
-		return f(out)
+		_, err = table.Replace(key.V().([]byte), d)
+		if err != nil {
+			return err
+		}
+
+		if stats := out.GetStats(); stats != nil {
+			stats.RowsAffected++
+		}
+
+		return f(withWrittenOldDoc(out, old))
 	}
 
 	if op.Prev == nil {
@@ -262,6 +356,7 @@ func TableDelete(tableName string) *TableDeleteOperator {
 // Iterate implements the Operator interface.
 func (op *TableDeleteOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
 	var table *database.Table
+	var triggers tableTriggers
 
 	return op.Prev.Iterate(in, func(out *environment.Environment) error {
 		if table == nil {
@@ -270,6 +365,7 @@ func (op *TableDeleteOperator) Iterate(in *environment.Environment, f func(out *
 			if err != nil {
 				return err
 			}
+			triggers.infos = out.GetCatalog().GetTableTriggers(op.Name)
 		}
 
 		key, ok := out.Get(environment.DocPKKey)
@@ -277,15 +373,223 @@ func (op *TableDeleteOperator) Iterate(in *environment.Environment, f func(out *
 			return errors.New("missing key")
 		}
 
-		err := table.Delete(key.V().([]byte))
+		// the incoming document may only contain some of the fields
+		// of the stored document, fetch the whole document instead.
+		var old types.Document
+		if triggers.has(database.TriggerDelete) {
+			var err error
+			old, err = getDocumentCopy(table, key.V().([]byte))
+			if err != nil {
+				return err
+			}
+		}
+
+		err := triggers.fire(out, database.TriggerBefore, database.TriggerDelete, nil, old)
 		if err != nil {
 			return err
 		}
 
-		return f(out)
+		err = table.Delete(key.V().([]byte))
+		if err != nil {
+			return err
+		}
+
+		if stats := out.GetStats(); stats != nil {
+			stats.RowsAffected++
+		}
+
+		return f(withWrittenOldDoc(out, old))
 	})
 }
 
 func (op *TableDeleteOperator) String() string {
 	return fmt.Sprintf("table.Delete('%s')", op.Name)
 }
+
+// withWrittenOldDoc returns an environment holding the document replaced or deleted
+// by a write operator, for the AFTER triggers.
+func withWrittenOldDoc(out *environment.Environment, old types.Document) *environment.Environment {
+	if old == nil {
+		return out
+	}
+
+	var newEnv environment.Environment
+	newEnv.SetOuter(out)
+	newEnv.Set(environment.WrittenOldDocKey, types.NewDocumentValue(old))
+	return &newEnv
+}
+
+// A TableAfterTriggersOperator fires the AFTER triggers of a table
+// for each document written by the previous operators.
+type TableAfterTriggersOperator struct {
+	baseOperator
+	Name  string
+	Event database.TriggerEvent
+}
+
+// TableAfterTriggers fires the AFTER triggers of the table for the given event.
+// It must be placed after the operators maintaining the indexes of the table,
+// so that triggers only fire for documents that were entirely written.
+func TableAfterTriggers(tableName string, event database.TriggerEvent) *TableAfterTriggersOperator {
+	return &TableAfterTriggersOperator{Name: tableName, Event: event}
+}
+
+// Iterate implements the Operator interface.
+func (op *TableAfterTriggersOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
+	var triggers tableTriggers
+	var loaded bool
+
+	return op.Prev.Iterate(in, func(out *environment.Environment) error {
+		if !loaded {
+			triggers.infos = out.GetCatalog().GetTableTriggers(op.Name)
+			loaded = true
+		}
+
+		if !triggers.has(op.Event) {
+			return f(out)
+		}
+
+		var newDoc, oldDoc types.Document
+		if op.Event != database.TriggerDelete {
+			d, ok := out.GetDocument()
+			if !ok {
+				return errors.New("missing document")
+			}
+			newDoc = d
+		}
+		if op.Event != database.TriggerInsert {
+			v, ok := out.Get(environment.WrittenOldDocKey)
+			if !ok {
+				return errors.New("missing old document")
+			}
+			oldDoc = v.V().(types.Document)
+		}
+
+		err := triggers.fire(out, database.TriggerAfter, op.Event, newDoc, oldDoc)
+		if err != nil {
+			return err
+		}
+
+		return f(out)
+	})
+}
+
+func (op *TableAfterTriggersOperator) String() string {
+	return fmt.Sprintf("table.AfterTriggers(%q, %q)", op.Name, op.Event)
+}
+
+// MaxTriggerDepth is the maximum number of nested trigger calls.
+// It prevents triggers from firing each other indefinitely.
+const MaxTriggerDepth = 32
+
+// A TriggerRunner prepares the action of a trigger.
+type TriggerRunner interface {
+	// Prepare is called the first time the trigger fires during the execution of a statement.
+	// The returned PreparedTrigger is run every time the trigger fires during that execution.
+	Prepare(env *environment.Environment) (PreparedTrigger, error)
+}
+
+// A PreparedTrigger runs the prepared action of a trigger.
+// The environment holds the NEW and OLD documents, if any.
+type PreparedTrigger interface {
+	Run(env *environment.Environment) error
+}
+
+// tableTriggers holds the triggers of a table during the execution of a statement.
+// The action of each trigger is prepared once, the first time it fires.
+type tableTriggers struct {
+	infos    []*database.TriggerInfo
+	prepared map[string]PreparedTrigger
+}
+
+func (tt *tableTriggers) has(event database.TriggerEvent) bool {
+	for _, t := range tt.infos {
+		if t.Event == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fire runs the triggers matching the given time and event, in the transaction
+// of the environment.
+func (tt *tableTriggers) fire(in *environment.Environment, time database.TriggerTime, event database.TriggerEvent, newDoc, oldDoc types.Document) error {
+	if len(tt.infos) == 0 {
+		return nil
+	}
+
+	depth := int64(1)
+	if v, ok := in.Get(environment.TriggerDepthKey); ok {
+		depth = v.V().(int64) + 1
+	}
+
+	for _, t := range tt.infos {
+		if t.Time != time || t.Event != event {
+			continue
+		}
+
+		if depth > MaxTriggerDepth {
+			return errors.Errorf("trigger %s: maximum trigger depth of %d exceeded", t.TriggerName, MaxTriggerDepth)
+		}
+
+		// triggers don't have access to the environment of the statement
+		// that fired them, except for the transaction, its context and session.
+		env := environment.Environment{
+			DB:      in.GetDB(),
+			Tx:      in.GetTx(),
+			Catalog: in.GetCatalog(),
+			Ctx:     in.GetContext(),
+			Session: in.GetSession(),
+		}
+		env.Set(environment.TriggerDepthKey, types.NewIntegerValue(depth))
+
+		pt, ok := tt.prepared[t.TriggerName]
+		if !ok {
+			r, ok := t.Action.(TriggerRunner)
+			if !ok {
+				return errors.Errorf("trigger %s cannot be run", t.TriggerName)
+			}
+
+			var err error
+			pt, err = r.Prepare(&env)
+			if err != nil {
+				return err
+			}
+
+			if tt.prepared == nil {
+				tt.prepared = make(map[string]PreparedTrigger)
+			}
+			tt.prepared[t.TriggerName] = pt
+		}
+
+		if newDoc != nil {
+			env.Set(environment.NewDocKey, types.NewDocumentValue(newDoc))
+		}
+		if oldDoc != nil {
+			env.Set(environment.OldDocKey, types.NewDocumentValue(oldDoc))
+		}
+
+		err := pt.Run(&env)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getDocumentCopy(table *database.Table, key []byte) (types.Document, error) {
+	d, err := table.GetDocument(key)
+	if err != nil {
+		return nil, err
+	}
+
+	fb := document.NewFieldBuffer()
+	err = fb.Copy(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return fb, nil
+}
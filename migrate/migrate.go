@@ -0,0 +1,362 @@
+// Package migrate applies versioned SQL migrations to a Genji database.
+//
+// Migrations are read from files named <version>_<name>.up.sql, and optionally
+// <version>_<name>.down.sql to revert them, where version is a positive integer.
+// They are applied in the order of their versions, each one in its own transaction.
+// Applied migrations are recorded in the internal __genji_migrations table, along with
+// a checksum of their up file, to ensure the files still match the database history.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/document"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/types"
+)
+
+// TableName is the name of the table recording the applied migrations.
+// It is not listed with the tables of the database, but it is included in dumps
+// so that the history of the migrations is restored along with the tables.
+const TableName = database.MigrationsTableName
+
+const (
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+)
+
+// ErrHistoryMismatch is returned when the migrations applied to the database
+// don't match the migration files.
+var ErrHistoryMismatch = errors.New("applied migrations don't match the migration files")
+
+// A Migration is a versioned change of the database.
+type Migration struct {
+	Version int64
+	Name    string
+	// SQL statements applying the migration.
+	Up string
+	// SQL statements reverting the migration.
+	// If empty, the migration cannot be reverted.
+	Down string
+}
+
+// Checksum returns the checksum of the up statements of the migration.
+func (m *Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// LoadDir reads the migration files of the given directory.
+func LoadDir(dir string) ([]Migration, error) {
+	return Load(os.DirFS(dir))
+}
+
+// Load reads the migration files found at the root of fsys and returns them sorted by version.
+// Files that don't end with .up.sql or .down.sql are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		fileName := e.Name()
+
+		var base string
+		var up bool
+		switch {
+		case strings.HasSuffix(fileName, upSuffix):
+			base, up = strings.TrimSuffix(fileName, upSuffix), true
+		case strings.HasSuffix(fileName, downSuffix):
+			base = strings.TrimSuffix(fileName, downSuffix)
+		default:
+			continue
+		}
+
+		version, name, err := parseFileName(base)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid migration file %q", fileName)
+		}
+
+		b, err := fs.ReadFile(fsys, path.Clean(fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migrations %s and %d_%s have the same version", m, version, name)
+		}
+
+		if up {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %s has no up file", m)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// parseFileName returns the version and the name of a migration file name,
+// without its suffix.
+func parseFileName(base string) (int64, string, error) {
+	idx := strings.IndexByte(base, '_')
+	if idx <= 0 || idx == len(base)-1 {
+		return 0, "", errors.New("expected <version>_<name>")
+	}
+
+	version, err := strconv.ParseInt(base[:idx], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", errors.New("version must be a positive integer")
+	}
+
+	return version, base[idx+1:], nil
+}
+
+// Status describes a migration and whether it was applied to the database.
+type Status struct {
+	Migration Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// A Migrator applies migrations to a database.
+type Migrator struct {
+	db         *genji.DB
+	migrations []Migration
+}
+
+// New creates a migrator for the given migrations.
+// Migrations must have unique versions.
+func New(db *genji.DB, migrations []Migration) (*Migrator, error) {
+	ms := make([]Migration, len(migrations))
+	copy(ms, migrations)
+
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].Version < ms[j].Version
+	})
+
+	for i := range ms {
+		if ms[i].Version <= 0 {
+			return nil, fmt.Errorf("migration %s: version must be a positive integer", &ms[i])
+		}
+		if i > 0 && ms[i].Version == ms[i-1].Version {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", &ms[i-1], &ms[i])
+		}
+	}
+
+	return &Migrator{db: db, migrations: ms}, nil
+}
+
+// appliedMigration is a migration recorded in the migrations table.
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// applied returns the migrations recorded in the database, sorted by version,
+// and ensures they match the first migrations of the migrator.
+func (m *Migrator) applied(tx *genji.Tx) ([]appliedMigration, error) {
+	res, err := tx.Query("SELECT version, name, checksum, applied_at FROM " + TableName + " ORDER BY version")
+	// nothing was applied yet
+	if errs.IsNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var applied []appliedMigration
+	err = res.Iterate(func(d types.Document) error {
+		var am appliedMigration
+		err := document.Scan(d, &am.Version, &am.Name, &am.Checksum, &am.AppliedAt)
+		applied = append(applied, am)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, am := range applied {
+		if i >= len(m.migrations) || m.migrations[i].Version != am.Version {
+			return nil, errors.Wrapf(ErrHistoryMismatch, "applied migration %d_%s not found", am.Version, am.Name)
+		}
+
+		mig := &m.migrations[i]
+		if mig.Name != am.Name || mig.Checksum() != am.Checksum {
+			return nil, errors.Wrapf(ErrHistoryMismatch, "migration %s was modified after being applied", mig)
+		}
+	}
+
+	return applied, nil
+}
+
+// Status returns the migrations and whether they were applied.
+// It returns ErrHistoryMismatch if the migrations applied to the database
+// don't match the migrations of the migrator.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	tx, err := m.db.WithContext(ctx).Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	applied, err := m.applied(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		status[i].Migration = mig
+		if i < len(applied) {
+			status[i].Applied = true
+			status[i].AppliedAt = applied[i].AppliedAt
+		}
+	}
+
+	return status, nil
+}
+
+// Up applies at most n pending migrations, in order, and returns the applied migrations.
+// If n is zero or negative, every pending migration is applied.
+// Each migration is applied in its own transaction: if one fails, the migrations
+// applied before it are kept.
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+
+	for n <= 0 || len(done) < n {
+		mig, err := m.step(ctx, true)
+		if err != nil {
+			return done, err
+		}
+		if mig == nil {
+			break
+		}
+
+		done = append(done, *mig)
+	}
+
+	return done, nil
+}
+
+// Down reverts at most n applied migrations, most recent first, and returns the reverted migrations.
+// If n is zero or negative, every applied migration is reverted.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+
+	for n <= 0 || len(done) < n {
+		mig, err := m.step(ctx, false)
+		if err != nil {
+			return done, err
+		}
+		if mig == nil {
+			break
+		}
+
+		done = append(done, *mig)
+	}
+
+	return done, nil
+}
+
+// step applies the next pending migration if up is true, otherwise it reverts the last
+// applied migration. It returns nil if there is nothing left to do.
+func (m *Migrator) step(ctx context.Context, up bool) (*Migration, error) {
+	tx, err := m.db.WithContext(ctx).Begin(true)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	applied, err := m.applied(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mig *Migration
+	if up {
+		if len(applied) == len(m.migrations) {
+			return nil, nil
+		}
+		mig = &m.migrations[len(applied)]
+
+		err = tx.Exec("CREATE TABLE IF NOT EXISTS " + TableName + " (version INTEGER PRIMARY KEY, name TEXT NOT NULL, checksum TEXT NOT NULL, applied_at TEXT NOT NULL)")
+		if err != nil {
+			return nil, err
+		}
+
+		err = tx.Exec(mig.Up)
+		if err != nil {
+			return nil, errors.Wrapf(err, "migration %s", mig)
+		}
+
+		err = tx.Exec("INSERT INTO "+TableName+" (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+			mig.Version, mig.Name, mig.Checksum(), time.Now().UTC())
+	} else {
+		if len(applied) == 0 {
+			return nil, nil
+		}
+		mig = &m.migrations[len(applied)-1]
+
+		if mig.Down == "" {
+			return nil, fmt.Errorf("migration %s cannot be reverted: no down file", mig)
+		}
+
+		err = tx.Exec(mig.Down)
+		if err != nil {
+			return nil, errors.Wrapf(err, "migration %s", mig)
+		}
+
+		err = tx.Exec("DELETE FROM "+TableName+" WHERE version = ?", mig.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mig, tx.Commit()
+}
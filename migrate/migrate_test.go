@@ -0,0 +1,246 @@
+package migrate_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cockroachdb/errors"
+	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/genjidb/genji/migrate"
+	"github.com/genjidb/genji/types"
+	"github.com/stretchr/testify/require"
+)
+
+var files = fstest.MapFS{
+	"1_create_foo.up.sql":   {Data: []byte("CREATE TABLE foo (a INT PRIMARY KEY);")},
+	"1_create_foo.down.sql": {Data: []byte("DROP TABLE foo;")},
+	"2_add_index.up.sql":    {Data: []byte("CREATE INDEX foo_a ON foo (a); INSERT INTO foo (a) VALUES (1);")},
+	"2_add_index.down.sql":  {Data: []byte("DROP INDEX foo_a; DELETE FROM foo;")},
+	"10_create_bar.up.sql":  {Data: []byte("CREATE TABLE bar;")},
+	"README.md":             {Data: []byte("ignored")},
+}
+
+func newMigrator(t *testing.T, db *genji.DB, fsys fstest.MapFS) *migrate.Migrator {
+	t.Helper()
+
+	migrations, err := migrate.Load(fsys)
+	assert.NoError(t, err)
+
+	m, err := migrate.New(db, migrations)
+	assert.NoError(t, err)
+
+	return m
+}
+
+func listTables(t *testing.T, db *genji.DB) []string {
+	t.Helper()
+
+	res, err := db.Query("SELECT name FROM __genji_tables")
+	assert.NoError(t, err)
+	defer res.Close()
+
+	names := []string{}
+	err = res.Iterate(func(d types.Document) error {
+		var name string
+		err := document.Scan(d, &name)
+		names = append(names, name)
+		return err
+	})
+	assert.NoError(t, err)
+
+	return names
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := migrate.Load(files)
+	assert.NoError(t, err)
+
+	require.Len(t, migrations, 3)
+	require.Equal(t, migrate.Migration{Version: 1, Name: "create_foo", Up: "CREATE TABLE foo (a INT PRIMARY KEY);", Down: "DROP TABLE foo;"}, migrations[0])
+	require.Equal(t, int64(2), migrations[1].Version)
+	require.Equal(t, int64(10), migrations[2].Version)
+	require.Empty(t, migrations[2].Down)
+
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"no version", fstest.MapFS{"create_foo.up.sql": {}}},
+		{"no name", fstest.MapFS{"1_.up.sql": {}}},
+		{"negative version", fstest.MapFS{"-1_foo.up.sql": {}}},
+		{"same version", fstest.MapFS{"1_foo.up.sql": {Data: []byte("a")}, "1_bar.up.sql": {Data: []byte("b")}}},
+		{"no up file", fstest.MapFS{"1_foo.down.sql": {Data: []byte("a")}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := migrate.Load(test.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	m := newMigrator(t, db, files)
+
+	status, err := m.Status(ctx)
+	assert.NoError(t, err)
+	require.Len(t, status, 3)
+	for _, s := range status {
+		require.False(t, s.Applied)
+	}
+
+	// up with a limit
+	done, err := m.Up(ctx, 1)
+	assert.NoError(t, err)
+	require.Len(t, done, 1)
+	require.Equal(t, int64(1), done[0].Version)
+	require.Equal(t, []string{"foo"}, listTables(t, db))
+
+	// up applies every pending migration
+	done, err = m.Up(ctx, 0)
+	assert.NoError(t, err)
+	require.Len(t, done, 2)
+	require.Equal(t, []string{"bar", "foo"}, listTables(t, db))
+
+	status, err = m.Status(ctx)
+	assert.NoError(t, err)
+	for _, s := range status {
+		require.True(t, s.Applied)
+		require.False(t, s.AppliedAt.IsZero())
+	}
+
+	// nothing left to do
+	done, err = m.Up(ctx, 0)
+	assert.NoError(t, err)
+	require.Empty(t, done)
+
+	// the last migration has no down file
+	_, err = m.Down(ctx, 1)
+	assert.Error(t, err)
+}
+
+func TestMigratorDown(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	m := newMigrator(t, db, fstest.MapFS{
+		"1_create_foo.up.sql":   files["1_create_foo.up.sql"],
+		"1_create_foo.down.sql": files["1_create_foo.down.sql"],
+		"2_add_index.up.sql":    files["2_add_index.up.sql"],
+		"2_add_index.down.sql":  files["2_add_index.down.sql"],
+	})
+
+	_, err = m.Up(ctx, 0)
+	assert.NoError(t, err)
+
+	// down reverts the most recent migrations first
+	done, err := m.Down(ctx, 1)
+	assert.NoError(t, err)
+	require.Len(t, done, 1)
+	require.Equal(t, int64(2), done[0].Version)
+
+	done, err = m.Down(ctx, 0)
+	assert.NoError(t, err)
+	require.Len(t, done, 1)
+	require.Equal(t, int64(1), done[0].Version)
+	require.Equal(t, []string{}, listTables(t, db))
+
+	status, err := m.Status(ctx)
+	assert.NoError(t, err)
+	for _, s := range status {
+		require.False(t, s.Applied)
+	}
+
+	// migrations can be applied again
+	done, err = m.Up(ctx, 0)
+	assert.NoError(t, err)
+	require.Len(t, done, 2)
+}
+
+func TestMigratorFailure(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	m := newMigrator(t, db, fstest.MapFS{
+		"1_foo.up.sql": {Data: []byte("CREATE TABLE foo;")},
+		"2_bar.up.sql": {Data: []byte("CREATE TABLE bar; CREATE TABLE foo;")},
+	})
+
+	// the failing migration is rolled back, the previous ones are kept
+	done, err := m.Up(ctx, 0)
+	assert.Error(t, err)
+	require.Len(t, done, 1)
+	require.Equal(t, []string{"foo"}, listTables(t, db))
+
+	status, err := m.Status(ctx)
+	assert.NoError(t, err)
+	require.True(t, status[0].Applied)
+	require.False(t, status[1].Applied)
+}
+
+func TestMigratorHistoryMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"modified", fstest.MapFS{
+			"1_foo.up.sql": {Data: []byte("CREATE TABLE foo (a INT);")},
+			"2_bar.up.sql": {Data: []byte("CREATE TABLE bar;")},
+		}},
+		{"renamed", fstest.MapFS{
+			"1_baz.up.sql": {Data: []byte("CREATE TABLE foo;")},
+			"2_bar.up.sql": {Data: []byte("CREATE TABLE bar;")},
+		}},
+		{"missing", fstest.MapFS{
+			"2_bar.up.sql": {Data: []byte("CREATE TABLE bar;")},
+		}},
+		{"inserted before", fstest.MapFS{
+			"1_baz.up.sql": {Data: []byte("CREATE TABLE baz;")},
+			"2_foo.up.sql": {Data: []byte("CREATE TABLE foo;")},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, err := genji.Open(":memory:")
+			assert.NoError(t, err)
+			defer db.Close()
+
+			m := newMigrator(t, db, fstest.MapFS{
+				"1_foo.up.sql": {Data: []byte("CREATE TABLE foo;")},
+			})
+			_, err = m.Up(ctx, 0)
+			assert.NoError(t, err)
+
+			m = newMigrator(t, db, test.fsys)
+
+			_, err = m.Status(ctx)
+			require.True(t, errors.Is(err, migrate.ErrHistoryMismatch))
+			_, err = m.Up(ctx, 0)
+			require.True(t, errors.Is(err, migrate.ErrHistoryMismatch))
+			_, err = m.Down(ctx, 0)
+			require.True(t, errors.Is(err, migrate.ErrHistoryMismatch))
+
+			// nothing was run
+			require.Equal(t, []string{"foo"}, listTables(t, db))
+		})
+	}
+}
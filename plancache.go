@@ -0,0 +1,208 @@
+package genji
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/genjidb/genji/internal/query"
+	"github.com/genjidb/genji/internal/query/statement"
+	"github.com/genjidb/genji/internal/sql/parser"
+)
+
+// default number of queries kept by the plan cache.
+const defaultPlanCacheSize = 256
+
+// PlanCacheStats describes the usage of the plan cache of a database.
+type PlanCacheStats struct {
+	// Number of times a query was run with a plan
+	// prepared by a previous run.
+	Hits int64
+	// Number of times a query was parsed and planned.
+	Misses int64
+	// Number of queries in the cache.
+	Len int
+}
+
+// PlanCacheStats returns the statistics of the plan cache.
+func (db *DB) PlanCacheStats() PlanCacheStats {
+	return db.plans.stats()
+}
+
+// planCache keeps the most recently used queries along with their plan,
+// so that queries that are run often are only parsed and planned once.
+type planCache struct {
+	// maximum number of queries, the cache is disabled if negative.
+	size int
+
+	mu      sync.Mutex
+	queries map[string]*list.Element
+	lru     list.List
+
+	hits, misses int64
+}
+
+func newPlanCache(size int) *planCache {
+	if size == 0 {
+		size = defaultPlanCacheSize
+	}
+
+	return &planCache{
+		size:    size,
+		queries: make(map[string]*list.Element),
+	}
+}
+
+func (c *planCache) get(q string) *preparedQuery {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.queries[q]
+	if !ok {
+		return nil
+	}
+
+	c.lru.MoveToFront(e)
+	return e.Value.(*preparedQuery)
+}
+
+func (c *planCache) add(p *preparedQuery) {
+	if c.size < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.queries[p.sql]; ok {
+		e.Value = p
+		c.lru.MoveToFront(e)
+		return
+	}
+
+	c.queries[p.sql] = c.lru.PushFront(p)
+
+	if c.lru.Len() > c.size {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.queries, e.Value.(*preparedQuery).sql)
+	}
+}
+
+func (c *planCache) stats() PlanCacheStats {
+	c.mu.Lock()
+	l := c.lru.Len()
+	c.mu.Unlock()
+
+	return PlanCacheStats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+		Len:    l,
+	}
+}
+
+// preparedQuery is a query along with its plan. The plan is only valid
+// for the version of the catalog it was prepared with: it is prepared again
+// when the query is run after the catalog was modified.
+type preparedQuery struct {
+	sql string
+
+	mu      sync.Mutex
+	pq      query.Query
+	version int64
+	// true until the plan is run for the first time.
+	fresh bool
+}
+
+// prepare returns the prepared query of q, from the plan cache if possible.
+func (db *DB) prepare(q string, tx *Tx) (*preparedQuery, error) {
+	if p := db.plans.get(q); p != nil {
+		_, err := p.plan(db, tx, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return p, nil
+	}
+
+	p := preparedQuery{sql: q}
+
+	cacheable, err := p.prepare(db, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheable {
+		db.plans.add(&p)
+	}
+
+	return &p, nil
+}
+
+// plan returns the plan of the query, prepared again if the catalog was modified since.
+// If run is true, the plan is about to be run and counts as a cache hit.
+func (p *preparedQuery) plan(db *DB, tx *Tx, run bool) (query.Query, error) {
+	p.mu.Lock()
+	if p.version != db.DB.Catalog.Version() {
+		p.mu.Unlock()
+
+		_, err := p.prepare(db, tx)
+		if err != nil {
+			return query.Query{}, err
+		}
+
+		p.mu.Lock()
+	}
+	defer p.mu.Unlock()
+
+	if run {
+		if p.fresh {
+			p.fresh = false
+		} else {
+			atomic.AddInt64(&db.plans.hits, 1)
+		}
+	}
+
+	return p.pq, nil
+}
+
+// prepare parses and plans the query. It reports whether the plan can be cached,
+// which is the case if every statement of the query was prepared: the other statements
+// are planned when they are run and must not be shared.
+func (p *preparedQuery) prepare(db *DB, tx *Tx) (cacheable bool, err error) {
+	atomic.AddInt64(&db.plans.misses, 1)
+
+	version := db.DB.Catalog.Version()
+
+	pq, err := parser.ParseQuery(p.sql)
+	if err != nil {
+		return false, err
+	}
+
+	cacheable = true
+	for _, stmt := range pq.Statements {
+		if _, ok := stmt.(statement.Preparer); !ok {
+			cacheable = false
+			break
+		}
+	}
+
+	err = pq.Prepare(newQueryContext(db, tx, nil))
+	if err != nil {
+		return false, err
+	}
+
+	// if the catalog was modified while preparing,
+	// the plan must be prepared again on the next run.
+	if db.DB.Catalog.Version() != version {
+		version = -1
+	}
+
+	p.mu.Lock()
+	p.pq = pq
+	p.version = version
+	p.fresh = true
+	p.mu.Unlock()
+
+	return cacheable, nil
+}
@@ -0,0 +1,117 @@
+package genji_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/internal/testutil"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanCache(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE test(a INT, b INT); INSERT INTO test (a, b) VALUES (1, 10), (2, 20)")
+	assert.NoError(t, err)
+
+	base := db.PlanCacheStats()
+
+	stmt, err := db.Prepare("SELECT b FROM test WHERE a = ?")
+	assert.NoError(t, err)
+
+	query := func(stmt *genji.Statement) {
+		t.Helper()
+
+		d, err := stmt.QueryDocument(2)
+		assert.NoError(t, err)
+		testutil.RequireDocJSONEq(t, d, `{"b": 20}`)
+	}
+
+	requireStats := func(hits, misses int64) {
+		t.Helper()
+
+		stats := db.PlanCacheStats()
+		require.Equal(t, hits, stats.Hits-base.Hits, "hits")
+		require.Equal(t, misses, stats.Misses-base.Misses, "misses")
+	}
+
+	query(stmt)
+	requireStats(0, 1)
+	query(stmt)
+	requireStats(1, 1)
+
+	// queries with the same text share the plan
+	d, err := db.QueryDocument("SELECT b FROM test WHERE a = ?", 2)
+	assert.NoError(t, err)
+	testutil.RequireDocJSONEq(t, d, `{"b": 20}`)
+	requireStats(2, 1)
+
+	// modifying the schema invalidates the plan
+	err = db.Exec("CREATE INDEX test_a ON test(a)")
+	assert.NoError(t, err)
+	base = db.PlanCacheStats()
+	query(stmt)
+	requireStats(0, 1)
+
+	// the plan using the index is not used once it is dropped
+	err = db.Exec("DROP INDEX test_a")
+	assert.NoError(t, err)
+	base = db.PlanCacheStats()
+	query(stmt)
+	requireStats(0, 1)
+
+	// modifications that are rolled back invalidate the plan as well
+	errRollback := errors.New("rollback")
+	err = db.Update(func(tx *genji.Tx) error {
+		err := tx.Exec("CREATE INDEX test_a ON test(a)")
+		assert.NoError(t, err)
+
+		// plans prepared within the transaction can use the new index
+		stmt, err := tx.Prepare("SELECT b FROM test WHERE a = ?")
+		assert.NoError(t, err)
+		query(stmt)
+
+		return errRollback
+	})
+	require.Equal(t, errRollback, err)
+	base = db.PlanCacheStats()
+	query(stmt)
+	requireStats(0, 1)
+	query(stmt)
+	requireStats(1, 1)
+
+	err = db.Exec("DROP TABLE test")
+	assert.NoError(t, err)
+	_, err = stmt.Query(2)
+	assert.Error(t, err)
+}
+
+func TestPlanCacheSize(t *testing.T) {
+	db, err := genji.OpenWith(":memory:", &genji.Options{PlanCacheSize: 2})
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE test(a INT)")
+	assert.NoError(t, err)
+
+	for _, q := range []string{"SELECT a FROM test", "SELECT * FROM test", "SELECT a FROM test", "SELECT a + 1 FROM test"} {
+		err = db.Exec(q)
+		assert.NoError(t, err)
+	}
+	require.Equal(t, 2, db.PlanCacheStats().Len)
+
+	// the cache can be disabled
+	db, err = genji.OpenWith(":memory:", &genji.Options{PlanCacheSize: -1})
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE test(a INT)")
+	assert.NoError(t, err)
+	err = db.Exec("SELECT a FROM test")
+	assert.NoError(t, err)
+	require.Equal(t, 0, db.PlanCacheStats().Len)
+}
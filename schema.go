@@ -0,0 +1,451 @@
+package genji
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/database"
+	"github.com/genjidb/genji/internal/query/statement"
+	"github.com/genjidb/genji/internal/sql/parser"
+	"github.com/genjidb/genji/internal/sql/scanner"
+	"github.com/genjidb/genji/internal/stringutil"
+	"github.com/genjidb/genji/types"
+)
+
+// CreateTableFromStruct creates a table whose schema is derived from the fields of s,
+// which must be a struct or a pointer to a struct.
+// Fields are named and selected like with document.NewFromStruct, and their constraints
+// are described by the options of the "genji" tag (see document.StructTag).
+// The type of each field is inferred from its Go type:
+//
+//	bool                              BOOL
+//	ints, uints, time.Duration        INTEGER
+//	float32, float64                  DOUBLE
+//	string, time.Time                 TEXT
+//	[]byte                            BLOB
+//	slices and arrays                 ARRAY
+//	maps                              DOCUMENT
+//
+// Fields of nested structs are declared using dotted paths.
+// Interface fields are untyped.
+func CreateTableFromStruct(db *DB, tableName string, s interface{}) error {
+	q, err := createTableQueryFromStruct(tableName, s)
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(q)
+}
+
+// SchemaDrift describes a difference between the schema derived from a struct
+// and the schema of a table.
+type SchemaDrift struct {
+	// Path of the field, if the difference concerns a single field.
+	Path   document.Path
+	Reason string
+}
+
+func (d SchemaDrift) String() string {
+	if d.Path == nil {
+		return d.Reason
+	}
+
+	return d.Path.String() + ": " + d.Reason
+}
+
+// StructDrift compares the schema derived from the fields of s, as described by CreateTableFromStruct,
+// with the schema of the given table, and returns the differences.
+// Fields inferred from nested paths and generated fields of the table are ignored.
+func StructDrift(db *DB, tableName string, s interface{}) ([]SchemaDrift, error) {
+	want, paths, err := tableInfoFromStruct(tableName, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var have *database.TableInfo
+	err = db.View(func(tx *Tx) error {
+		have, err = db.DB.Catalog.GetTableInfo(tableName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []SchemaDrift
+
+	for _, fc := range want.FieldConstraints {
+		if fc.IsInferred {
+			continue
+		}
+
+		other := have.GetFieldConstraintForPath(fc.Path)
+		if other == nil || other.IsInferred {
+			drift = append(drift, SchemaDrift{Path: fc.Path, Reason: "not declared by the table"})
+			continue
+		}
+
+		drift = append(drift, fieldDrift(fc, other)...)
+	}
+
+	for _, fc := range have.FieldConstraints {
+		if fc.IsInferred || fc.IsGenerated() {
+			continue
+		}
+
+		var found bool
+		for _, p := range paths {
+			if p.IsEqual(fc.Path) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			drift = append(drift, SchemaDrift{Path: fc.Path, Reason: "not declared by the struct"})
+		}
+	}
+
+	var wantPK, havePK document.Paths
+	if pk := want.GetPrimaryKey(); pk != nil {
+		wantPK = pk.Paths
+	}
+	if pk := have.GetPrimaryKey(); pk != nil {
+		havePK = pk.Paths
+	}
+	if !wantPK.IsEqual(havePK) {
+		drift = append(drift, SchemaDrift{
+			Reason: fmt.Sprintf("primary key is %s in the struct and %s in the table", pathsOrNone(wantPK), pathsOrNone(havePK)),
+		})
+	}
+
+	drift = append(drift, uniqueDrift(want, have, "table")...)
+	drift = append(drift, uniqueDrift(have, want, "struct")...)
+
+	return drift, nil
+}
+
+// fieldDrift compares the constraints declared by the struct with those of the table
+// for the same path.
+func fieldDrift(want, have *database.FieldConstraint) []SchemaDrift {
+	var drift []SchemaDrift
+
+	if want.Type != have.Type {
+		drift = append(drift, SchemaDrift{
+			Path:   want.Path,
+			Reason: fmt.Sprintf("type is %s in the struct and %s in the table", typeName(want.Type), typeName(have.Type)),
+		})
+	}
+
+	if want.IsNotNull != have.IsNotNull {
+		reason := "NOT NULL in the struct only"
+		if have.IsNotNull {
+			reason = "NOT NULL in the table only"
+		}
+		drift = append(drift, SchemaDrift{Path: want.Path, Reason: reason})
+	}
+
+	if defaultString(want) != defaultString(have) {
+		drift = append(drift, SchemaDrift{
+			Path:   want.Path,
+			Reason: fmt.Sprintf("default is %s in the struct and %s in the table", defaultString(want), defaultString(have)),
+		})
+	}
+
+	return drift
+}
+
+// uniqueDrift reports the unique constraints of a that are missing from b.
+func uniqueDrift(a, b *database.TableInfo, bName string) []SchemaDrift {
+	var drift []SchemaDrift
+
+	for _, tc := range a.TableConstraints {
+		if !tc.Unique {
+			continue
+		}
+
+		var found bool
+		for _, other := range b.TableConstraints {
+			if other.Unique && other.Paths.IsEqual(tc.Paths) {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		d := SchemaDrift{Reason: "unique constraint not declared by the " + bName}
+		if len(tc.Paths) == 1 {
+			d.Path = tc.Paths[0]
+		} else {
+			d.Reason = fmt.Sprintf("unique constraint on (%s) not declared by the %s", tc.Paths, bName)
+		}
+		drift = append(drift, d)
+	}
+
+	return drift
+}
+
+func typeName(t types.ValueType) string {
+	if t.IsAny() {
+		return "any"
+	}
+
+	return t.String()
+}
+
+func defaultString(fc *database.FieldConstraint) string {
+	if !fc.HasDefaultValue() {
+		return "none"
+	}
+
+	return fc.DefaultValue.String()
+}
+
+func pathsOrNone(p document.Paths) string {
+	if len(p) == 0 {
+		return "none"
+	}
+
+	return "(" + p.String() + ")"
+}
+
+// tableInfoFromStruct returns the table info derived from the struct and the paths
+// of all of its fields, including the untyped ones.
+func tableInfoFromStruct(tableName string, s interface{}) (*database.TableInfo, []document.Path, error) {
+	var b schemaBuilder
+	err := b.build(tableName, s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q, err := parser.ParseQuery(b.query())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stmt := q.Statements[0].(*statement.CreateTableStmt)
+	return &stmt.Info, b.paths, nil
+}
+
+func createTableQueryFromStruct(tableName string, s interface{}) (string, error) {
+	var b schemaBuilder
+	err := b.build(tableName, s)
+	if err != nil {
+		return "", err
+	}
+
+	return b.query(), nil
+}
+
+// schemaBuilder generates a CREATE TABLE statement from a struct.
+type schemaBuilder struct {
+	tableName   string
+	visiting    map[reflect.Type]bool
+	paths       []document.Path
+	fields      []string
+	primaryKey  document.Paths
+	uniquePaths document.Paths
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+func (b *schemaBuilder) build(tableName string, s interface{}) error {
+	tp := reflect.TypeOf(s)
+	if tp != nil && tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+	if tp == nil || tp.Kind() != reflect.Struct || tp == timeType {
+		return fmt.Errorf("expected struct, got %T", s)
+	}
+
+	b.tableName = tableName
+	b.visiting = make(map[reflect.Type]bool)
+	return b.addStruct(tp, nil)
+}
+
+func (b *schemaBuilder) addStruct(tp reflect.Type, prefix document.Path) error {
+	// a struct containing itself would describe infinitely nested paths
+	if b.visiting[tp] {
+		return fmt.Errorf("cyclic struct type %s", tp)
+	}
+	b.visiting[tp] = true
+	defer delete(b.visiting, tp)
+
+	for i := 0; i < tp.NumField(); i++ {
+		sf := tp.Field(i)
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		isUnexported := sf.PkgPath != ""
+
+		// embedded structs are flattened, like with document.NewFromStruct
+		if sf.Anonymous {
+			if ft.Kind() != reflect.Struct {
+				continue
+			}
+			err := b.addStruct(ft, prefix)
+			if err != nil {
+				return err
+			}
+			continue
+		} else if isUnexported {
+			continue
+		}
+
+		tag, err := document.ParseStructTag(sf)
+		if err != nil {
+			return err
+		}
+		if tag.Skip {
+			continue
+		}
+
+		path := append(prefix[:len(prefix):len(prefix)], document.PathFragment{FieldName: tag.Name})
+		b.paths = append(b.paths, path)
+
+		if tag.PrimaryKey {
+			b.primaryKey = append(b.primaryKey, path)
+		}
+		if tag.Unique {
+			b.uniquePaths = append(b.uniquePaths, path)
+		}
+
+		// fields of nested structs are declared with their own path
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			if tag.NotNull || tag.Default != "" {
+				b.addField(path, types.DocumentValue, tag)
+			}
+
+			err = b.addStruct(ft, path)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		tp, err := valueTypeOf(ft)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", sf.Name, err)
+		}
+
+		// untyped fields are only declared if they have constraints
+		if tp.IsAny() && !tag.NotNull && tag.Default == "" {
+			continue
+		}
+
+		b.addField(path, tp, tag)
+	}
+
+	return nil
+}
+
+func (b *schemaBuilder) addField(path document.Path, tp types.ValueType, tag *document.StructTag) {
+	var s strings.Builder
+
+	s.WriteString(quotePath(path))
+	if !tp.IsAny() {
+		s.WriteString(" ")
+		s.WriteString(strings.ToUpper(tp.String()))
+	}
+	if tag.NotNull {
+		s.WriteString(" NOT NULL")
+	}
+	if tag.Default != "" {
+		fmt.Fprintf(&s, " DEFAULT (%s)", tag.Default)
+	}
+
+	b.fields = append(b.fields, s.String())
+}
+
+// query returns the CREATE TABLE statement.
+func (b *schemaBuilder) query() string {
+	defs := b.fields
+	if len(b.primaryKey) > 0 {
+		paths := make([]string, len(b.primaryKey))
+		for i, p := range b.primaryKey {
+			paths[i] = quotePath(p)
+		}
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(paths, ", ")))
+	}
+	for _, p := range b.uniquePaths {
+		defs = append(defs, fmt.Sprintf("UNIQUE (%s)", quotePath(p)))
+	}
+
+	q := "CREATE TABLE " + stringutil.NormalizeIdentifier(b.tableName, '`')
+	if len(defs) > 0 {
+		q += " (" + strings.Join(defs, ", ") + ")"
+	}
+
+	return q
+}
+
+// quotePath returns the path as it must be written in a query,
+// with the field names that aren't valid identifiers quoted.
+func quotePath(p document.Path) string {
+	var s strings.Builder
+
+	for i := range p {
+		if p[i].FieldName == "" {
+			s.WriteString("[" + strconv.Itoa(p[i].ArrayIndex) + "]")
+			continue
+		}
+
+		if i != 0 {
+			s.WriteRune('.')
+		}
+		if scanner.IsKeyword(p[i].FieldName) {
+			s.WriteString("`" + p[i].FieldName + "`")
+		} else {
+			s.WriteString(stringutil.NormalizeIdentifier(p[i].FieldName, '`'))
+		}
+	}
+
+	return s.String()
+}
+
+// valueTypeOf returns the type of the values created from the given Go type
+// by document.NewValue.
+func valueTypeOf(tp reflect.Type) (types.ValueType, error) {
+	switch tp {
+	case timeType:
+		return types.TextValue, nil
+	case durationType:
+		return types.IntegerValue, nil
+	}
+
+	switch tp.Kind() {
+	case reflect.Bool:
+		return types.BoolValue, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return types.IntegerValue, nil
+	case reflect.Float32, reflect.Float64:
+		return types.DoubleValue, nil
+	case reflect.String:
+		return types.TextValue, nil
+	case reflect.Slice:
+		if tp.Elem().Kind() == reflect.Uint8 {
+			return types.BlobValue, nil
+		}
+		return types.ArrayValue, nil
+	case reflect.Array:
+		return types.ArrayValue, nil
+	case reflect.Map, reflect.Struct:
+		return types.DocumentValue, nil
+	case reflect.Interface:
+		return types.AnyType, nil
+	}
+
+	return 0, fmt.Errorf("unsupported type %s", tp)
+}
@@ -0,0 +1,229 @@
+package genji_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/genjidb/genji/types"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaAddress struct {
+	City    string `genji:",notnull"`
+	ZipCode string `genji:"zip_code"`
+}
+
+type schemaBase struct {
+	ID int64 `genji:"id,pk"`
+}
+
+type schemaUser struct {
+	schemaBase
+	Name      string `genji:"name,unique,notnull"`
+	Age       uint8
+	Score     *float64 `genji:",default=1.5"`
+	Tags      []string
+	Avatar    []byte
+	Meta      map[string]interface{}
+	Extra     interface{}
+	Address   schemaAddress
+	CreatedAt time.Time `genji:"created_at"`
+	Ignored   string    `genji:"-"`
+	internal  int
+}
+
+type schemaNode struct {
+	Name string
+	Next *schemaNode
+}
+
+type schemaQuoted struct {
+	Type    string `genji:"type,pk"`
+	Field   int    `genji:"field,notnull"`
+	MyField string `genji:"my field,unique"`
+	Key     struct {
+		MyField bool `genji:"my field"`
+	} `genji:"key"`
+}
+
+func listFields(t *testing.T, db *genji.DB, tableName string) []string {
+	t.Helper()
+
+	res, err := db.Query("SELECT path, type, not_null FROM __genji_fields WHERE table_name = ?", tableName)
+	assert.NoError(t, err)
+	defer res.Close()
+
+	var fields []string
+	err = res.Iterate(func(d types.Document) error {
+		var path, tp string
+		var notNull bool
+		err := document.Scan(d, &path, &tp, &notNull)
+		if notNull {
+			tp += " not null"
+		}
+		fields = append(fields, path+" "+tp)
+		return err
+	})
+	assert.NoError(t, err)
+
+	return fields
+}
+
+func TestCreateTableFromStruct(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = genji.CreateTableFromStruct(db, "users", &schemaUser{})
+	assert.NoError(t, err)
+
+	require.Equal(t, []string{
+		"id integer",
+		"name text not null",
+		"age integer",
+		"score double",
+		"tags array",
+		"avatar blob",
+		"meta document",
+		"address.city text not null",
+		"address.zip_code text",
+		"created_at text",
+	}, listFields(t, db, "users"))
+
+	d, err := db.QueryDocument("SELECT sql FROM __genji_tables WHERE name = 'users'")
+	assert.NoError(t, err)
+	var sql string
+	assert.NoError(t, document.Scan(d, &sql))
+	require.Contains(t, sql, "score DOUBLE DEFAULT 1.5")
+	require.Contains(t, sql, "PRIMARY KEY (id)")
+	require.Contains(t, sql, "UNIQUE (name)")
+
+	// documents created from the struct are valid
+	now := time.Now()
+	err = db.Exec("INSERT INTO users VALUES ?", &schemaUser{
+		schemaBase: schemaBase{ID: 1},
+		Name:       "foo",
+		Address:    schemaAddress{City: "Lyon"},
+		CreatedAt:  now,
+	})
+	assert.NoError(t, err)
+
+	d, err = db.QueryDocument("SELECT * FROM users")
+	assert.NoError(t, err)
+	var u schemaUser
+	assert.NoError(t, document.StructScan(d, &u))
+	require.Equal(t, "foo", u.Name)
+	require.Equal(t, 1.5, *u.Score)
+	require.Equal(t, "Lyon", u.Address.City)
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name string
+			s    interface{}
+		}{
+			{"not a struct", 10},
+			{"nil", nil},
+			{"unknown option", &struct {
+				A int `genji:"a,foo"`
+			}{}},
+			{"unsupported type", &struct{ A chan int }{}},
+			{"invalid default", &struct {
+				A int `genji:",default=("`
+			}{}},
+			{"cyclic struct", &schemaNode{}},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				err := genji.CreateTableFromStruct(db, "foo", test.s)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestCreateTableFromStructQuoted(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = genji.CreateTableFromStruct(db, "quoted", &schemaQuoted{})
+	assert.NoError(t, err)
+
+	require.Equal(t, []string{
+		"type text",
+		"field integer not null",
+		"my field text",
+		"key.my field bool",
+	}, listFields(t, db, "quoted"))
+
+	var q schemaQuoted
+	q.Type = "a"
+	q.Field = 1
+	q.MyField = "b"
+	q.Key.MyField = true
+	err = db.Exec("INSERT INTO quoted VALUES ?", &q)
+	assert.NoError(t, err)
+
+	// the unique constraint applies to the quoted field
+	q.Type = "c"
+	err = db.Exec("INSERT INTO quoted VALUES ?", &q)
+	assert.Error(t, err)
+
+	drift, err := genji.StructDrift(db, "quoted", &schemaQuoted{})
+	assert.NoError(t, err)
+	require.Empty(t, drift)
+}
+
+func TestStructDrift(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = genji.CreateTableFromStruct(db, "users", &schemaUser{})
+	assert.NoError(t, err)
+
+	drift, err := genji.StructDrift(db, "users", schemaUser{})
+	assert.NoError(t, err)
+	require.Empty(t, drift)
+
+	err = db.Exec(`
+		CREATE TABLE other (
+			id INTEGER,
+			name TEXT NOT NULL,
+			age DOUBLE NOT NULL,
+			score DOUBLE DEFAULT 2,
+			address.city TEXT NOT NULL,
+			legacy TEXT,
+			UNIQUE (age)
+		)`)
+	assert.NoError(t, err)
+
+	drift, err = genji.StructDrift(db, "other", schemaUser{})
+	assert.NoError(t, err)
+
+	var got []string
+	for _, d := range drift {
+		got = append(got, d.String())
+	}
+	require.Equal(t, []string{
+		"age: type is integer in the struct and double in the table",
+		"age: NOT NULL in the table only",
+		"score: default is 1.5 in the struct and 2 in the table",
+		"tags: not declared by the table",
+		"avatar: not declared by the table",
+		"meta: not declared by the table",
+		"address.zip_code: not declared by the table",
+		"created_at: not declared by the table",
+		"legacy: not declared by the struct",
+		"primary key is (id) in the struct and none in the table",
+		"name: unique constraint not declared by the table",
+		"age: unique constraint not declared by the struct",
+	}, got)
+
+	_, err = genji.StructDrift(db, "unknown", schemaUser{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+-- setup:
+CREATE TABLE test (a INT);
+CREATE SEQUENCE seq;
+
+-- test: RESTART
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq), (NEXT VALUE FOR seq);
+ALTER SEQUENCE seq RESTART;
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+SELECT a FROM test;
+/* result:
+{
+  "a": 1
+}
+{
+  "a": 2
+}
+{
+  "a": 1
+}
+*/
+
+-- test: RESTART WITH
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+ALTER SEQUENCE seq RESTART WITH 100;
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq), (NEXT VALUE FOR seq);
+SELECT a FROM test;
+/* result:
+{
+  "a": 1
+}
+{
+  "a": 100
+}
+{
+  "a": 101
+}
+*/
+
+-- test: RESTART 100
+ALTER SEQUENCE seq RESTART 100;
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+SELECT a FROM test;
+/* result:
+{
+  "a": 100
+}
+*/
+
+-- test: INCREMENT BY
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+ALTER SEQUENCE seq INCREMENT BY 10;
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq), (NEXT VALUE FOR seq);
+SELECT a FROM test;
+/* result:
+{
+  "a": 1
+}
+{
+  "a": 11
+}
+{
+  "a": 21
+}
+*/
+
+-- test: catalog
+ALTER SEQUENCE seq INCREMENT BY 2 MINVALUE 10 MAXVALUE 20 START WITH 12 CACHE 5 CYCLE;
+SELECT * FROM __genji_catalog WHERE type = "sequence" AND name = "seq";
+/* result:
+{
+  "name": "seq",
+  "type": "sequence",
+  "sql": "CREATE SEQUENCE seq INCREMENT BY 2 MINVALUE 10 MAXVALUE 20 START WITH 12 CACHE 5 CYCLE"
+}
+*/
+
+-- test: NO MINVALUE NO MAXVALUE NO CYCLE
+ALTER SEQUENCE seq MINVALUE 10 MAXVALUE 20 START 10 CYCLE;
+ALTER SEQUENCE seq NO MINVALUE NO MAXVALUE START 1 NO CYCLE;
+SELECT * FROM __genji_catalog WHERE type = "sequence" AND name = "seq";
+/* result:
+{
+  "name": "seq",
+  "type": "sequence",
+  "sql": "CREATE SEQUENCE seq"
+}
+*/
+
+-- test: MAXVALUE
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+ALTER SEQUENCE seq MAXVALUE 2;
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+-- error:
+
+-- test: MAXVALUE with CYCLE
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+ALTER SEQUENCE seq MAXVALUE 2 CYCLE;
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq), (NEXT VALUE FOR seq);
+SELECT a FROM test;
+/* result:
+{
+  "a": 1
+}
+{
+  "a": 2
+}
+{
+  "a": 1
+}
+*/
+
+-- test: MINVALUE greater than MAXVALUE
+ALTER SEQUENCE seq MINVALUE 10 MAXVALUE 5;
+-- error:
+
+-- test: START out of bounds
+ALTER SEQUENCE seq MINVALUE 10;
+-- error:
+
+-- test: RESTART out of bounds
+ALTER SEQUENCE seq MAXVALUE 10 RESTART WITH 20;
+-- error:
+
+-- test: unknown sequence
+ALTER SEQUENCE foo RESTART;
+-- error:
+
+-- test: no options
+ALTER SEQUENCE seq;
+-- error:
+
+-- test: AS
+ALTER SEQUENCE seq AS INT;
+-- error:
@@ -0,0 +1,39 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int);
+INSERT INTO test (a, b) VALUES (1, 10), (2, 20);
+
+-- test: existing documents
+ALTER TABLE test ADD FIELD c INT GENERATED ALWAYS AS (a + b);
+SELECT * FROM test;
+/* result:
+{
+  "a": 1,
+  "b": 10,
+  "c": 11
+}
+{
+  "a": 2,
+  "b": 20,
+  "c": 22
+}
+*/
+
+-- test: new documents
+ALTER TABLE test ADD FIELD c INT GENERATED ALWAYS AS (a + b);
+INSERT INTO test (a, b) VALUES (3, 30);
+SELECT c FROM test WHERE a = 3;
+/* result:
+{
+  "c": 33
+}
+*/
+
+-- test: alter type of a dependency
+ALTER TABLE test ADD FIELD c GENERATED ALWAYS AS (b / 3);
+ALTER TABLE test ALTER FIELD b TYPE double;
+SELECT c FROM test WHERE a = 1;
+/* result:
+{
+  "c": 3.3333333333333335
+}
+*/
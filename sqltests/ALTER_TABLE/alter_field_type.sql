@@ -0,0 +1,54 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int, c TEXT);
+CREATE INDEX test_b_idx ON test(b);
+INSERT INTO test (a, b, c) VALUES (1, 10, '1'), (2, 20, 'foo');
+
+-- test: conversion
+ALTER TABLE test ALTER FIELD b TYPE double;
+SELECT b FROM test;
+/* result:
+{
+  "b": 10.0
+}
+{
+  "b": 20.0
+}
+*/
+
+-- test: constraint is updated
+ALTER TABLE test ALTER FIELD b TYPE double;
+SELECT name, sql FROM __genji_catalog WHERE type = "table" AND name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER, b DOUBLE, c TEXT, PRIMARY KEY (a))"
+}
+*/
+
+-- test: index is updated
+ALTER TABLE test ALTER FIELD b TYPE double;
+SELECT a FROM test WHERE b = 20.0;
+/* result:
+{
+  "a": 2
+}
+*/
+
+-- test: new field constraint
+ALTER TABLE test ALTER FIELD d TYPE integer;
+INSERT INTO test (a, d) VALUES (3, 1.5);
+SELECT a, d FROM test WHERE a = 3;
+/* result:
+{
+  "a": 3,
+  "d": 1
+}
+*/
+
+-- test: failed conversion
+ALTER TABLE test ALTER FIELD c TYPE integer;
+-- error:
+
+-- test: primary key
+ALTER TABLE test ALTER FIELD a TYPE double;
+-- error:
@@ -19,3 +19,8 @@ ALTER TABLE unknown RENAME TO test2;
 CREATE TABLE test2;
 ALTER TABLE test2 RENAME TO test;
 -- error:
+
+-- test: read by a view
+CREATE VIEW v AS SELECT a FROM test;
+ALTER TABLE test RENAME TO test2;
+-- error: cannot rename test: view v depends on it
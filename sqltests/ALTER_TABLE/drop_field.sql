@@ -0,0 +1,91 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int NOT NULL, c TEXT, d int, CHECK (d > 0));
+CREATE INDEX test_c_idx ON test(c);
+INSERT INTO test (a, b, c, d, e) VALUES (1, 10, 'foo', 1, true), (2, 20, 'bar', 2, false);
+
+-- test: drop field
+ALTER TABLE test DROP FIELD b;
+SELECT * FROM test;
+/* result:
+{
+  "a": 1,
+  "c": "foo",
+  "d": 1,
+  "e": true
+}
+{
+  "a": 2,
+  "c": "bar",
+  "d": 2,
+  "e": false
+}
+*/
+
+-- test: constraints are removed
+ALTER TABLE test DROP FIELD b;
+SELECT name, sql FROM __genji_catalog WHERE type = "table" AND name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER, c TEXT, d INTEGER, PRIMARY KEY (a), CHECK (d > 0))"
+}
+*/
+
+-- test: field without constraint
+ALTER TABLE test DROP FIELD e;
+SELECT * FROM test WHERE a = 1;
+/* result:
+{
+  "a": 1,
+  "b": 10,
+  "c": "foo",
+  "d": 1
+}
+*/
+
+-- test: insert after drop
+ALTER TABLE test DROP FIELD b;
+INSERT INTO test (a, c, d) VALUES (3, 'baz', 3);
+SELECT COUNT(*) FROM test;
+/* result:
+{
+  "COUNT(*)": 3
+}
+*/
+
+-- test: primary key
+ALTER TABLE test DROP FIELD a;
+-- error:
+
+-- test: indexed field
+ALTER TABLE test DROP FIELD c;
+-- error:
+
+-- test: check constraint
+ALTER TABLE test DROP FIELD d;
+-- error:
+
+-- test: unknown table
+ALTER TABLE unknown DROP FIELD b;
+-- error:
+
+-- test: used by a generated field
+CREATE TABLE gen (a INT, b INT GENERATED ALWAYS AS (a + 1));
+ALTER TABLE gen DROP FIELD a;
+-- error:
+
+-- test: generated field
+CREATE TABLE gen (a INT, b INT GENERATED ALWAYS AS (a + 1));
+INSERT INTO gen (a) VALUES (1);
+ALTER TABLE gen DROP FIELD b;
+INSERT INTO gen (a, b) VALUES (2, 10);
+SELECT * FROM gen;
+/* result:
+{
+  a: 1
+}
+{
+  a: 2,
+  b: 10.0
+}
+*/
@@ -0,0 +1,86 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int NOT NULL, c TEXT UNIQUE, d int, CHECK (d > 0));
+CREATE INDEX test_b_idx ON test(b);
+INSERT INTO test (a, b, c, d) VALUES (1, 10, 'foo', 1), (2, 20, 'bar', 2);
+
+-- test: rename field
+ALTER TABLE test RENAME FIELD b TO z;
+SELECT * FROM test;
+/* result:
+{
+  "a": 1,
+  "z": 10,
+  "c": "foo",
+  "d": 1
+}
+{
+  "a": 2,
+  "z": 20,
+  "c": "bar",
+  "d": 2
+}
+*/
+
+-- test: constraints and indexes are renamed
+ALTER TABLE test RENAME FIELD b TO z;
+SELECT name, sql FROM __genji_catalog WHERE name = "test" OR name = "test_b_idx";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER, z INTEGER NOT NULL, c TEXT, d INTEGER, PRIMARY KEY (a), UNIQUE (c), CHECK (d > 0))"
+}
+{
+  "name": "test_b_idx",
+  "sql": "CREATE INDEX test_b_idx ON test (z)"
+}
+*/
+
+-- test: renamed index is used
+ALTER TABLE test RENAME FIELD b TO z;
+EXPLAIN SELECT a FROM test WHERE z = 20;
+/* result:
+{
+  "plan": 'index.Scan("test_b_idx", [{"min": [20], "exact": true}]) | docs.Project(a)'
+}
+*/
+
+-- test: renamed index data
+ALTER TABLE test RENAME FIELD b TO z;
+SELECT a FROM test WHERE z = 20;
+/* result:
+{
+  "a": 2
+}
+*/
+
+-- test: primary key
+ALTER TABLE test RENAME FIELD a TO aa;
+INSERT INTO test (aa, b, c, d) VALUES (3, 30, 'baz', 3);
+SELECT aa FROM test WHERE aa = 3;
+/* result:
+{
+  "aa": 3
+}
+*/
+
+-- test: unique constraint
+ALTER TABLE test RENAME FIELD c TO cc;
+INSERT INTO test (a, b, cc, d) VALUES (3, 30, 'foo', 3);
+-- error:
+
+-- test: existing field
+ALTER TABLE test RENAME FIELD b TO c;
+-- error:
+
+-- test: check constraint
+ALTER TABLE test RENAME FIELD d TO dd;
+-- error:
+
+-- test: unknown table
+ALTER TABLE unknown RENAME FIELD b TO z;
+-- error:
+
+-- test: used by a generated field
+CREATE TABLE gen (a INT, b INT GENERATED ALWAYS AS (a + 1));
+ALTER TABLE gen RENAME FIELD a TO c;
+-- error:
@@ -0,0 +1,82 @@
+-- setup:
+CREATE TABLE test(a int PRIMARY KEY, b int, c int);
+CREATE INDEX test_b ON test(b);
+CREATE INDEX test_b_c ON test(b, c);
+CREATE TABLE foo;
+
+INSERT INTO test (a, b, c) VALUES (1, 1, 1), (2, 1, 2), (3, 2, 2), (4, 2, 2);
+INSERT INTO foo (a) VALUES (1), (2);
+
+-- test: all tables
+ANALYZE;
+SELECT name, type, table_name, row_count, distinct_count FROM __genji_statistics;
+/* result:
+{
+    "name": "foo",
+    "type": "table",
+    "table_name": "foo",
+    "row_count": 2,
+    "distinct_count": [2]
+}
+{
+    "name": "test",
+    "type": "table",
+    "table_name": "test",
+    "row_count": 4,
+    "distinct_count": [4]
+}
+{
+    "name": "test_b",
+    "type": "index",
+    "table_name": "test",
+    "row_count": 4,
+    "distinct_count": [2]
+}
+{
+    "name": "test_b_c",
+    "type": "index",
+    "table_name": "test",
+    "row_count": 4,
+    "distinct_count": [2, 3]
+}
+*/
+
+-- test: single table
+ANALYZE foo;
+SELECT name, row_count FROM __genji_statistics;
+/* result:
+{
+    "name": "foo",
+    "row_count": 2
+}
+*/
+
+-- test: histogram
+ANALYZE test;
+SELECT histogram FROM __genji_statistics WHERE name = 'test_b';
+/* result:
+{
+    "histogram": [
+        {"lower": 1, "upper": 1, "count": 2, "distinct_count": 1},
+        {"lower": 2, "upper": 2, "count": 2, "distinct_count": 1}
+    ]
+}
+*/
+
+-- test: drop table
+ANALYZE;
+DROP TABLE test;
+SELECT name FROM __genji_statistics;
+/* result:
+{
+    "name": "foo"
+}
+*/
+
+-- test: unknown table
+ANALYZE unknown;
+-- error:
+
+-- test: system table
+ANALYZE __genji_catalog;
+-- error:
@@ -0,0 +1,124 @@
+-- setup:
+CREATE TABLE foo (a INT PRIMARY KEY, b TEXT);
+INSERT INTO foo (a, b, c) VALUES (1, 'one', 1), (2, 'two', 'two'), (3, NULL, 3.5);
+
+-- test: inferred types
+CREATE TABLE test AS SELECT a, b, c FROM foo;
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test (a INTEGER, b TEXT)"
+}
+*/
+
+-- test: data
+CREATE TABLE test AS SELECT a, b, c FROM foo WHERE a > 1;
+SELECT * FROM test;
+/* result:
+{
+  a: 2,
+  b: "two",
+  c: "two"
+}
+{
+  a: 3,
+  b: null,
+  c: 3.5
+}
+*/
+
+-- test: projection
+CREATE TABLE test AS SELECT a * 10 AS x, b AS name FROM foo;
+SELECT * FROM test WHERE x > 10;
+/* result:
+{
+  x: 20,
+  name: "two"
+}
+{
+  x: 30,
+  name: null
+}
+*/
+
+-- test: the query is run once
+CREATE SEQUENCE seq;
+CREATE TABLE test AS SELECT a, NEXT VALUE FOR seq AS n FROM foo;
+SELECT * FROM test;
+/* result:
+{
+  a: 1,
+  n: 1
+}
+{
+  a: 2,
+  n: 2
+}
+{
+  a: 3,
+  n: 3
+}
+*/
+
+-- test: with constraints
+CREATE TABLE test (a DOUBLE PRIMARY KEY, b TEXT NOT NULL) AS SELECT a, b FROM foo WHERE b IS NOT NULL;
+SELECT * FROM test;
+/* result:
+{
+  a: 1.0,
+  b: "one"
+}
+{
+  a: 2.0,
+  b: "two"
+}
+*/
+
+-- test: with constraints, violation
+CREATE TABLE test (b TEXT NOT NULL) AS SELECT a, b FROM foo;
+-- error:
+
+-- test: with unique constraint
+CREATE TABLE test (c UNIQUE) AS SELECT c FROM foo;
+SELECT * FROM test WHERE c = 'two';
+/* result:
+{
+  c: "two"
+}
+*/
+
+-- test: with unique constraint, violation
+CREATE TABLE test (UNIQUE (x)) AS SELECT 1 AS x FROM foo;
+-- error:
+
+-- test: empty result
+CREATE TABLE test AS SELECT a, b FROM foo WHERE a > 10;
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test"
+}
+*/
+
+-- test: if not exists
+CREATE TABLE test (a INT);
+CREATE TABLE IF NOT EXISTS test AS SELECT a FROM foo;
+SELECT COUNT(*) FROM test;
+/* result:
+{
+  "COUNT(*)": 0
+}
+*/
+
+-- test: already exists
+CREATE TABLE test (a INT);
+CREATE TABLE test AS SELECT a FROM foo;
+-- error:
+
+-- test: unknown table
+CREATE TABLE test AS SELECT a FROM bar;
+-- error:
@@ -0,0 +1,59 @@
+-- test: basic
+CREATE TABLE test (
+    first TEXT,
+    last TEXT,
+    full_name TEXT GENERATED ALWAYS AS (first || ' ' || last)
+);
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test (first TEXT, last TEXT, full_name TEXT GENERATED ALWAYS AS (first || \" \" || last))"
+}
+*/
+
+-- test: stored
+CREATE TABLE test (
+    a INT,
+    b INT GENERATED ALWAYS AS (a * 2) STORED NOT NULL
+);
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test (a INTEGER, b INTEGER NOT NULL GENERATED ALWAYS AS (a * 2))"
+}
+*/
+
+-- test: lowercase
+create table test (a int, b int generated always as (a + 1));
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test (a INTEGER, b INTEGER GENERATED ALWAYS AS (a + 1))"
+}
+*/
+
+-- test: missing ALWAYS
+CREATE TABLE test (a INT, b INT GENERATED AS (a + 1));
+-- error:
+
+-- test: missing parentheses
+CREATE TABLE test (a INT, b INT GENERATED ALWAYS AS a + 1);
+-- error:
+
+-- test: with default value
+CREATE TABLE test (a INT, b INT DEFAULT 10 GENERATED ALWAYS AS (a + 1));
+-- error:
+
+-- test: nested path
+CREATE TABLE test (a INT, b.c INT GENERATED ALWAYS AS (a + 1));
+-- error:
+
+-- test: generated twice
+CREATE TABLE test (a INT, b INT GENERATED ALWAYS AS (a + 1) GENERATED ALWAYS AS (a + 2));
+-- error:
@@ -0,0 +1,79 @@
+-- test: basic
+CREATE TABLE test (a INT, expires_at TEXT) WITH TTL ON expires_at;
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test (a INTEGER, expires_at TEXT) WITH TTL ON expires_at"
+}
+*/
+
+-- test: untyped nested field
+CREATE TABLE test WITH TTL ON meta.expires_at;
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test WITH TTL ON meta.expires_at"
+}
+*/
+
+-- test: lowercase
+create table test (a int) with ttl on b;
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test (a INTEGER) WITH TTL ON b"
+}
+*/
+
+-- test: as select
+CREATE TABLE foo (a INT, b INT);
+INSERT INTO foo (a, b) VALUES (1, 10);
+CREATE TABLE test WITH TTL ON b AS SELECT * FROM foo;
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test (a INTEGER, b INTEGER) WITH TTL ON b"
+}
+*/
+
+-- test: invalid type
+CREATE TABLE test (a INT, expires_at BOOL) WITH TTL ON expires_at;
+-- error:
+
+-- test: missing path
+CREATE TABLE test (a INT) WITH TTL ON;
+-- error:
+
+-- test: missing TTL
+CREATE TABLE test (a INT) WITH expires_at;
+-- error:
+
+-- test: drop field
+CREATE TABLE test (a INT, expires_at TEXT) WITH TTL ON expires_at;
+ALTER TABLE test DROP FIELD expires_at;
+-- error:
+
+-- test: rename field
+CREATE TABLE test (a INT, expires_at TEXT) WITH TTL ON expires_at;
+ALTER TABLE test RENAME FIELD expires_at TO exp;
+SELECT name, type, sql FROM __genji_catalog WHERE name = "test";
+/* result:
+{
+  name: "test",
+  type: "table",
+  sql: "CREATE TABLE test (a INTEGER, exp TEXT) WITH TTL ON exp"
+}
+*/
+
+-- test: alter field type
+CREATE TABLE test (a INT, expires_at TEXT) WITH TTL ON expires_at;
+ALTER TABLE test ALTER FIELD expires_at TYPE BLOB;
+-- error:
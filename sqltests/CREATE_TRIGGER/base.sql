@@ -0,0 +1,259 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int);
+CREATE TABLE log(id int primary key, op TEXT, old_b int, new_b int);
+CREATE SEQUENCE log_seq;
+
+-- test: after insert
+CREATE TRIGGER t AFTER INSERT ON test FOR EACH ROW BEGIN
+    INSERT INTO log (id, op, new_b) VALUES (NEXT VALUE FOR log_seq, 'insert', NEW.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 10), (2, 20);
+SELECT * FROM log;
+/* result:
+{
+  "id": 1,
+  "op": "insert",
+  "new_b": 10
+}
+{
+  "id": 2,
+  "op": "insert",
+  "new_b": 20
+}
+*/
+
+-- test: after update
+INSERT INTO test (a, b) VALUES (1, 10), (2, 20);
+CREATE TRIGGER t AFTER UPDATE ON test BEGIN
+    INSERT INTO log (id, op, old_b, new_b) VALUES (NEXT VALUE FOR log_seq, 'update', OLD.b, NEW.b);
+END;
+UPDATE test SET b = b + 1 WHERE a = 2;
+SELECT * FROM log;
+/* result:
+{
+  "id": 1,
+  "op": "update",
+  "old_b": 20,
+  "new_b": 21
+}
+*/
+
+-- test: after delete
+INSERT INTO test (a, b) VALUES (1, 10), (2, 20);
+CREATE TRIGGER t AFTER DELETE ON test BEGIN
+    INSERT INTO log (id, op, old_b) VALUES (NEXT VALUE FOR log_seq, 'delete', OLD.b);
+END;
+DELETE FROM test WHERE a = 1;
+SELECT * FROM log;
+/* result:
+{
+  "id": 1,
+  "op": "delete",
+  "old_b": 10
+}
+*/
+
+-- test: after insert sees the indexes
+CREATE INDEX test_b_idx ON test(b);
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    INSERT INTO log (id, op, new_b) SELECT a, 'index', b FROM test WHERE b = 10;
+END;
+INSERT INTO test (a, b) VALUES (1, 10);
+SELECT id, op, new_b FROM log;
+/* result:
+{
+  "id": 1,
+  "op": "index",
+  "new_b": 10
+}
+*/
+
+-- test: before insert
+CREATE TRIGGER t BEFORE INSERT ON test BEGIN
+    INSERT INTO log (id, op, new_b) VALUES (NEW.a, 'insert', NEW.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 10);
+SELECT id, op, new_b FROM log;
+/* result:
+{
+  "id": 1,
+  "op": "insert",
+  "new_b": 10
+}
+*/
+
+-- test: when
+CREATE TRIGGER t AFTER INSERT ON test WHEN NEW.b > 15 BEGIN
+    INSERT INTO log (id, op, new_b) VALUES (NEW.a, 'insert', NEW.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 10), (2, 20);
+SELECT id, new_b FROM log;
+/* result:
+{
+  "id": 2,
+  "new_b": 20
+}
+*/
+
+-- test: multiple statements
+INSERT INTO log (id, op) VALUES (100, 'count');
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    UPDATE log SET new_b = NEW.b WHERE id = 100;
+    DELETE FROM log WHERE id = 100 AND NEW.b > 15;
+END;
+INSERT INTO test (a, b) VALUES (1, 10);
+SELECT id, new_b FROM log;
+/* result:
+{
+  "id": 100,
+  "new_b": 10
+}
+*/
+
+-- test: multiple statements with delete
+INSERT INTO log (id, op) VALUES (100, 'count');
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    UPDATE log SET new_b = NEW.b WHERE id = 100;
+    DELETE FROM log WHERE id = 100 AND NEW.b > 15;
+END;
+INSERT INTO test (a, b) VALUES (1, 20);
+SELECT COUNT(*) FROM log;
+/* result:
+{
+  "COUNT(*)": 0
+}
+*/
+
+-- test: triggers are fired in name order
+CREATE TRIGGER t2 AFTER INSERT ON test BEGIN
+    INSERT INTO log (id, op) VALUES (NEXT VALUE FOR log_seq, 't2');
+END;
+CREATE TRIGGER t1 AFTER INSERT ON test BEGIN
+    INSERT INTO log (id, op) VALUES (NEXT VALUE FOR log_seq, 't1');
+END;
+INSERT INTO test (a, b) VALUES (1, 10);
+SELECT id, op FROM log;
+/* result:
+{
+  "id": 1,
+  "op": "t1"
+}
+{
+  "id": 2,
+  "op": "t2"
+}
+*/
+
+-- test: error in trigger
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    INSERT INTO log (id) VALUES (1);
+END;
+INSERT INTO log (id) VALUES (1);
+INSERT INTO test (a, b) VALUES (1, 10);
+-- error:
+
+-- test: recursion
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    INSERT INTO test (a, b) VALUES (NEW.a + 1, NEW.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 10);
+-- error:
+
+-- test: limited recursion
+CREATE TRIGGER t AFTER INSERT ON test WHEN NEW.a < 5 BEGIN
+    INSERT INTO test (a, b) VALUES (NEW.a + 1, NEW.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 10);
+SELECT COUNT(*) FROM test;
+/* result:
+{
+  "COUNT(*)": 5
+}
+*/
+
+-- test: catalog
+CREATE TRIGGER t AFTER UPDATE ON test FOR EACH ROW WHEN OLD.b != NEW.b BEGIN
+    INSERT INTO log (id, op, old_b, new_b) VALUES (NEXT VALUE FOR log_seq, 'update', OLD.b, NEW.b);
+    UPDATE log SET op = 'seen' WHERE op = 'update';
+    DELETE FROM log WHERE id > 100;
+END;
+SELECT name, type, table_name, sql FROM __genji_catalog WHERE name = "t";
+/* result:
+{
+  "name": "t",
+  "type": "trigger",
+  "table_name": "test",
+  "sql": "CREATE TRIGGER t AFTER UPDATE ON test FOR EACH ROW WHEN OLD.b != NEW.b BEGIN INSERT INTO log (id, op, old_b, new_b) VALUES (NEXT VALUE FOR log_seq, \"update\", OLD.b, NEW.b); UPDATE log SET op = \"seen\" WHERE op = \"update\"; DELETE FROM log WHERE id > 100; END"
+}
+*/
+
+-- test: if not exists
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    INSERT INTO log (id) VALUES (NEW.a);
+END;
+CREATE TRIGGER IF NOT EXISTS t AFTER INSERT ON test BEGIN
+    INSERT INTO log (id) VALUES (NEW.a + 100);
+END;
+INSERT INTO test (a, b) VALUES (1, 10);
+SELECT id FROM log;
+/* result:
+{
+  "id": 1
+}
+*/
+
+-- test: duplicate
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    INSERT INTO log (id) VALUES (NEW.a);
+END;
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    INSERT INTO log (id) VALUES (NEW.a);
+END;
+-- error:
+
+-- test: unknown table
+CREATE TRIGGER t AFTER INSERT ON unknown BEGIN
+    INSERT INTO log (id) VALUES (NEW.a);
+END;
+-- error:
+
+-- test: read-only table
+CREATE TRIGGER t AFTER INSERT ON __genji_catalog BEGIN
+    INSERT INTO log (id) VALUES (1);
+END;
+-- error:
+
+-- test: drop table drops its triggers
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    INSERT INTO log (id) VALUES (NEW.a);
+END;
+DROP TABLE test;
+SELECT name FROM __genji_catalog WHERE type = "trigger";
+/* result:
+*/
+
+-- test: rename table
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    INSERT INTO log (id) VALUES (NEW.a);
+END;
+ALTER TABLE test RENAME TO foo;
+INSERT INTO foo (a, b) VALUES (1, 10);
+SELECT table_name FROM __genji_catalog WHERE name = "t";
+/* result:
+{
+  "table_name": "foo"
+}
+*/
+
+-- test: schema changes don't fire triggers
+INSERT INTO test (a, b) VALUES (1, 10);
+CREATE TRIGGER t AFTER UPDATE ON test BEGIN
+    INSERT INTO log (id) VALUES (NEW.a);
+END;
+ALTER TABLE test DROP FIELD b;
+SELECT COUNT(*) FROM log;
+/* result:
+{
+  "COUNT(*)": 0
+}
+*/
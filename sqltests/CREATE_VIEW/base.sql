@@ -0,0 +1,138 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int, c TEXT);
+INSERT INTO test (a, b, c) VALUES (1, 10, 'foo'), (2, 20, 'bar'), (3, 30, 'baz');
+
+-- test: basic
+CREATE VIEW v AS SELECT a, b FROM test WHERE b > 10;
+SELECT * FROM v;
+/* result:
+{
+  "a": 2,
+  "b": 20
+}
+{
+  "a": 3,
+  "b": 30
+}
+*/
+
+-- test: filter on view
+CREATE VIEW v AS SELECT a, b FROM test WHERE b > 10;
+SELECT a FROM v WHERE b < 30;
+/* result:
+{
+  "a": 2
+}
+*/
+
+-- test: reflects table changes
+CREATE VIEW v AS SELECT a FROM test;
+INSERT INTO test (a, b) VALUES (4, 40);
+SELECT COUNT(*) FROM v;
+/* result:
+{
+  "COUNT(*)": 4
+}
+*/
+
+-- test: catalog
+CREATE VIEW v AS SELECT b + 1 AS `b plus one`, COUNT(*) AS n FROM test WHERE c != "foo" GROUP BY b + 1 ORDER BY n DESC LIMIT 10 OFFSET 1;
+SELECT name, type, sql FROM __genji_catalog WHERE name = "v";
+/* result:
+{
+  "name": "v",
+  "type": "view",
+  "sql": "CREATE VIEW v AS SELECT b + 1 AS `b plus one`, COUNT(*) AS n FROM test WHERE c != \"foo\" GROUP BY b + 1 ORDER BY n DESC LIMIT 10 OFFSET 1"
+}
+*/
+
+-- test: group by
+CREATE VIEW v AS SELECT b % 20 AS m, COUNT(*) AS n FROM test GROUP BY b % 20;
+SELECT * FROM v;
+/* result:
+{
+  "m": 0,
+  "n": 1
+}
+{
+  "m": 10,
+  "n": 2
+}
+*/
+
+-- test: union
+CREATE VIEW v AS SELECT a FROM test WHERE a = 1 UNION ALL SELECT a FROM test WHERE a = 3;
+SELECT * FROM v;
+/* result:
+{
+  "a": 1
+}
+{
+  "a": 3
+}
+*/
+
+-- test: view of view
+CREATE VIEW v AS SELECT a, b FROM test WHERE b > 10;
+CREATE VIEW w AS SELECT a FROM v WHERE b > 20;
+SELECT * FROM w;
+/* result:
+{
+  "a": 3
+}
+*/
+
+-- test: if not exists
+CREATE VIEW v AS SELECT a FROM test;
+CREATE VIEW IF NOT EXISTS v AS SELECT b FROM test;
+SELECT * FROM v WHERE a = 1;
+/* result:
+{
+  "a": 1
+}
+*/
+
+-- test: duplicate
+CREATE VIEW v AS SELECT a FROM test;
+CREATE VIEW v AS SELECT b FROM test;
+-- error:
+
+-- test: same name as table
+CREATE VIEW test AS SELECT a FROM test;
+-- error:
+
+-- test: read from itself
+CREATE VIEW v AS SELECT a FROM v;
+-- error:
+
+-- test: unknown table
+CREATE VIEW v AS SELECT a FROM unknown;
+-- error:
+
+-- test: invalid query
+CREATE VIEW v AS SELECT a, COUNT(*) FROM test GROUP BY b;
+-- error:
+
+-- test: params
+CREATE VIEW v AS SELECT a FROM test WHERE a = ?;
+-- error:
+
+-- test: insert into view
+CREATE VIEW v AS SELECT a FROM test;
+INSERT INTO v (a) VALUES (10);
+-- error: cannot write to view v
+
+-- test: update view
+CREATE VIEW v AS SELECT a FROM test;
+UPDATE v SET a = 10;
+-- error: cannot write to view v
+
+-- test: delete from view
+CREATE VIEW v AS SELECT a FROM test;
+DELETE FROM v;
+-- error: cannot write to view v
+
+-- test: create table with view name
+CREATE VIEW v AS SELECT a FROM test;
+CREATE TABLE v;
+-- error:
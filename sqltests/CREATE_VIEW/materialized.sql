@@ -0,0 +1,98 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int);
+INSERT INTO test (a, b) VALUES (1, 10), (2, 20), (3, 30);
+
+-- test: basic
+CREATE MATERIALIZED VIEW v AS SELECT a, b FROM test WHERE b > 10;
+SELECT * FROM v;
+/* result:
+{
+  "a": 2,
+  "b": 20
+}
+{
+  "a": 3,
+  "b": 30
+}
+*/
+
+-- test: catalog
+CREATE MATERIALIZED VIEW v AS SELECT a FROM test;
+SELECT name, type, sql FROM __genji_catalog WHERE name = "v";
+/* result:
+{
+  "name": "v",
+  "type": "view",
+  "sql": "CREATE MATERIALIZED VIEW v AS SELECT a FROM test"
+}
+*/
+
+-- test: not updated until refreshed
+CREATE MATERIALIZED VIEW v AS SELECT a FROM test;
+INSERT INTO test (a, b) VALUES (4, 40);
+SELECT COUNT(*) FROM v;
+/* result:
+{
+  "COUNT(*)": 3
+}
+*/
+
+-- test: refresh
+CREATE MATERIALIZED VIEW v AS SELECT a FROM test;
+INSERT INTO test (a, b) VALUES (4, 40);
+DELETE FROM test WHERE a = 1;
+REFRESH MATERIALIZED VIEW v;
+SELECT * FROM v;
+/* result:
+{
+  "a": 2
+}
+{
+  "a": 3
+}
+{
+  "a": 4
+}
+*/
+
+-- test: order is preserved
+CREATE MATERIALIZED VIEW v AS SELECT a FROM test ORDER BY a DESC;
+SELECT a, pk() FROM v;
+/* result:
+{
+  "a": 3,
+  "pk()": [1]
+}
+{
+  "a": 2,
+  "pk()": [2]
+}
+{
+  "a": 1,
+  "pk()": [3]
+}
+*/
+
+-- test: refresh regular view
+CREATE VIEW v AS SELECT a FROM test;
+REFRESH MATERIALIZED VIEW v;
+-- error:
+
+-- test: refresh unknown view
+REFRESH MATERIALIZED VIEW v;
+-- error:
+
+-- test: insert
+CREATE MATERIALIZED VIEW v AS SELECT a FROM test;
+INSERT INTO v (a) VALUES (10);
+-- error: cannot write to view v
+
+-- test: delete
+CREATE MATERIALIZED VIEW v AS SELECT a FROM test;
+DELETE FROM v;
+-- error: cannot write to view v
+
+-- test: index
+CREATE MATERIALIZED VIEW v AS SELECT a FROM test;
+CREATE INDEX v_a_idx ON v(a);
+-- error:
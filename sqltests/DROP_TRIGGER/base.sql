@@ -0,0 +1,40 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int);
+CREATE TABLE log(id int primary key);
+CREATE TRIGGER t AFTER INSERT ON test BEGIN
+    INSERT INTO log (id) VALUES (NEW.a);
+END;
+
+-- test: drop trigger
+DROP TRIGGER t;
+INSERT INTO test (a, b) VALUES (1, 10);
+SELECT COUNT(*) FROM log;
+/* result:
+{
+  "COUNT(*)": 0
+}
+*/
+
+-- test: catalog
+DROP TRIGGER t;
+SELECT name FROM __genji_catalog WHERE name = "t";
+/* result:
+*/
+
+-- test: if exists
+DROP TRIGGER t;
+DROP TRIGGER IF EXISTS t;
+SELECT COUNT(*) FROM test;
+/* result:
+{
+  "COUNT(*)": 0
+}
+*/
+
+-- test: unknown
+DROP TRIGGER unknown;
+-- error:
+
+-- test: table
+DROP TRIGGER test;
+-- error:
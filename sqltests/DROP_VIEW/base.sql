@@ -0,0 +1,86 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int);
+INSERT INTO test (a, b) VALUES (1, 10), (2, 20);
+
+-- test: drop view
+CREATE VIEW v AS SELECT a FROM test;
+DROP VIEW v;
+SELECT name FROM __genji_catalog WHERE name = "v";
+/* result:
+*/
+
+-- test: drop materialized view
+CREATE MATERIALIZED VIEW v AS SELECT a FROM test;
+DROP VIEW v;
+CREATE VIEW v AS SELECT b FROM test;
+SELECT * FROM v;
+/* result:
+{
+  "b": 10
+}
+{
+  "b": 20
+}
+*/
+
+-- test: table is untouched
+CREATE VIEW v AS SELECT a FROM test;
+DROP VIEW v;
+SELECT COUNT(*) FROM test;
+/* result:
+{
+  "COUNT(*)": 2
+}
+*/
+
+-- test: if exists
+DROP VIEW IF EXISTS v;
+SELECT COUNT(*) FROM test;
+/* result:
+{
+  "COUNT(*)": 2
+}
+*/
+
+-- test: unknown
+DROP VIEW v;
+-- error:
+
+-- test: table
+DROP VIEW test;
+-- error: test is not a view
+
+-- test: table if exists
+DROP VIEW IF EXISTS test;
+-- error: test is not a view
+
+-- test: table read by a view
+CREATE VIEW v AS SELECT a FROM test;
+DROP VIEW test;
+-- error: test is not a view
+
+-- test: other views depend on it
+CREATE VIEW v AS SELECT a FROM test;
+CREATE VIEW w AS SELECT a FROM v;
+DROP VIEW v;
+-- error: cannot drop v: view w depends on it
+
+-- test: drop table read by a view
+CREATE VIEW v AS SELECT a FROM test;
+DROP TABLE test;
+-- error: cannot drop test: view v depends on it
+
+-- test: drop table read by a materialized view
+CREATE MATERIALIZED VIEW v AS SELECT a FROM test;
+DROP TABLE test;
+-- error: cannot drop test: view v depends on it
+
+-- test: drop table after its views
+CREATE VIEW v AS SELECT a FROM test;
+CREATE VIEW w AS SELECT a FROM v;
+DROP VIEW w;
+DROP VIEW v;
+DROP TABLE test;
+SELECT name FROM __genji_catalog WHERE name = "test";
+/* result:
+*/
@@ -0,0 +1,92 @@
+-- setup:
+CREATE TABLE test (
+    first TEXT,
+    last TEXT,
+    full_name TEXT GENERATED ALWAYS AS (first || ' ' || last),
+    greeting TEXT GENERATED ALWAYS AS ('Hello ' || full_name)
+);
+
+-- test: computed
+INSERT INTO test (first, last) VALUES ('John', 'Doe');
+SELECT * FROM test;
+/* result:
+{
+  first: "John",
+  last: "Doe",
+  full_name: "John Doe",
+  greeting: "Hello John Doe"
+}
+*/
+
+-- test: documents
+INSERT INTO test VALUES {first: 'Jane', last: 'Doe'};
+SELECT full_name FROM test;
+/* result:
+{
+  full_name: "Jane Doe"
+}
+*/
+
+-- test: null dependencies
+INSERT INTO test (first) VALUES ('John');
+SELECT full_name FROM test;
+/* result:
+{
+  full_name: null
+}
+*/
+
+-- test: not null
+CREATE TABLE foo (a INT, b INT GENERATED ALWAYS AS (a + 1) NOT NULL);
+INSERT INTO foo (a) VALUES (1);
+INSERT INTO foo (b) VALUES (NULL);
+-- error:
+
+-- test: not null, computed null
+CREATE TABLE foo (a INT, b INT GENERATED ALWAYS AS (a + 1) NOT NULL);
+INSERT INTO foo (a) VALUES (NULL);
+-- error:
+
+-- test: type conversion
+CREATE TABLE foo (a DOUBLE, b INT GENERATED ALWAYS AS (a * 2));
+INSERT INTO foo (a) VALUES (1.6);
+SELECT b FROM foo;
+/* result:
+{
+  b: 3
+}
+*/
+
+-- test: write generated field
+INSERT INTO test (first, last, full_name) VALUES ('John', 'Doe', 'foo');
+-- error: cannot write to generated field "full_name"
+
+-- test: write generated field with document
+INSERT INTO test VALUES {first: 'John', last: 'Doe', full_name: 'John Doe'};
+-- error: cannot write to generated field "full_name"
+
+-- test: index
+CREATE INDEX test_full_name_idx ON test (full_name);
+INSERT INTO test (first, last) VALUES ('John', 'Doe'), ('Jane', 'Doe');
+EXPLAIN SELECT first FROM test WHERE full_name = 'Jane Doe';
+/* result:
+{
+  plan: 'index.Scan("test_full_name_idx", [{"min": ["Jane Doe"], "exact": true}]) | docs.Project(first)'
+}
+*/
+
+-- test: index lookup
+CREATE INDEX test_full_name_idx ON test (full_name);
+INSERT INTO test (first, last) VALUES ('John', 'Doe'), ('Jane', 'Doe');
+SELECT first FROM test WHERE full_name = 'Jane Doe';
+/* result:
+{
+  first: "Jane"
+}
+*/
+
+-- test: unique
+CREATE UNIQUE INDEX test_full_name_idx ON test (full_name);
+INSERT INTO test (first, last) VALUES ('John', 'Doe');
+INSERT INTO test (first, last) VALUES ('John', 'Doe');
+-- error:
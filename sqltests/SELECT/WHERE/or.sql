@@ -0,0 +1,88 @@
+-- setup:
+CREATE TABLE test(a int, b int, c int);
+
+CREATE INDEX test_a ON test(a);
+
+CREATE INDEX test_b ON test(b);
+
+INSERT INTO
+    test (a, b, c)
+VALUES
+    (1, 1, 1),
+    (2, 2, 2),
+    (3, 1, 3),
+    (4, 4, 4),
+    (5, 5, 5);
+
+-- test: distinct documents
+SELECT * FROM test WHERE a = 1 OR b = 1 OR a = 4;
+/* result:
+{
+    "a": 1,
+    "b": 1,
+    "c": 1
+}
+{
+    "a": 3,
+    "b": 1,
+    "c": 3
+}
+{
+    "a": 4,
+    "b": 4,
+    "c": 4
+}
+*/
+
+-- test: remaining filters
+SELECT * FROM test WHERE (a = 1 OR b = 1) AND c > 1;
+/* result:
+{
+    "a": 3,
+    "b": 1,
+    "c": 3
+}
+*/
+
+-- test: order by
+SELECT a FROM test WHERE a > 3 OR b = 1 ORDER BY a DESC;
+/* result:
+{
+    "a": 5
+}
+{
+    "a": 4
+}
+{
+    "a": 3
+}
+{
+    "a": 1
+}
+*/
+
+-- test: update
+UPDATE test SET c = 10 WHERE a = 2 OR b = 5;
+SELECT a, c FROM test WHERE c = 10;
+/* result:
+{
+    "a": 2,
+    "c": 10
+}
+{
+    "a": 5,
+    "c": 10
+}
+*/
+
+-- test: delete
+DELETE FROM test WHERE a = 2 OR b = 1;
+SELECT a FROM test;
+/* result:
+{
+    "a": 4
+}
+{
+    "a": 5
+}
+*/
@@ -18,3 +18,10 @@ SELECT a % 2 FROM test GROUP BY a % 2
 {"a % 2": 0}
 {"a % 2": 1}
 */
+
+-- test: GROUP BY with aggregators
+SELECT a % 2, COUNT(*), SUM(a), MIN(a) FROM test GROUP BY a % 2
+/* result:
+{"a % 2": 0, "COUNT(*)": 2, "SUM(a)": 6, "MIN(a)": 2}
+{"a % 2": 1, "COUNT(*)": 3, "SUM(a)": 9, "MIN(a)": 1}
+*/
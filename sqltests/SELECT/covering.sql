@@ -0,0 +1,95 @@
+-- setup:
+CREATE TABLE test(a int, b double, d int, PRIMARY KEY (d));
+
+CREATE INDEX test_a ON test(a);
+
+CREATE INDEX test_b_c ON test(b, c);
+
+INSERT INTO test (a, b, c, d) VALUES (1, 1.5, 'foo', 1);
+INSERT INTO test (a, b, c, d) VALUES (2, 2.5, [1, 2], 2);
+INSERT INTO test (a, b, c, d) VALUES (3, 2.5, {"e": 1}, 3);
+INSERT INTO test (a, b, c, d) VALUES (null, 4.5, null, 4);
+INSERT INTO test (b, d) VALUES (5.5, 5);
+
+-- test: projection
+SELECT a, pk() FROM test WHERE a >= 2;
+/* result:
+{
+    "a": 2,
+    "pk()": [2]
+}
+{
+    "a": 3,
+    "pk()": [3]
+}
+*/
+
+-- test: types
+SELECT b, c, typeof(c) AS t FROM test WHERE b = 2.5;
+/* result:
+{
+    "b": 2.5,
+    "c": [1.0, 2.0],
+    "t": "array"
+}
+{
+    "b": 2.5,
+    "c": {"e": 1.0},
+    "t": "document"
+}
+*/
+
+-- test: nested path
+SELECT c.e FROM test WHERE b = 2.5 AND c.e = 1;
+/* result:
+{
+    "c.e": 1.0
+}
+*/
+
+-- test: null and missing fields
+SELECT b, c FROM test WHERE b > 4;
+/* result:
+{
+    "b": 4.5,
+    "c": null
+}
+{
+    "b": 5.5,
+    "c": null
+}
+*/
+
+-- test: count
+SELECT COUNT(*), COUNT(a), MAX(a) FROM test WHERE a > 0;
+/* result:
+{
+    "COUNT(*)": 3,
+    "COUNT(a)": 3,
+    "MAX(a)": 3
+}
+*/
+
+-- test: group by
+SELECT b, COUNT(*) FROM test WHERE b < 3 GROUP BY b;
+/* result:
+{
+    "b": 1.5,
+    "COUNT(*)": 1
+}
+{
+    "b": 2.5,
+    "COUNT(*)": 2
+}
+*/
+
+-- test: order by
+SELECT a FROM test ORDER BY a DESC LIMIT 2;
+/* result:
+{
+    "a": 3
+}
+{
+    "a": 2
+}
+*/
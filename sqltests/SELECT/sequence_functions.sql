@@ -0,0 +1,77 @@
+-- setup:
+CREATE TABLE test (a INT);
+CREATE SEQUENCE seq;
+CREATE SEQUENCE seq2 START WITH 10;
+
+-- test: currval
+BEGIN;
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq), (NEXT VALUE FOR seq);
+SELECT currval('seq') AS c;
+/* result:
+{
+  "c": 2
+}
+*/
+
+-- test: currval not yet defined
+SELECT currval('seq');
+-- error:
+
+-- test: currval is scoped to the transaction
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+SELECT currval('seq');
+-- error:
+
+-- test: currval unknown sequence
+BEGIN;
+SELECT currval('foo');
+-- error:
+
+-- test: lastval
+BEGIN;
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq);
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq2);
+SELECT lastval() AS l, currval('seq') AS c;
+/* result:
+{
+  "l": 10,
+  "c": 1
+}
+*/
+
+-- test: lastval not yet defined
+SELECT lastval();
+-- error:
+
+-- test: setval
+SELECT setval('seq', 10) AS s;
+INSERT INTO test (a) VALUES (NEXT VALUE FOR seq), (NEXT VALUE FOR seq);
+SELECT a FROM test;
+/* result:
+{
+  "a": 11
+}
+{
+  "a": 12
+}
+*/
+
+-- test: setval sets currval
+BEGIN;
+SELECT setval('seq', 10);
+SELECT currval('seq') AS c, lastval() AS l;
+/* result:
+{
+  "c": 10,
+  "l": 10
+}
+*/
+
+-- test: setval out of bounds
+SELECT setval('seq', 0);
+-- error:
+
+-- test: setval in a read-only transaction
+BEGIN READ ONLY;
+SELECT setval('seq', 10);
+-- error:
@@ -0,0 +1,166 @@
+-- setup:
+CREATE TABLE foo (
+    a INT PRIMARY KEY,
+    b TEXT NOT NULL DEFAULT "x",
+    c.d DOUBLE,
+    e INT UNIQUE,
+    f INT GENERATED ALWAYS AS (a + 1)
+) WITH TTL ON a;
+CREATE TABLE bar;
+CREATE INDEX foo_b_c ON foo (b, c.d);
+CREATE SEQUENCE seq INCREMENT BY 2 CACHE 10;
+
+-- test: __genji_tables
+SELECT name, read_only, primary_key, ttl FROM __genji_tables;
+/* result:
+{
+  "name": "bar",
+  "read_only": false,
+  "primary_key": null,
+  "ttl": null
+}
+{
+  "name": "foo",
+  "read_only": false,
+  "primary_key": ["a"],
+  "ttl": "a"
+}
+*/
+
+-- test: __genji_tables sql
+SELECT sql FROM __genji_tables WHERE name = "bar";
+/* result:
+{
+  "sql": "CREATE TABLE bar"
+}
+*/
+
+-- test: __genji_fields
+SELECT * FROM __genji_fields;
+/* result:
+{
+  "table_name": "foo",
+  "position": 1,
+  "path": "a",
+  "type": "integer",
+  "not_null": false,
+  "primary_key": true
+}
+{
+  "table_name": "foo",
+  "position": 2,
+  "path": "b",
+  "type": "text",
+  "not_null": true,
+  "primary_key": false,
+  "default_expr": "\"x\""
+}
+{
+  "table_name": "foo",
+  "position": 3,
+  "path": "c.d",
+  "type": "double",
+  "not_null": false,
+  "primary_key": false
+}
+{
+  "table_name": "foo",
+  "position": 4,
+  "path": "e",
+  "type": "integer",
+  "not_null": false,
+  "primary_key": false
+}
+{
+  "table_name": "foo",
+  "position": 5,
+  "path": "f",
+  "type": "integer",
+  "not_null": false,
+  "primary_key": false,
+  "generated_expr": "a + 1"
+}
+*/
+
+-- test: __genji_indexes
+SELECT name, table_name, paths, is_unique, sql FROM __genji_indexes;
+/* result:
+{
+  "name": "foo_b_c",
+  "table_name": "foo",
+  "paths": ["b", "c.d"],
+  "is_unique": false,
+  "sql": "CREATE INDEX foo_b_c ON foo (b, c.d)"
+}
+{
+  "name": "foo_e_idx",
+  "table_name": "foo",
+  "paths": ["e"],
+  "is_unique": true,
+  "sql": "CREATE UNIQUE INDEX foo_e_idx ON foo (e)"
+}
+*/
+
+-- test: __genji_sequences
+SELECT name, increment_by, min_value, start_value, cache_size, is_cyclic, owner FROM __genji_sequences;
+/* result:
+{
+  "name": "bar_seq",
+  "increment_by": 1,
+  "min_value": 1,
+  "start_value": 1,
+  "cache_size": 64,
+  "is_cyclic": false,
+  "owner": "bar"
+}
+{
+  "name": "seq",
+  "increment_by": 2,
+  "min_value": 1,
+  "start_value": 1,
+  "cache_size": 10,
+  "is_cyclic": false,
+  "owner": null
+}
+*/
+
+-- test: WHERE and ORDER BY
+SELECT path FROM __genji_fields WHERE table_name = "foo" AND not_null = false ORDER BY path DESC LIMIT 2;
+/* result:
+{
+  "path": "f"
+}
+{
+  "path": "e"
+}
+*/
+
+-- test: reflects schema changes
+ALTER TABLE foo ADD FIELD g BOOL;
+SELECT path, type FROM __genji_fields WHERE position = 6;
+/* result:
+{
+  "path": "g",
+  "type": "bool"
+}
+*/
+
+-- test: INSERT
+INSERT INTO __genji_tables (name) VALUES ("baz");
+-- error:
+
+-- test: UPDATE
+UPDATE __genji_tables SET name = "baz";
+-- error:
+
+-- test: DELETE
+DELETE FROM __genji_tables;
+-- error:
+
+-- test: CREATE INDEX
+CREATE INDEX idx ON __genji_tables (name);
+-- error:
+
+-- test: name is reserved
+CREATE TABLE __genji_fields;
+-- error:
@@ -0,0 +1,110 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int);
+CREATE INDEX test_b ON test(b);
+
+-- test: rollback to savepoint
+BEGIN;
+INSERT INTO test (a, b) VALUES (1, 10);
+SAVEPOINT sp;
+INSERT INTO test (a, b) VALUES (2, 20);
+UPDATE test SET b = 11 WHERE a = 1;
+ROLLBACK TO SAVEPOINT sp;
+INSERT INTO test (a, b) VALUES (3, 30);
+COMMIT;
+SELECT * FROM test WHERE b > 0;
+/* result:
+{
+  "a": 1,
+  "b": 10
+}
+{
+  "a": 3,
+  "b": 30
+}
+*/
+
+-- test: rollback to savepoint twice
+BEGIN;
+SAVEPOINT sp;
+INSERT INTO test (a, b) VALUES (1, 10);
+ROLLBACK TO sp;
+INSERT INTO test (a, b) VALUES (1, 11);
+ROLLBACK TO sp;
+INSERT INTO test (a, b) VALUES (1, 12);
+COMMIT;
+SELECT * FROM test;
+/* result:
+{
+  "a": 1,
+  "b": 12
+}
+*/
+
+-- test: nested savepoints
+BEGIN;
+SAVEPOINT a;
+INSERT INTO test (a, b) VALUES (1, 10);
+SAVEPOINT b;
+INSERT INTO test (a, b) VALUES (2, 20);
+SAVEPOINT c;
+INSERT INTO test (a, b) VALUES (3, 30);
+ROLLBACK TO SAVEPOINT b;
+COMMIT;
+SELECT * FROM test;
+/* result:
+{
+  "a": 1,
+  "b": 10
+}
+*/
+
+-- test: release
+BEGIN;
+SAVEPOINT a;
+INSERT INTO test (a, b) VALUES (1, 10);
+SAVEPOINT b;
+INSERT INTO test (a, b) VALUES (2, 20);
+RELEASE SAVEPOINT b;
+ROLLBACK TO SAVEPOINT a;
+INSERT INTO test (a, b) VALUES (3, 30);
+COMMIT;
+SELECT * FROM test;
+/* result:
+{
+  "a": 3,
+  "b": 30
+}
+*/
+
+-- test: released savepoint
+BEGIN;
+SAVEPOINT a;
+SAVEPOINT b;
+RELEASE a;
+ROLLBACK TO b;
+-- error:
+
+-- test: unknown savepoint
+BEGIN;
+ROLLBACK TO SAVEPOINT sp;
+-- error:
+
+-- test: savepoint with no active transaction
+SAVEPOINT sp;
+-- error:
+
+-- test: rollback to savepoint after create table
+BEGIN;
+SAVEPOINT sp;
+CREATE TABLE foo;
+INSERT INTO foo (a) VALUES (1);
+ROLLBACK TO sp;
+CREATE TABLE foo(b int);
+INSERT INTO foo (b) VALUES (2);
+COMMIT;
+SELECT * FROM foo;
+/* result:
+{
+  "b": 2
+}
+*/
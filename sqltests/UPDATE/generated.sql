@@ -0,0 +1,69 @@
+-- setup:
+CREATE TABLE test (
+    a INT PRIMARY KEY,
+    b INT,
+    c INT GENERATED ALWAYS AS (b * 10)
+);
+CREATE INDEX test_c_idx ON test (c);
+INSERT INTO test (a, b) VALUES (1, 1), (2, 2);
+
+-- test: recomputed
+UPDATE test SET b = 3 WHERE a = 2;
+SELECT * FROM test;
+/* result:
+{
+  a: 1,
+  b: 1,
+  c: 10
+}
+{
+  a: 2,
+  b: 3,
+  c: 30
+}
+*/
+
+-- test: unset dependency
+UPDATE test UNSET b;
+SELECT * FROM test WHERE a = 1;
+/* result:
+{
+  a: 1,
+  c: null
+}
+*/
+
+-- test: index is updated
+UPDATE test SET b = 3 WHERE a = 2;
+SELECT a FROM test WHERE c = 30;
+/* result:
+{
+  a: 2
+}
+*/
+
+-- test: old index entry is removed
+UPDATE test SET b = 3 WHERE a = 2;
+SELECT a FROM test WHERE c = 20;
+/* result:
+*/
+
+-- test: set generated field
+UPDATE test SET c = 5;
+-- error: cannot update generated field "c"
+
+-- test: unset generated field
+UPDATE test UNSET c;
+-- error: cannot update generated field "c"
+
+-- test: generated primary key
+CREATE TABLE foo (a INT, b INT GENERATED ALWAYS AS (a + 1) PRIMARY KEY);
+INSERT INTO foo (a) VALUES (1), (10);
+UPDATE foo SET a = 2 WHERE a = 1;
+SELECT * FROM foo WHERE b = 3;
+/* result:
+{
+  a: 2,
+  b: 3
+}
+*/
@@ -0,0 +1,95 @@
+-- setup:
+CREATE TABLE test(a int, b int, c int, d int, PRIMARY KEY (d));
+
+CREATE INDEX test_a ON test(a);
+
+CREATE INDEX test_b_c ON test(b, c);
+
+INSERT INTO
+    test (a, b, c, d)
+VALUES
+    (1, 1, 1, 1),
+    (2, 2, 2, 2),
+    (3, 3, 3, 3),
+    (4, 4, 4, 4),
+    (5, 5, 5, 5);
+
+-- test: indexed path
+EXPLAIN SELECT a FROM test WHERE a = 1;
+/* result:
+{
+    "plan": 'index.CoveringScan("test_a", [{"min": [1], "exact": true}]) | docs.Project(a)'
+}
+*/
+
+-- test: pk
+EXPLAIN SELECT pk(), a + 1 FROM test WHERE a > 1;
+/* result:
+{
+    "plan": 'index.CoveringScan("test_a", [{"min": [1], "exclusive": true}]) | docs.Project(pk(), a + 1)'
+}
+*/
+
+-- test: composite index
+EXPLAIN SELECT c FROM test WHERE b = 1 AND c > 0;
+/* result:
+{
+    "plan": 'index.CoveringScan("test_b_c", [{"min": [1, 0], "exclusive": true}]) | docs.Project(c)'
+}
+*/
+
+-- test: remaining filter
+EXPLAIN SELECT b FROM test WHERE b = 1 AND (c = 1 OR c = 2);
+/* result:
+{
+    "plan": 'index.CoveringScan("test_b_c", [{"min": [1], "exact": true}]) | docs.Filter((c = 1 OR c = 2)) | docs.Project(b)'
+}
+*/
+
+-- test: count
+EXPLAIN SELECT COUNT(*) FROM test WHERE a > 2;
+/* result:
+{
+    "plan": 'index.CoveringScan("test_a", [{"min": [2], "exclusive": true}]) | docs.GroupAggregate(NULL, COUNT(*)) | docs.Project(COUNT(*))'
+}
+*/
+
+-- test: order by
+EXPLAIN SELECT a FROM test ORDER BY a DESC;
+/* result:
+{
+    "plan": 'index.CoveringScanReverse("test_a") | docs.Project(a)'
+}
+*/
+
+-- test: non indexed path
+EXPLAIN SELECT a, c FROM test WHERE a = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_a", [{"min": [1], "exact": true}]) | docs.Project(a, c)'
+}
+*/
+
+-- test: non indexed filter
+EXPLAIN SELECT a FROM test WHERE a = 1 AND c = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_a", [{"min": [1], "exact": true}]) | docs.Filter(c = 1) | docs.Project(a)'
+}
+*/
+
+-- test: wildcard
+EXPLAIN SELECT * FROM test WHERE a = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_a", [{"min": [1], "exact": true}])'
+}
+*/
+
+-- test: update
+EXPLAIN UPDATE test SET c = 10 WHERE a = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_a", [{"min": [1], "exact": true}]) | paths.Set(c, 10) | table.Validate("test") | index.Delete("test_a") | index.Delete("test_b_c") | table.Replace("test") | index.Insert("test_a") | index.Insert("test_b_c")'
+}
+*/
@@ -0,0 +1,53 @@
+-- setup:
+CREATE TABLE test(a int, b int, c int);
+
+CREATE INDEX test_a ON test(a);
+
+INSERT INTO
+    test (a, b, c)
+VALUES
+    (1, 1, 1),
+    (2, 2, 2),
+    (3, 3, 3),
+    (4, 4, 4),
+    (5, 5, 5);
+
+-- test: hash aggregate
+EXPLAIN SELECT b, COUNT(*) FROM test GROUP BY b;
+/* result:
+{
+    "plan": 'table.Scan("test") | docs.HashAggregate(b, COUNT(*)) | docs.Project(b, COUNT(*))'
+}
+*/
+
+-- test: ascending order
+EXPLAIN SELECT b, COUNT(*) FROM test GROUP BY b ORDER BY b;
+/* result:
+{
+    "plan": 'table.Scan("test") | docs.HashAggregate(b, COUNT(*)) | docs.Project(b, COUNT(*))'
+}
+*/
+
+-- test: descending order
+EXPLAIN SELECT b, COUNT(*) FROM test GROUP BY b ORDER BY b DESC;
+/* result:
+{
+    "plan": 'table.Scan("test") | docs.TempTreeSortReverse(b) | docs.GroupAggregate(b, COUNT(*)) | docs.Project(b, COUNT(*))'
+}
+*/
+
+-- test: sorted by index
+EXPLAIN SELECT a, COUNT(*) FROM test GROUP BY a;
+/* result:
+{
+    "plan": 'index.CoveringScan("test_a") | docs.GroupAggregate(a, COUNT(*)) | docs.Project(a, COUNT(*))'
+}
+*/
+
+-- test: no group by
+EXPLAIN SELECT COUNT(*) FROM test;
+/* result:
+{
+    "plan": 'table.Scan("test") | docs.GroupAggregate(NULL, COUNT(*)) | docs.Project(COUNT(*))'
+}
+*/
@@ -0,0 +1,71 @@
+-- setup:
+CREATE TABLE test(a int, b int, c int, d int, PRIMARY KEY (d));
+
+CREATE INDEX test_a ON test(a);
+
+CREATE INDEX test_b ON test(b);
+
+INSERT INTO
+    test (a, b, c, d)
+VALUES
+    (1, 1, 1, 1),
+    (2, 2, 2, 2),
+    (3, 3, 3, 3),
+    (4, 4, 4, 4),
+    (5, 5, 5, 5);
+
+-- test: two indexes
+EXPLAIN SELECT * FROM test WHERE a = 1 OR b = 2;
+/* result:
+{
+    "plan": 'union.Keys("test", index.Scan("test_a", [{"min": [1], "exact": true}]), index.Scan("test_b", [{"min": [2], "exact": true}]))'
+}
+*/
+
+-- test: index and primary key
+EXPLAIN SELECT * FROM test WHERE a > 1 OR d = 2;
+/* result:
+{
+    "plan": 'union.Keys("test", index.Scan("test_a", [{"min": [1], "exclusive": true}]), table.Scan("test", [{"min": [2], "exact": true}]))'
+}
+*/
+
+-- test: three operands
+EXPLAIN SELECT * FROM test WHERE a = 1 OR b = 2 OR a = 3;
+/* result:
+{
+    "plan": 'union.Keys("test", index.Scan("test_a", [{"min": [1], "exact": true}]), index.Scan("test_b", [{"min": [2], "exact": true}]), index.Scan("test_a", [{"min": [3], "exact": true}]))'
+}
+*/
+
+-- test: partially covered operand
+EXPLAIN SELECT * FROM test WHERE (a = 1 AND c = 1) OR b = 2;
+/* result:
+{
+    "plan": 'union.Keys("test", index.Scan("test_a", [{"min": [1], "exact": true}]), index.Scan("test_b", [{"min": [2], "exact": true}])) | docs.Filter((a = 1 AND c = 1) OR b = 2)'
+}
+*/
+
+-- test: with other filters
+EXPLAIN SELECT * FROM test WHERE (a = 1 OR b = 2) AND c > 0;
+/* result:
+{
+    "plan": 'union.Keys("test", index.Scan("test_a", [{"min": [1], "exact": true}]), index.Scan("test_b", [{"min": [2], "exact": true}])) | docs.Filter(c > 0)'
+}
+*/
+
+-- test: non indexed operand
+EXPLAIN SELECT * FROM test WHERE a = 1 OR c = 2;
+/* result:
+{
+    "plan": 'table.Scan("test") | docs.Filter(a = 1 OR c = 2)'
+}
+*/
+
+-- test: AND is preferred
+EXPLAIN SELECT * FROM test WHERE (a = 1 OR b = 2) AND d = 1;
+/* result:
+{
+    "plan": 'table.Scan("test", [{"min": [1], "exact": true}]) | docs.Filter((a = 1 OR b = 2))'
+}
+*/
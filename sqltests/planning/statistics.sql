@@ -0,0 +1,101 @@
+-- setup:
+CREATE TABLE test(a int, b int, c int);
+
+CREATE INDEX test_a ON test(a);
+
+CREATE INDEX test_b ON test(b);
+
+INSERT INTO
+    test (a, b, c)
+VALUES
+    (1, 1, 1),
+    (2, 1, 2),
+    (3, 1, 3),
+    (4, 1, 4),
+    (5, 1, 5),
+    (6, 1, 6),
+    (7, 1, 7),
+    (8, 1, 8),
+    (9, 1, 9),
+    (10, 1, 10),
+    (11, 1, 11),
+    (12, 1, 12),
+    (13, 1, 13),
+    (14, 1, 14),
+    (15, 1, 15),
+    (16, 1, 16),
+    (17, 1, 17),
+    (18, 1, 18),
+    (19, 1, 19),
+    (20, 2, 20);
+
+-- test: without statistics, = is preferred
+EXPLAIN SELECT * FROM test WHERE a > 15 AND b = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_b", [{"min": [1], "exact": true}]) | docs.Filter(a > 15)'
+}
+*/
+
+-- test: selective range
+ANALYZE;
+EXPLAIN SELECT * FROM test WHERE a > 15 AND b = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_a", [{"min": [15], "exclusive": true}]) | docs.Filter(b = 1)'
+}
+*/
+
+-- test: selective value
+ANALYZE;
+EXPLAIN SELECT * FROM test WHERE b = 2;
+/* result:
+{
+    "plan": 'index.Scan("test_b", [{"min": [2], "exact": true}])'
+}
+*/
+
+-- test: unselective index
+ANALYZE;
+EXPLAIN SELECT * FROM test WHERE b = 1;
+/* result:
+{
+    "plan": 'table.Scan("test") | docs.Filter(b = 1)'
+}
+*/
+
+-- test: equality on both indexes
+ANALYZE;
+EXPLAIN SELECT * FROM test WHERE a = 10 AND b = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_a", [{"min": [10], "exact": true}]) | docs.Filter(b = 1)'
+}
+*/
+
+-- test: order by
+ANALYZE;
+EXPLAIN SELECT * FROM test WHERE b = 1 ORDER BY a;
+/* result:
+{
+    "plan": 'index.Scan("test_a") | docs.Filter(b = 1)'
+}
+*/
+
+-- test: selective union
+ANALYZE;
+EXPLAIN SELECT * FROM test WHERE a = 1 OR b = 2;
+/* result:
+{
+    "plan": 'union.Keys("test", index.Scan("test_a", [{"min": [1], "exact": true}]), index.Scan("test_b", [{"min": [2], "exact": true}]))'
+}
+*/
+
+-- test: unselective union
+ANALYZE;
+EXPLAIN SELECT * FROM test WHERE a = 1 OR b = 1;
+/* result:
+{
+    "plan": 'table.Scan("test") | docs.Filter(a = 1 OR b = 1)'
+}
+*/
@@ -0,0 +1,21 @@
+-- setup:
+CREATE TABLE test(a int primary key, b int);
+CREATE INDEX test_b ON test(b);
+CREATE VIEW v AS SELECT a, b FROM test WHERE b = 1;
+CREATE MATERIALIZED VIEW mv AS SELECT a, b FROM test;
+
+-- test: view query is optimized
+EXPLAIN SELECT a FROM v WHERE a > 2;
+/* result:
+{
+  "plan": 'concat(index.Scan("test_b", [{"min": [1], "exact": true}]) | docs.Project(a, b)) | docs.Filter(a > 2) | docs.Project(a)'
+}
+*/
+
+-- test: materialized view
+EXPLAIN SELECT a FROM mv WHERE a > 2;
+/* result:
+{
+  "plan": 'table.Scan("mv") | docs.Filter(a > 2) | docs.Project(a)'
+}
+*/
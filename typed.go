@@ -0,0 +1,172 @@
+package genji
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/internal/stringutil"
+	"github.com/genjidb/genji/types"
+)
+
+// A Querier runs queries, either directly against the database or within a transaction.
+// It is implemented by DB and Tx.
+type Querier interface {
+	Query(q string, args ...interface{}) (*Result, error)
+	QueryDocument(q string, args ...interface{}) (types.Document, error)
+	Exec(q string, args ...interface{}) error
+}
+
+var (
+	_ Querier = (*DB)(nil)
+	_ Querier = (*Tx)(nil)
+)
+
+// QueryAll runs the query and returns all the documents it selects, scanned into values of type T.
+// T must be a struct or a pointer to a struct, whose fields are decoded as described by document.StructScan.
+func QueryAll[T any](qr Querier, q string, args ...interface{}) ([]T, error) {
+	rows, err := QueryRows[T](qr, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var all []T
+	err = rows.Iterate(func(v T) error {
+		all = append(all, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
+// QueryOne runs the query and returns the first document it selects, scanned into a value of type T.
+// T must be a struct or a pointer to a struct, whose fields are decoded as described by document.StructScan.
+// If the query selects no document, QueryOne returns errs.ErrDocumentNotFound.
+func QueryOne[T any](qr Querier, q string, args ...interface{}) (T, error) {
+	var v T
+
+	err := checkStructType[T]()
+	if err != nil {
+		return v, err
+	}
+
+	d, err := qr.QueryDocument(q, args...)
+	if err != nil {
+		return v, err
+	}
+
+	return scanStruct[T](d)
+}
+
+// Rows is the result of a query whose documents are scanned into values of type T.
+// It must always be closed after usage.
+type Rows[T any] struct {
+	res *Result
+}
+
+// QueryRows runs the query and returns its result, whose documents are scanned into values of type T.
+// T must be a struct or a pointer to a struct, whose fields are decoded as described by document.StructScan.
+func QueryRows[T any](qr Querier, q string, args ...interface{}) (*Rows[T], error) {
+	err := checkStructType[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := qr.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Rows[T]{res: res}, nil
+}
+
+// Iterate scans every document of the result into a new value of type T and calls fn with it.
+// If fn returns an error, the iteration stops and the error is returned.
+func (r *Rows[T]) Iterate(fn func(v T) error) error {
+	return r.res.Iterate(func(d types.Document) error {
+		v, err := scanStruct[T](d)
+		if err != nil {
+			return err
+		}
+
+		return fn(v)
+	})
+}
+
+// Fields returns the fields of the documents of the result.
+func (r *Rows[T]) Fields() []string {
+	return r.res.Fields()
+}
+
+// Close the result.
+func (r *Rows[T]) Close() error {
+	return r.res.Close()
+}
+
+// InsertStruct inserts the given values in the table, each value being converted
+// to a document as described by document.NewFromStruct.
+// T must be a struct or a pointer to a struct.
+func InsertStruct[T any](qr Querier, tableName string, values ...T) error {
+	return insertStruct(qr, tableName, "", values)
+}
+
+// UpsertStruct inserts the given values in the table, like InsertStruct,
+// but replaces the documents that have the same primary key.
+func UpsertStruct[T any](qr Querier, tableName string, values ...T) error {
+	return insertStruct(qr, tableName, " ON CONFLICT DO REPLACE", values)
+}
+
+func insertStruct[T any](qr Querier, tableName, onConflict string, values []T) error {
+	err := checkStructType[T]()
+	if err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, len(values))
+	for i := range values {
+		args[i] = values[i]
+	}
+
+	q := "INSERT INTO " + stringutil.NormalizeIdentifier(tableName, '`') +
+		" VALUES ?" + strings.Repeat(", ?", len(values)-1) + onConflict
+
+	return qr.Exec(q, args...)
+}
+
+// scanStruct scans the document into a new value of type T.
+func scanStruct[T any](d types.Document) (T, error) {
+	var v T
+
+	var target interface{} = &v
+	ref := reflect.ValueOf(&v).Elem()
+	if ref.Kind() == reflect.Ptr {
+		ref.Set(reflect.New(ref.Type().Elem()))
+		target = v
+	}
+
+	err := document.StructScan(d, target)
+	return v, err
+}
+
+// checkStructType returns an error if T is neither a struct nor a pointer to a struct.
+func checkStructType[T any]() error {
+	tp := reflect.TypeOf((*T)(nil)).Elem()
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	if tp.Kind() != reflect.Struct {
+		return fmt.Errorf("expected struct or pointer to struct, got %s", tp)
+	}
+
+	return nil
+}
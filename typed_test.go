@@ -0,0 +1,95 @@
+package genji_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/genjidb/genji"
+	errs "github.com/genjidb/genji/errors"
+	"github.com/genjidb/genji/internal/testutil/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedUser struct {
+	ID    int64 `genji:"id"`
+	Name  string
+	Email string `genji:"-"`
+}
+
+func TestTypedHelpers(t *testing.T) {
+	db, err := genji.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec("CREATE TABLE users(id INT PRIMARY KEY, name TEXT)")
+	assert.NoError(t, err)
+
+	err = genji.InsertStruct(db, "users", typedUser{ID: 1, Name: "foo", Email: "foo@example.com"}, typedUser{ID: 2, Name: "bar"})
+	assert.NoError(t, err)
+
+	t.Run("InsertStruct", func(t *testing.T) {
+		// duplicate primary key
+		err := genji.InsertStruct(db, "users", &typedUser{ID: 1, Name: "baz"})
+		assert.Error(t, err)
+
+		err = genji.InsertStruct(db, "users", 1)
+		assert.Error(t, err)
+
+		// nothing to insert
+		err = genji.InsertStruct[typedUser](db, "users")
+		assert.NoError(t, err)
+	})
+
+	t.Run("QueryAll", func(t *testing.T) {
+		users, err := genji.QueryAll[typedUser](db, "SELECT * FROM users")
+		assert.NoError(t, err)
+		require.Equal(t, []typedUser{{ID: 1, Name: "foo"}, {ID: 2, Name: "bar"}}, users)
+
+		ptrs, err := genji.QueryAll[*typedUser](db, "SELECT * FROM users WHERE id > ?", 1)
+		assert.NoError(t, err)
+		require.Equal(t, []*typedUser{{ID: 2, Name: "bar"}}, ptrs)
+
+		users, err = genji.QueryAll[typedUser](db, "SELECT * FROM users WHERE id > 10")
+		assert.NoError(t, err)
+		require.Empty(t, users)
+
+		_, err = genji.QueryAll[int](db, "SELECT * FROM users")
+		assert.Error(t, err)
+	})
+
+	t.Run("QueryOne", func(t *testing.T) {
+		u, err := genji.QueryOne[typedUser](db, "SELECT * FROM users WHERE id = ?", 2)
+		assert.NoError(t, err)
+		require.Equal(t, typedUser{ID: 2, Name: "bar"}, u)
+
+		_, err = genji.QueryOne[typedUser](db, "SELECT * FROM users WHERE id = 10")
+		require.True(t, errors.Is(err, errs.ErrDocumentNotFound))
+	})
+
+	t.Run("QueryRows", func(t *testing.T) {
+		rows, err := genji.QueryRows[typedUser](db, "SELECT name FROM users")
+		assert.NoError(t, err)
+		defer rows.Close()
+
+		require.Equal(t, []string{"name"}, rows.Fields())
+
+		var names []string
+		err = rows.Iterate(func(u typedUser) error {
+			names = append(names, u.Name)
+			return nil
+		})
+		assert.NoError(t, err)
+		require.Equal(t, []string{"foo", "bar"}, names)
+	})
+
+	t.Run("UpsertStruct", func(t *testing.T) {
+		err := db.Update(func(tx *genji.Tx) error {
+			return genji.UpsertStruct(tx, "users", typedUser{ID: 1, Name: "baz"}, typedUser{ID: 3, Name: "qux"})
+		})
+		assert.NoError(t, err)
+
+		users, err := genji.QueryAll[typedUser](db, "SELECT * FROM users")
+		assert.NoError(t, err)
+		require.Equal(t, []typedUser{{ID: 1, Name: "baz"}, {ID: 2, Name: "bar"}, {ID: 3, Name: "qux"}}, users)
+	})
+}
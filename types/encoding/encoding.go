@@ -154,9 +154,13 @@ func appendDocument(buf []byte, d types.Document) ([]byte, error) {
 		return nil, err
 	}
 
-	// replace the last delimiter with the end marker
+	// replace the last delimiter with the end marker.
+	// empty documents are only made of the end marker, to
+	// allow decoders to skip them when nested.
 	if len(buf) != l {
 		buf[len(buf)-1] = DocumentEnd
+	} else {
+		buf = append(buf, DocumentEnd)
 	}
 
 	return buf, nil
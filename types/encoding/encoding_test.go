@@ -78,6 +78,15 @@ func TestEncodeDecode(t *testing.T) {
 			`{"age": 10, "name": "john", "address": {"city": "Ajaccio", "country": "France"}, "array": [true, -40, -3.14, 3, "YmxvYg==", "hello", {"city": "Ajaccio", "country": "France"}, [11]]}`,
 			false,
 		},
+		{
+			"Nested empty types.Document",
+			document.NewFieldBuffer().
+				Add("a", types.NewDocumentValue(document.NewFieldBuffer())).
+				Add("b", types.NewArrayValue(document.NewValueBuffer())).
+				Add("c", types.NewIntegerValue(10)),
+			`{"a": {}, "b": [], "c": 10}`,
+			false,
+		},
 	}
 
 	var buf bytes.Buffer